@@ -0,0 +1,314 @@
+package slice
+
+import (
+	"fmt"
+	"math"
+)
+
+// ArcDirection is the direction an arc travels around its center
+type ArcDirection int
+
+const (
+	// ArcClockwise arcs are emitted as G2
+	ArcClockwise ArcDirection = iota
+	// ArcCounterClockwise arcs are emitted as G3
+	ArcCounterClockwise
+)
+
+// Arc is a circular segment travelling from Start to End around Center
+type Arc struct {
+	Start     *Point
+	End       *Point
+	Center    *Point
+	Radius    float64
+	Direction ArcDirection
+}
+
+// NewArc will construct an Arc. The radius is taken from the start point
+func NewArc(start *Point, end *Point, center *Point, direction ArcDirection) *Arc {
+	arc := new(Arc)
+	arc.Start = start
+	arc.End = end
+	arc.Center = center
+	arc.Radius = center.DistanceTo(start)
+	arc.Direction = direction
+	return arc
+}
+
+// Describe will return a string description of the Arc
+func (a *Arc) Describe() string {
+	return fmt.Sprintf("ARC(Start: %s, End: %s, Center: %s, Radius: %f, CCW: %t)",
+		a.Start.Describe(), a.End.Describe(), a.Center.Describe(), a.Radius, a.IsCounterClockwise())
+}
+
+// IsCounterClockwise will tell if the arc travels counter clockwise (G3)
+func (a *Arc) IsCounterClockwise() bool {
+	return a.Direction == ArcCounterClockwise
+}
+
+// Angle will return the angle swept by the arc, always positive
+func (a *Arc) Angle() float64 {
+	start := math.Atan2(a.Start.Y-a.Center.Y, a.Start.X-a.Center.X)
+	end := math.Atan2(a.End.Y-a.Center.Y, a.End.X-a.Center.X)
+	angle := end - start
+	if !a.IsCounterClockwise() {
+		angle = -angle
+	}
+	for angle <= 0 {
+		angle += 2 * math.Pi
+	}
+	return angle
+}
+
+// Length will return the length travelled along the arc
+func (a *Arc) Length() float64 {
+	return a.Radius * a.Angle()
+}
+
+// Reverse will swap the start and end of the arc and flip its direction
+func (a *Arc) Reverse() {
+	a.Start, a.End = a.End, a.Start
+	if a.IsCounterClockwise() {
+		a.Direction = ArcClockwise
+	} else {
+		a.Direction = ArcCounterClockwise
+	}
+}
+
+// Polyline will approximate the arc with segments that stray no further than
+// maxDeviation from the arc
+func (a *Arc) Polyline(maxDeviation float64) *Polyline {
+	pl := NewPolyline()
+	angle := a.Angle()
+	steps := 1
+	if maxDeviation > 0 && maxDeviation < a.Radius {
+		step := 2 * math.Acos(1-maxDeviation/a.Radius)
+		steps = int(math.Ceil(angle / step))
+	}
+
+	startAngle := math.Atan2(a.Start.Y-a.Center.Y, a.Start.X-a.Center.X)
+	if !a.IsCounterClockwise() {
+		angle = -angle
+	}
+	pl.MP.Points.Push(a.Start)
+	for i := 1; i < steps; i++ {
+		theta := startAngle + angle*float64(i)/float64(steps)
+		pl.MP.Points.Push(NewPoint(a.Center.X+a.Radius*math.Cos(theta), a.Center.Y+a.Radius*math.Sin(theta)))
+	}
+	pl.MP.Points.Push(a.End)
+	return pl
+}
+
+// PathSegment is a single piece of an ArcPath. Exactly one of Line or Arc is set
+type PathSegment struct {
+	Line *Line
+	Arc  *Arc
+}
+
+// IsArc will tell if the segment is an arc
+func (ps *PathSegment) IsArc() bool {
+	return ps.Arc != nil
+}
+
+// Start will return the first point of the segment
+func (ps *PathSegment) Start() *Point {
+	if ps.IsArc() {
+		return ps.Arc.Start
+	}
+	return ps.Line.A
+}
+
+// End will return the last point of the segment
+func (ps *PathSegment) End() *Point {
+	if ps.IsArc() {
+		return ps.Arc.End
+	}
+	return ps.Line.B
+}
+
+// Length will return the length of the segment
+func (ps *PathSegment) Length() float64 {
+	if ps.IsArc() {
+		return ps.Arc.Length()
+	}
+	return ps.Line.Length()
+}
+
+// ArcPath is a path mixing line and arc segments
+type ArcPath struct {
+	Segments []*PathSegment
+}
+
+// NewArcPath will construct an empty ArcPath
+func NewArcPath() *ArcPath {
+	ap := new(ArcPath)
+	ap.Segments = make([]*PathSegment, 0)
+	return ap
+}
+
+// PushLine will add a line segment
+func (ap *ArcPath) PushLine(line *Line) {
+	ap.Segments = append(ap.Segments, &PathSegment{Line: line})
+}
+
+// PushArc will add an arc segment
+func (ap *ArcPath) PushArc(arc *Arc) {
+	ap.Segments = append(ap.Segments, &PathSegment{Arc: arc})
+}
+
+// Length will return the length of all segments
+func (ap *ArcPath) Length() float64 {
+	var length float64 = 0
+	for _, seg := range ap.Segments {
+		length += seg.Length()
+	}
+	return length
+}
+
+// ArcCount will return the number of arc segments
+func (ap *ArcPath) ArcCount() int {
+	count := 0
+	for _, seg := range ap.Segments {
+		if seg.IsArc() {
+			count++
+		}
+	}
+	return count
+}
+
+// Polyline will flatten the path back into a polyline
+func (ap *ArcPath) Polyline(maxDeviation float64) *Polyline {
+	pl := NewPolyline()
+	for _, seg := range ap.Segments {
+		var points Points
+		if seg.IsArc() {
+			points = seg.Arc.Polyline(maxDeviation).MP.Points
+		} else {
+			points = Points{seg.Line.A, seg.Line.B}
+		}
+		if !pl.MP.Points.Empty() && pl.MP.Points.Last().CoincidesWith(points.First()) {
+			points = points[1:]
+		}
+		pl.MP.Points.Push(points...)
+	}
+	return pl
+}
+
+// ArcFitter holds the settings used to turn runs of points into arcs
+type ArcFitter struct {
+	Tolerance float64 // furthest a point or segment midpoint may be from the arc
+	MinPoints int     // fewest points a run may have to become an arc
+	MaxRadius float64 // runs with a larger radius are left as lines
+}
+
+// NewArcFitter will construct an ArcFitter with the supplied scaled tolerance
+func NewArcFitter(tolerance float64) *ArcFitter {
+	af := new(ArcFitter)
+	af.Tolerance = tolerance
+	af.MinPoints = 4
+	af.MaxRadius = Scale(1000)
+	return af
+}
+
+// CircleCenter will find the center of the circle passing through three points.
+// nil is returned when the points are collinear
+func CircleCenter(a *Point, b *Point, c *Point) *Point {
+	// intersect the perpendicular bisectors of ab and bc
+	ab := NewLine(a, b)
+	bc := NewLine(b, c)
+	m1, n1 := ab.Midpoint(), ab.Normal()
+	m2, n2 := bc.Midpoint(), bc.Normal()
+
+	cross := n1.X*n2.Y - n1.Y*n2.X
+	if math.Abs(cross) < Epsilon {
+		return nil
+	}
+	d := m1.VectorTo(m2)
+	t := (d.X*n2.Y - d.Y*n2.X) / cross
+	return NewPoint(m1.X+n1.X*t, m1.Y+n1.Y*t)
+}
+
+// fitRun will try to fit an arc to points[start:end+1]
+func (af *ArcFitter) fitRun(points Points, start int, end int) *Arc {
+	first, last := points[start], points[end]
+	center := CircleCenter(first, points[(start+end)/2], last)
+	if center == nil {
+		return nil
+	}
+	radius := center.DistanceTo(first)
+	if radius > af.MaxRadius {
+		return nil
+	}
+
+	var swept float64 = 0
+	var turn float64 = 0
+	for i := start; i < end; i++ {
+		p1, p2 := points[i], points[i+1]
+		if math.Abs(center.DistanceTo(p2)-radius) > af.Tolerance ||
+			math.Abs(center.DistanceTo(NewLine(p1, p2).Midpoint())-radius) > af.Tolerance {
+			return nil
+		}
+
+		// every step has to turn the same way around the center
+		step := p2.CCW(center, p1)
+		if step == 0 || (turn != 0 && (step > 0) != (turn > 0)) {
+			return nil
+		}
+		turn = step
+		v1, v2 := center.VectorTo(p1), center.VectorTo(p2)
+		swept += math.Abs(math.Atan2(v1.X*v2.Y-v1.Y*v2.X, v1.X*v2.X+v1.Y*v2.Y))
+	}
+	if swept >= 2*math.Pi-Epsilon {
+		return nil
+	}
+
+	direction := ArcClockwise
+	if turn > 0 {
+		direction = ArcCounterClockwise
+	}
+	arc := NewArc(first, last, center, direction)
+	arc.Radius = radius
+	return arc
+}
+
+// Fit will replace runs of points lying on a circle with arcs. Every other segment
+// is kept as a line
+func (af *ArcFitter) Fit(points Points) *ArcPath {
+	path := NewArcPath()
+	minPoints := af.MinPoints
+	if minPoints < 3 {
+		minPoints = 3
+	}
+
+	for start := 0; start < len(points)-1; {
+		var best *Arc
+		bestEnd := start
+		for end := start + minPoints - 1; end < len(points); end++ {
+			arc := af.fitRun(points, start, end)
+			if arc == nil {
+				break
+			}
+			best, bestEnd = arc, end
+		}
+
+		if best != nil {
+			path.PushArc(best)
+			start = bestEnd
+			continue
+		}
+		path.PushLine(NewLine(points[start], points[start+1]))
+		start++
+	}
+	return path
+}
+
+// FitArcs will convert the polyline into lines and arcs that stray no further than tolerance
+func (pl *Polyline) FitArcs(tolerance float64) *ArcPath {
+	return NewArcFitter(tolerance).Fit(pl.MP.Points)
+}
+
+// FitArcs will convert the polygon into a closed path of lines and arcs that stray
+// no further than tolerance
+func (pg *Polygon) FitArcs(tolerance float64) *ArcPath {
+	return NewArcFitter(tolerance).Fit(pg.SplitAtFirstPoint().MP.Points)
+}
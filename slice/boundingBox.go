@@ -44,27 +44,31 @@ func NewBoundingBoxLines(lines ...*Line) *BoundingBox {
 	return NewBoundingBox(points...)
 }
 
-// Polygon will alter a polygon to be a bounding box
-func (bb *BoundingBox) Polygon(poly *Polygon) {
-	poly.MP.Points.Clear()
+// Polygon will return the counter clockwise polygon of the bounding box
+func (bb *BoundingBox) Polygon() *Polygon {
+	poly := NewPolygon()
 	poly.Push(NewPoint(bb.Min.X, bb.Min.Y))
 	poly.Push(NewPoint(bb.Max.X, bb.Min.Y))
 	poly.Push(NewPoint(bb.Max.X, bb.Max.Y))
 	poly.Push(NewPoint(bb.Min.X, bb.Max.Y))
+	return poly
 }
 
-// GetPolygon will get the polygon of the bounding box
-func (bb *BoundingBox) GetPolygon() *Polygon {
-	poly := NewPolygon()
-	bb.Polygon(poly)
-	return poly
+// Defined will tell if the bounding box holds any points
+func (bb *BoundingBox) Defined() bool {
+	return bb.defined
 }
 
-// MergeBox will merge a point with the bounding box
+// MergeBox will merge another bounding box into the bounding box
 func (bb *BoundingBox) MergeBox(mergeBox *BoundingBox) {
+	if !mergeBox.defined {
+		return
+	}
 	if !bb.defined {
-		bb.Min, bb.Max = mergeBox.Min, mergeBox.Max
+		bb.Min = NewPoint(mergeBox.Min.X, mergeBox.Min.Y)
+		bb.Max = NewPoint(mergeBox.Max.X, mergeBox.Max.Y)
 		bb.defined = true
+		return
 	}
 
 	bb.Min.X = math.Min(mergeBox.Min.X, bb.Min.X)
@@ -78,29 +82,32 @@ func (bb *BoundingBox) MergeBox(mergeBox *BoundingBox) {
 // MergePoint will merge a point into the bounding box
 func (bb *BoundingBox) MergePoint(point *Point) {
 	if !bb.defined {
-		bb.Min, bb.Max = point, point
+		bb.Min = NewPoint(point.X, point.Y)
+		bb.Max = NewPoint(point.X, point.Y)
 		bb.defined = true
+		return
 	}
 
 	bb.Min.X = math.Min(point.X, bb.Min.X)
 	bb.Min.Y = math.Min(point.Y, bb.Min.Y)
 
 	bb.Max.X = math.Max(point.X, bb.Max.X)
-	bb.Max.Y = math.Max(point.X, bb.Max.Y)
+	bb.Max.Y = math.Max(point.Y, bb.Max.Y)
 }
 
 // Rotate will Rotate this bounding box
 func (bb *BoundingBox) Rotate(angle float64) {
-	bb = bb.Rotated(angle)
+	*bb = *bb.Rotated(angle)
 }
 
 // RotateWithCenter will Rotate this bounding box
 func (bb *BoundingBox) RotateWithCenter(angle float64, center *Point) {
-	bb = bb.RotatedWithCenter(angle, center)
+	*bb = *bb.RotatedWithCenter(angle, center)
 }
 
 // Rotated will return a rotated boundingbox
 func (bb *BoundingBox) Rotated(angle float64) (box *BoundingBox) {
+	box = new(BoundingBox)
 	min := bb.Min.Rotated(angle)
 	max := bb.Max.Rotated(angle)
 
@@ -118,6 +125,7 @@ func (bb *BoundingBox) Rotated(angle float64) (box *BoundingBox) {
 
 // RotatedWithCenter will return a Rotated around center boundingbox
 func (bb *BoundingBox) RotatedWithCenter(angle float64, center *Point) (box *BoundingBox) {
+	box = new(BoundingBox)
 	min := bb.Min.RotatedWithCenter(angle, center)
 	max := bb.Max.RotatedWithCenter(angle, center)
 	box.MergePoint(min)
@@ -143,10 +151,11 @@ func (bb *BoundingBox) Size() *Point {
 	return NewPoint(bb.Max.X-bb.Min.X, bb.Max.Y-bb.Min.Y)
 }
 
-// Radius will return the Bounding Box Radius
+// Radius will return the Bounding Box Radius, half of its diagonal
 // TODO implement for Z
 func (bb *BoundingBox) Radius() float64 {
-	return 0
+	size := bb.Size()
+	return 0.5 * math.Sqrt(size.X*size.X+size.Y*size.Y)
 }
 
 // Translate will translate the Bounding Box
@@ -165,7 +174,7 @@ func (bb *BoundingBox) Offset(delta float64) {
 func (bb *BoundingBox) Center() *Point {
 	return NewPoint(
 		(bb.Max.X+bb.Min.X)/2,
-		(bb.Max.Y+bb.Max.Y)/2)
+		(bb.Max.Y+bb.Min.Y)/2)
 }
 
 // ContainsPoint will figure out if the bounding box contains a point
@@ -173,6 +182,11 @@ func (bb *BoundingBox) ContainsPoint(point *Point) bool {
 	return point.X >= bb.Min.X && point.X <= bb.Max.X && point.Y >= bb.Min.Y && point.Y <= bb.Max.Y
 }
 
+// Overlaps will tell if the bounding boxes share any point, touching counts
+func (bb *BoundingBox) Overlaps(other *BoundingBox) bool {
+	return bb.Min.X <= other.Max.X && other.Min.X <= bb.Max.X && bb.Min.Y <= other.Max.Y && other.Min.Y <= bb.Max.Y
+}
+
 // EqualBBoxi will Equate bounding boxes
 func EqualBBoxi(bbox1 *BoundingBox, bbox2 *BoundingBox) bool {
 	return EqualPoints(bbox1.Min, bbox2.Min) && EqualPoints(bbox1.Max, bbox2.Max)
@@ -0,0 +1,261 @@
+package slice
+
+import (
+	"math"
+	"sort"
+)
+
+// BridgeDetector finds the direction to print a bridge in so that its lines are
+// anchored on the lower layer at both ends. This follows Slic3r's BridgeDetector.cpp
+type BridgeDetector struct {
+	PolygonEx   *PolygonEx
+	LowerSlices PolygonExs
+	Spacing     float64 // scaled spacing of the bridge lines
+	Resolution  float64 // step between the tested angles
+	Angle       float64 // detected angle, -1 until DetectAngle succeeds
+
+	edges   Polylines  // parts of the bridge outline resting on the lower slices
+	anchors PolygonExs // lower slices just around the bridge
+}
+
+// bridgeCandidate is a tested angle with the length of its anchored lines
+type bridgeCandidate struct {
+	angle     float64
+	coverage  float64
+	maxLength float64
+}
+
+// defaultBridgeResolution is the step between tested angles of Slic3r, also used when
+// Resolution is not positive
+const defaultBridgeResolution = math.Pi / 36
+
+// NewBridgeDetector will construct a BridgeDetector for the bridge resting on lowerSlices
+func NewBridgeDetector(pgx *PolygonEx, lowerSlices PolygonExs, spacing float64) (*BridgeDetector, error) {
+	bd := new(BridgeDetector)
+	bd.PolygonEx = pgx
+	bd.LowerSlices = lowerSlices
+	bd.Spacing = spacing
+	bd.Resolution = defaultBridgeResolution
+	bd.Angle = -1
+
+	// outset the bridge by an arbitrary amount, the outer margin is used to find anchors
+	grown, err := Offset(pgx.Polygons(), spacing)
+	if err != nil {
+		return nil, err
+	}
+
+	// the outline of the grown bridge lying on the lower slices
+	outline := NewPolylines()
+	for _, pg := range grown {
+		outline.Push(pg.SplitAtFirstPoint())
+	}
+	contours := NewPolygons()
+	for _, lower := range lowerSlices {
+		contours.Push(lower.Contour)
+	}
+	if bd.edges, err = IntersectionPolylines(outline, contours); err != nil {
+		return nil, err
+	}
+
+	// anchors are where the grown bridge overlaps the lower slices
+	if bd.anchors, err = IntersectionEx(grown, lowerSlices.Polygons()); err != nil {
+		return nil, err
+	}
+	return bd, nil
+}
+
+// DetectAngle will try the candidate angles and keep the one with the most anchored
+// lines. Returns false when the bridge can't be anchored
+func (bd *BridgeDetector) DetectAngle() (bool, error) {
+	if bd.edges.Empty() || bd.anchors.Empty() {
+		// the bridge is completely in the air
+		return false, nil
+	}
+
+	candidates := make([]*bridgeCandidate, 0)
+	haveCoverage := false
+	for _, angle := range bd.candidateAngles() {
+		lines, err := bd.anchoredLines(angle)
+		if err != nil {
+			return false, err
+		}
+		candidate := &bridgeCandidate{angle: angle}
+		for _, line := range lines {
+			length := line.MP.Length()
+			candidate.coverage += length
+			candidate.maxLength = math.Max(candidate.maxLength, length)
+		}
+		haveCoverage = haveCoverage || candidate.coverage > 0
+		candidates = append(candidates, candidate)
+	}
+	if !haveCoverage {
+		return false, nil
+	}
+
+	// most coverage first
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].coverage > candidates[j].coverage
+	})
+
+	// if any other direction is within a line of the best coverage, prefer it if shorter
+	best := 0
+	for i := 1; i < len(candidates) && candidates[best].coverage-candidates[i].coverage < bd.Spacing; i++ {
+		if candidates[i].maxLength < candidates[best].maxLength {
+			best = i
+		}
+	}
+
+	bd.Angle = candidates[best].angle
+	if bd.Angle >= math.Pi {
+		bd.Angle -= math.Pi
+	}
+	return true, nil
+}
+
+// Detect will return the best angle and the area left unsupported when bridging at
+// it. The angle is -1 and the whole bridge is uncovered when it can't be anchored
+func (bd *BridgeDetector) Detect() (float64, PolygonExs, error) {
+	found, err := bd.DetectAngle()
+	if err != nil {
+		return -1, nil, err
+	}
+	if !found {
+		uncovered, err := UnionEx(bd.PolygonEx.Polygons())
+		return -1, uncovered, err
+	}
+	uncovered, err := bd.Uncovered(bd.Angle)
+	return bd.Angle, uncovered, err
+}
+
+// Coverage will return the part of the bridge covered by anchored lines at angle
+func (bd *BridgeDetector) Coverage(angle float64) (Polygons, error) {
+	lines, err := bd.anchoredLines(angle)
+	if err != nil || lines.Empty() {
+		return NewPolygons(), err
+	}
+	covered, err := OffsetPolylines(lines, bd.Spacing/2, JoinSquare, EndOpenButt, 3)
+	if err != nil {
+		return nil, err
+	}
+	return Intersection(covered, bd.PolygonEx.Polygons())
+}
+
+// Uncovered will return the part of the bridge not covered by anchored lines at angle
+func (bd *BridgeDetector) Uncovered(angle float64) (PolygonExs, error) {
+	covered, err := bd.Coverage(angle)
+	if err != nil {
+		return nil, err
+	}
+	return DiffEx(bd.PolygonEx.Polygons(), covered)
+}
+
+// candidateAngles will return the angles to test: every step of the resolution, the
+// directions of the bridge outline, and of the supporting edges which finds the
+// best angle for C shaped supports
+func (bd *BridgeDetector) candidateAngles() []float64 {
+	resolution := bd.Resolution
+	if !(resolution > 0) {
+		resolution = defaultBridgeResolution
+	}
+	angles := make([]float64, 0)
+	for i := 0; float64(i) <= math.Pi/resolution; i++ {
+		angles = append(angles, float64(i)*resolution)
+	}
+	for _, pg := range bd.PolygonEx.Polygons() {
+		for _, line := range pg.Lines() {
+			angles = append(angles, line.Direction())
+		}
+	}
+	for _, edge := range bd.edges {
+		if !edge.MP.Points.First().CoincidesWith(edge.MP.Points.Last()) {
+			angles = append(angles, NewLine(edge.MP.Points.First(), edge.MP.Points.Last()).Direction())
+		}
+	}
+
+	// remove duplicates within a degree
+	minResolution := math.Pi / 180
+	if len(angles) == 0 {
+		return angles
+	}
+	sort.Float64s(angles)
+	unique := angles[:1]
+	for _, angle := range angles[1:] {
+		if !DirectionsParallel(angle, unique[len(unique)-1], minResolution) {
+			unique = append(unique, angle)
+		}
+	}
+	// 0 and Pi are the same direction
+	if len(unique) > 1 && DirectionsParallel(unique[0], unique[len(unique)-1], minResolution) {
+		unique = unique[:len(unique)-1]
+	}
+	return unique
+}
+
+// anchoredLines will cover the anchors with lines at angle, clip them to the bridge
+// and keep the ones with both ends on an anchor
+func (bd *BridgeDetector) anchoredLines(angle float64) (Polylines, error) {
+	// clip slightly outside the bridge so the line ends fall inside the anchors rather
+	// than on their outline
+	clipArea, err := Offset(bd.PolygonEx.Polygons(), bd.Spacing/2)
+	if err != nil {
+		return nil, err
+	}
+
+	// bounding box of the bridge and its anchors aligned with the lines, so the lines
+	// always cross the bridge
+	rotated := NewPoints()
+	for _, pgx := range append(PolygonExs{bd.PolygonEx}, bd.anchors...) {
+		for _, point := range pgx.Points() {
+			rotated.Push(point.Rotated(-angle))
+		}
+	}
+	bb := NewBoundingBox(rotated...)
+
+	s, c := math.Sin(angle), math.Cos(angle)
+	lines := NewPolylines()
+	for y := bb.Min.Y + bd.Spacing/2; y <= bb.Max.Y; y += bd.Spacing {
+		lines.Push(polylineOf(
+			NewPoint(math.Round(c*bb.Min.X-s*y), math.Round(c*y+s*bb.Min.X)),
+			NewPoint(math.Round(c*bb.Max.X-s*y), math.Round(c*y+s*bb.Max.X))))
+	}
+	clipped, err := IntersectionPolylines(lines, clipArea)
+	if err != nil {
+		return nil, err
+	}
+
+	anchored := NewPolylines()
+	for _, line := range clipped {
+		if bd.anchored(line.MP.Points.First()) && bd.anchored(line.MP.Points.Last()) {
+			anchored.Push(line)
+		}
+	}
+	return anchored, nil
+}
+
+// anchored will tell if the point rests on an anchor
+func (bd *BridgeDetector) anchored(point *Point) bool {
+	for _, anchor := range bd.anchors {
+		if anchor.ContainsPoint(point) {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectBridgeAngles will set the bridge angle of the bottom bridges of every layer
+// from the slices of the layer below
+func DetectBridgeAngles(layers Layers, spacing float64) error {
+	for i := 1; i < len(layers); i++ {
+		for _, surface := range layers[i].Surfaces.Filter(StBottomBridge) {
+			bd, err := NewBridgeDetector(surface.PolygonEx, layers[i-1].Slices, spacing)
+			if err != nil {
+				return err
+			}
+			if _, err = bd.DetectAngle(); err != nil {
+				return err
+			}
+			surface.BridgeAngle = bd.Angle
+		}
+	}
+	return nil
+}
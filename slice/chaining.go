@@ -0,0 +1,176 @@
+package slice
+
+// Chaining orders paths to keep the travels between them short. Paths are picked by
+// nearest neighbour from a PointIndex, then runs of reversible paths are flipped with
+// 2-opt while that shortens the travels. Open paths may be reversed and loops may
+// start at any of their vertices.
+
+// chainItem is something to chain. Started at starts[i] it ends at ends[i]
+type chainItem struct {
+	starts Points
+	ends   Points
+	loop   bool
+}
+
+// chainStep is an item of the chain and which of its starts it uses
+type chainStep struct {
+	item   int
+	choice int
+}
+
+// maxTwoOptPasses bounds the improvement of long chains
+const maxTwoOptPasses = 10
+
+// newOpenChainItem will make the item of an open path, reversible unless noReverse
+func newOpenChainItem(first *Point, last *Point, noReverse bool) *chainItem {
+	item := new(chainItem)
+	item.starts = Points{first}
+	item.ends = Points{last}
+	if !noReverse {
+		item.starts.Push(last)
+		item.ends.Push(first)
+	}
+	return item
+}
+
+// newLoopChainItem will make the item of a loop which may start at any vertex
+func newLoopChainItem(vertices Points) *chainItem {
+	item := new(chainItem)
+	item.starts = vertices
+	item.ends = vertices
+	item.loop = true
+	return item
+}
+
+// reversible will tell if the direction of the step can be flipped by 2-opt
+func (item *chainItem) reversible() bool {
+	return item.loop || len(item.starts) == 2
+}
+
+// chain will order the items starting near start
+func chain(items []*chainItem, start *Point) []chainStep {
+	points := NewPoints()
+	owners := make([]chainStep, 0)
+	ids := make([][]int, len(items))
+	for i, item := range items {
+		for choice, point := range item.starts {
+			ids[i] = append(ids[i], len(points))
+			points.Push(point)
+			owners = append(owners, chainStep{i, choice})
+		}
+	}
+
+	index := NewPointIndex(points)
+	steps := make([]chainStep, 0, len(items))
+	last := start
+	for index.Len() > 0 {
+		step := owners[index.Nearest(last)]
+		for _, id := range ids[step.item] {
+			index.Remove(id)
+		}
+		steps = append(steps, step)
+		last = items[step.item].ends[step.choice]
+	}
+
+	improveChain(items, steps, start)
+	return steps
+}
+
+// improveChain will reverse runs of reversible steps with 2-opt while that shortens
+// the travels
+func improveChain(items []*chainItem, steps []chainStep, start *Point) {
+	startOf := func(step chainStep) *Point { return items[step.item].starts[step.choice] }
+	endOf := func(step chainStep) *Point { return items[step.item].ends[step.choice] }
+
+	improved := true
+	for pass := 0; improved && pass < maxTwoOptPasses; pass++ {
+		improved = false
+		for i := range steps {
+			prev := start
+			if i > 0 {
+				prev = endOf(steps[i-1])
+			}
+			for j := i; j < len(steps) && items[steps[j].item].reversible(); j++ {
+				// reversed, the run starts at the end of step j and ends at the start of step i
+				delta := prev.DistanceTo(endOf(steps[j])) - prev.DistanceTo(startOf(steps[i]))
+				if j+1 < len(steps) {
+					next := startOf(steps[j+1])
+					delta += startOf(steps[i]).DistanceTo(next) - endOf(steps[j]).DistanceTo(next)
+				}
+				if delta >= -ScaledEpsilon {
+					continue
+				}
+
+				for a, b := i, j; a < b; a, b = a+1, b-1 {
+					steps[a], steps[b] = steps[b], steps[a]
+				}
+				for k := i; k <= j; k++ {
+					if !items[steps[k].item].loop {
+						steps[k].choice = 1 - steps[k].choice
+					}
+				}
+				improved = true
+			}
+		}
+	}
+}
+
+// ChainPolylines will order the polylines starting near start, reversing them when
+// that shortens travels unless noReverse is set. Polylines are reversed in place
+func ChainPolylines(plines Polylines, start *Point, noReverse bool) Polylines {
+	items := make([]*chainItem, 0, len(plines))
+	for _, pline := range plines {
+		items = append(items, newOpenChainItem(pline.MP.Points.First(), pline.MP.Points.Last(), noReverse))
+	}
+
+	chained := NewPolylines()
+	for _, step := range chain(items, start) {
+		pline := plines[step.item]
+		if step.choice == 1 {
+			pline.MP.Reverse()
+		}
+		chained.Push(pline)
+	}
+	return chained
+}
+
+// ChainPolygons will order the loops starting near start, opening each at the vertex
+// where the travel to it is shortest
+func ChainPolygons(pgs Polygons, start *Point) Polylines {
+	items := make([]*chainItem, 0, len(pgs))
+	for _, pg := range pgs {
+		items = append(items, newLoopChainItem(pg.MP.Points))
+	}
+
+	chained := NewPolylines()
+	for _, step := range chain(items, start) {
+		chained.Push(pgs[step.item].SplitAtIndex(step.choice))
+	}
+	return chained
+}
+
+// ChainEntities will order copies of the entities starting near start. Entities
+// that can be reversed are unless noReverse is set, and loops start at the vertex
+// where the travel to them is shortest
+func ChainEntities(entities ExtrusionEntities, start *Point, noReverse bool) ExtrusionEntities {
+	items := make([]*chainItem, 0, len(entities))
+	for _, entity := range entities {
+		if loop, ok := entity.(*ExtrusionLoop); ok {
+			items = append(items, newLoopChainItem(loop.Polygon().MP.Points))
+			continue
+		}
+		items = append(items, newOpenChainItem(entity.FirstPoint(), entity.LastPoint(), noReverse || !entity.CanReverse()))
+	}
+
+	chained := NewExtrusionEntities()
+	for _, step := range chain(items, start) {
+		entity := entities[step.item].Clone()
+		if loop, ok := entity.(*ExtrusionLoop); ok {
+			loop.SplitAtVertex(items[step.item].starts[step.choice])
+		} else if step.choice == 1 {
+			entity.Reverse()
+		}
+		chained.Push(entity)
+	}
+	return chained
+}
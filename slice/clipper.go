@@ -4,6 +4,22 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"math/big"
+	"sort"
+)
+
+// clipperError is used to unwind the sweep when the edge structures end up
+// in an invalid state. It is recovered in ExecuteInternal and returned as a
+// regular error, the same way clipper.cpp catches its clipperException.
+type clipperError string
+
+func (ce clipperError) Error() string {
+	return string(ce)
+}
+
+var (
+	ErrExecuteLocked   = errors.New("Execution Locked")
+	ErrOpenPathsNeedPT = errors.New("PolyTree struct is needed for open path clipping")
 )
 
 func NearZero(val float64) bool {
@@ -11,15 +27,15 @@ func NearZero(val float64) bool {
 }
 
 type TEdge struct {
-	Bot       *Point
-	Curr      *Point
-	Top       *Point
+	Bot       Point
+	Curr      Point // current (updated for every new scanbeam)
+	Top       Point
 	Dx        float64
 	PolyType  PolyType
-	Side      EdgeSide
-	WinDelta  int
+	Side      EdgeSide // side only refers to current side of solution poly
+	WinDelta  int      // 1 or -1 depending on winding direction
 	WinCnt    int
-	WinCnt2   int
+	WinCnt2   int // winding count of the opposite polytype
 	OutIdx    int
 	Next      *TEdge
 	Prev      *TEdge
@@ -33,15 +49,15 @@ type TEdge struct {
 type IntersectNode struct {
 	Edge1 *TEdge
 	Edge2 *TEdge
-	Pt    *Point
+	Pt    Point
 }
 
 type OutRec struct {
 	Idx       int
 	IsHole    bool
 	IsOpen    bool
-	FirstLeft *OutRec
-	PolyNd    *Polygon
+	FirstLeft *OutRec // see comments in clipper.pas
+	PolyNd    *PolyNode
 	Pts       *OutPt
 	BottomPt  *OutPt
 }
@@ -60,26 +76,38 @@ type OutPt struct {
 	Prev *OutPt
 }
 
+// Area will return the area of the ring starting at this OutPt
 func (op *OutPt) Area() float64 {
-	StartIdx := op.Idx
 	if op == nil {
 		return 0
 	}
+	startOp := op
 
 	var area float64 = 0.00
-	for op.Idx != StartIdx {
+	for {
 		area += (op.Prev.Pt.X + op.Pt.X) * (op.Prev.Pt.Y - op.Pt.Y)
 		op = op.Next
+		if op == startOp {
+			break
+		}
 	}
 	return area * 0.5
 }
 
+// Area will return the area of the OutRec
+func (outrec *OutRec) Area() float64 {
+	return outrec.Pts.Area()
+}
+
 type Join struct {
-	OutPt1 *Point
-	OutPt2 *Point
-	OffPt  *Point
+	OutPt1 *OutPt
+	OutPt2 *OutPt
+	OffPt  Point
 }
 
+// PointInPolygon returns 0 if false, +1 if true, -1 if pt ON polygon boundary
+// See "The Point in Polygon Problem for Arbitrary Polygons" by Hormann & Agathos
+// http://citeseerx.ist.psu.edu/viewdoc/download?doi=10.1.1.88.5498&rep=rep1&type=pdf
 func PointInPolygon(pt *Point, poly *Polygon) int {
 	var result int = 0
 	cnt := len(poly.MP.Points)
@@ -91,8 +119,8 @@ func PointInPolygon(pt *Point, poly *Polygon) int {
 	for idx, point := range poly.MP.Points {
 		nextPoint := poly.MP.Points.NextEntry(idx)
 
-		if nextPoint.Y == point.Y {
-			if (nextPoint.X == point.X) || (point.Y == pt.Y &&
+		if nextPoint.Y == pt.Y {
+			if (nextPoint.X == pt.X) || (point.Y == pt.Y &&
 				((nextPoint.X > pt.X) == (point.X < pt.X))) {
 				return -1
 			}
@@ -128,48 +156,89 @@ func PointInPolygon(pt *Point, poly *Polygon) int {
 	return result
 }
 
+// PointInOutPt is PointInPolygon for an OutPt ring
 func PointInOutPt(pt *Point, op *OutPt) int {
-	poly := NewPolygon()
-	start := op.Idx
-
-	poly.Push(op.Pt)
+	result := 0
+	startOp := op
 	for {
+		if op.Next.Pt.Y == pt.Y {
+			if (op.Next.Pt.X == pt.X) || (op.Pt.Y == pt.Y &&
+				((op.Next.Pt.X > pt.X) == (op.Pt.X < pt.X))) {
+				return -1
+			}
+		}
+		if (op.Pt.Y < pt.Y) != (op.Next.Pt.Y < pt.Y) {
+			if op.Pt.X >= pt.X {
+				if op.Next.Pt.X > pt.X {
+					result = 1 - result
+				} else {
+					d := (op.Pt.X-pt.X)*(op.Next.Pt.Y-pt.Y) - (op.Next.Pt.X-pt.X)*(op.Pt.Y-pt.Y)
+					if d == 0.00 {
+						return -1
+					}
+					if (d > 0.00) == (op.Next.Pt.Y > op.Pt.Y) {
+						result = 1 - result
+					}
+				}
+			} else {
+				if op.Next.Pt.X > pt.X {
+					d := (op.Pt.X-pt.X)*(op.Next.Pt.Y-pt.Y) - (op.Next.Pt.X-pt.X)*(op.Pt.Y-pt.Y)
+					if d == 0.00 {
+						return -1
+					}
+					if (d > 0.00) == (op.Next.Pt.Y > op.Pt.Y) {
+						result = 1 - result
+					}
+				}
+			}
+		}
 		op = op.Next
-		if op.Idx == start {
+		if op == startOp {
 			break
 		}
-		poly.Push(op.Pt)
 	}
-
-	return PointInPolygon(pt, poly)
+	return result
 }
 
 func Poly2ContainsPoly1(poly1, poly2 *OutPt) bool {
-	opIdx := poly1.Idx
+	op := poly1
 	for {
-		res := PointInOutPt(poly1.Pt, poly2)
+		// nb: PointInPolygon returns 0 if false, +1 if true, -1 if pt on polygon
+		res := PointInOutPt(op.Pt, poly2)
 		if res >= 0 {
 			return res > 0
 		}
-		poly1 = poly1.Next
-		if poly1.Idx == opIdx {
+		op = op.Next
+		if op == poly1 {
 			break
 		}
 	}
 	return true
 }
 
-func SlopesEqual(edge1, edge2 *TEdge) bool {
-	return (edge1.Top.Y-edge1.Bot.Y)*(edge2.Top.X-edge2.Bot.X) ==
-		(edge1.Top.X-edge1.Bot.X)*(edge2.Top.Y-edge2.Bot.Y)
+// slopesEqual compares a*b with c*d without losing precision. Coordinates are
+// always whole numbers inside the clipper so the products can be done in
+// integer space, switching to big ints once the coordinates leave loRange.
+func slopesEqual(a, b, c, d float64, useFullRange bool) bool {
+	if useFullRange {
+		lhs := new(big.Int).Mul(big.NewInt(int64(a)), big.NewInt(int64(b)))
+		rhs := new(big.Int).Mul(big.NewInt(int64(c)), big.NewInt(int64(d)))
+		return lhs.Cmp(rhs) == 0
+	}
+	return int64(a)*int64(b) == int64(c)*int64(d)
+}
+
+func SlopesEqual(edge1, edge2 *TEdge, useFullRange bool) bool {
+	return slopesEqual(edge1.Top.Y-edge1.Bot.Y, edge2.Top.X-edge2.Bot.X,
+		edge1.Top.X-edge1.Bot.X, edge2.Top.Y-edge2.Bot.Y, useFullRange)
 }
 
-func SlopesEqual3Pt(pt1, pt2, pt3 *Point) bool {
-	return (pt1.Y-pt2.Y)*(pt2.X-pt3.X) == (pt1.X-pt2.X)*(pt2.Y-pt3.Y)
+func SlopesEqual3Pt(pt1, pt2, pt3 *Point, useFullRange bool) bool {
+	return slopesEqual(pt1.Y-pt2.Y, pt2.X-pt3.X, pt1.X-pt2.X, pt2.Y-pt3.Y, useFullRange)
 }
 
-func SlopesEqual4Pt(pt1, pt2, pt3, pt4 *Point) bool {
-	return (pt1.Y-pt2.Y)*(pt3.X-pt4.X) == (pt1.X-pt2.X)*(pt3.Y-pt4.Y)
+func SlopesEqual4Pt(pt1, pt2, pt3, pt4 *Point, useFullRange bool) bool {
+	return slopesEqual(pt1.Y-pt2.Y, pt3.X-pt4.X, pt1.X-pt2.X, pt3.Y-pt4.Y, useFullRange)
 }
 
 func IsHorizontal(edge *TEdge) bool {
@@ -183,17 +252,7 @@ func GetDx2Pt(pt1, pt2 *Point) float64 {
 	return (pt2.X - pt1.X) / (pt2.Y - pt1.Y)
 }
 
-func SetDxFromTedge(edge *TEdge) {
-	dy := edge.Top.Y - edge.Bot.Y
-	if dy == 0 {
-		edge.Dx = HORIZONTAL
-		return
-	}
-
-	edge.Dx = (edge.Top.X - edge.Bot.Y) / dy
-}
-
-// Small bit of go-ifying
+// SetDx will set the inverse slope of the edge
 func (edge *TEdge) SetDx() {
 	dy := edge.Top.Y - edge.Bot.Y
 	if dy == 0 {
@@ -201,7 +260,7 @@ func (edge *TEdge) SetDx() {
 		return
 	}
 
-	edge.Dx = (edge.Top.X - edge.Bot.Y) / dy
+	edge.Dx = (edge.Top.X - edge.Bot.X) / dy
 }
 
 func SwapSides(edge1, edge2 *TEdge) {
@@ -219,7 +278,8 @@ func (edge *TEdge) TopX(currentY float64) float64 {
 	return edge.Bot.X + math.Round(edge.Dx*(currentY-edge.Bot.Y))
 }
 
-func IntersectPoint(edge1, edge2 *TEdge, point *Point) {
+// IntersectPoint will return the point where two edges intersect
+func IntersectPoint(edge1, edge2 *TEdge) (point Point) {
 	var b1 float64
 	var b2 float64
 
@@ -279,13 +339,13 @@ func IntersectPoint(edge1, edge2 *TEdge, point *Point) {
 			point.X = edge1.TopX(point.Y)
 		}
 	}
+	return
 }
 
 func ReversePolyPtLinks(pp *OutPt) {
 	if pp == nil {
 		return
 	}
-	OrigIdx := pp.Idx
 	pp1 := pp
 	var pp2 *OutPt
 
@@ -295,7 +355,7 @@ func ReversePolyPtLinks(pp *OutPt) {
 		pp1.Prev = pp2
 		pp1 = pp2
 
-		if pp1.Idx == OrigIdx {
+		if pp1 == pp {
 			break
 		}
 	}
@@ -318,10 +378,11 @@ func DisposeOutPts(pp *OutPt) {
 }
 
 func (edge *TEdge) InitEdge(next, prev *TEdge, pt *Point) {
+	*edge = TEdge{}
 	edge.Next = next
 	edge.Prev = prev
-	edge.Curr = pt
-	edge.OutIdx = 0
+	edge.Curr = *pt
+	edge.OutIdx = Unassigned
 }
 
 func (edge *TEdge) InitEdgeWithPolyType(polyType PolyType) {
@@ -336,13 +397,13 @@ func (edge *TEdge) InitEdgeWithPolyType(polyType PolyType) {
 	edge.PolyType = polyType
 }
 
-// RemoveEdge will remove the current TEdge from the linked list
-func (edge *TEdge) RemoveEdge() {
+// RemoveEdge will remove the current TEdge from the linked list and return the next edge
+func (edge *TEdge) RemoveEdge() *TEdge {
 	edge.Prev.Next = edge.Next
 	edge.Next.Prev = edge.Prev
 	result := edge.Next
-	edge.Prev = nil // flag as removed (see ClipperBase.Clear) (Maybe... who knows)
-	edge = result
+	edge.Prev = nil // flag as removed (see ClipperBase.Clear)
+	return result
 }
 
 func (edge *TEdge) ReverseHorizontal() {
@@ -371,7 +432,7 @@ func GetOverlapSegment(pt1a, pt1b, pt2a, pt2b *Point) (result bool, pt1 *Point,
 		} else {
 			pt1 = pt2a
 		}
-		if pt1b.X > pt2b.X {
+		if pt1b.X < pt2b.X {
 			pt2 = pt1b
 		} else {
 			pt2 = pt2b
@@ -379,13 +440,13 @@ func GetOverlapSegment(pt1a, pt1b, pt2a, pt2b *Point) (result bool, pt1 *Point,
 		result = pt1.X < pt2.X
 		return
 	}
-	if pt1a.Y > pt1b.Y {
+	if pt1a.Y < pt1b.Y {
 		pt1a, pt1b = SwapPoints(pt1a, pt1b)
 	}
-	if pt2a.Y > pt2b.Y {
+	if pt2a.Y < pt2b.Y {
 		pt2a, pt2b = SwapPoints(pt2a, pt2b)
 	}
-	if pt1a.Y > pt2a.Y {
+	if pt1a.Y < pt2a.Y {
 		pt1 = pt1a
 	} else {
 		pt1 = pt2a
@@ -395,36 +456,36 @@ func GetOverlapSegment(pt1a, pt1b, pt2a, pt2b *Point) (result bool, pt1 *Point,
 	} else {
 		pt2 = pt2b
 	}
-	result = pt1.Y < pt2.Y
+	result = pt1.Y > pt2.Y
 	return
 }
 
 func FirstIsBottomPt(btmPt1 *OutPt, btmPt2 *OutPt) bool {
 	p := btmPt1.Prev
-	for EqualPoints(p.Pt, btmPt1.Pt) && p.Idx != btmPt1.Idx {
+	for EqualPoints(p.Pt, btmPt1.Pt) && p != btmPt1 {
 		p = p.Prev
 	}
 	dx1p := math.Abs(GetDx2Pt(btmPt1.Pt, p.Pt))
 	p = btmPt1.Next
-	for EqualPoints(p.Pt, btmPt1.Pt) && p.Idx != btmPt1.Idx {
+	for EqualPoints(p.Pt, btmPt1.Pt) && p != btmPt1 {
 		p = p.Next
 	}
 	dx1n := math.Abs(GetDx2Pt(btmPt1.Pt, p.Pt))
 
 	p = btmPt2.Prev
-	for EqualPoints(p.Pt, btmPt2.Pt) && p.Idx != btmPt2.Idx {
+	for EqualPoints(p.Pt, btmPt2.Pt) && p != btmPt2 {
 		p = p.Prev
 	}
 	dx2p := math.Abs(GetDx2Pt(btmPt2.Pt, p.Pt))
 	p = btmPt2.Next
-	for EqualPoints(p.Pt, btmPt2.Pt) && p.Idx != btmPt2.Idx {
+	for EqualPoints(p.Pt, btmPt2.Pt) && p != btmPt2 {
 		p = p.Next
 	}
 	dx2n := math.Abs(GetDx2Pt(btmPt2.Pt, p.Pt))
 
 	if math.Max(dx1p, dx1n) == math.Max(dx2p, dx2n) &&
-		math.Min(dx1p, dx1n) == math.Min(dx2p, dx1n) {
-		return btmPt1.Area() > 0
+		math.Min(dx1p, dx1n) == math.Min(dx2p, dx2n) {
+		return btmPt1.Area() > 0 // if otherwise identical use orientation
 	}
 	return (dx1p >= dx2p && dx1p >= dx2n) || (dx1n >= dx2p && dx1n >= dx2n)
 
@@ -434,7 +495,7 @@ func (pp *OutPt) GetBottomPt() *OutPt {
 	var dups *OutPt = nil
 	p := pp.Next
 
-	for p.Idx != pp.Idx {
+	for p != pp {
 		if p.Pt.Y > pp.Pt.Y {
 			pp = p
 			dups = nil
@@ -443,7 +504,7 @@ func (pp *OutPt) GetBottomPt() *OutPt {
 				dups = nil
 				pp = p
 			} else {
-				if p.Next.Idx != pp.Idx && p.Prev.Idx != pp.Idx {
+				if p.Next != pp && p.Prev != pp {
 					dups = p
 				}
 			}
@@ -452,12 +513,13 @@ func (pp *OutPt) GetBottomPt() *OutPt {
 	}
 
 	if dups != nil {
-		for dups.Idx != p.Idx {
+		// there appears to be at least 2 vertices at BottomPt so ...
+		for dups != p {
 			if !FirstIsBottomPt(p, dups) {
 				pp = dups
 			}
 			dups = dups.Next
-			for dups.Pt != pp.Pt {
+			for !EqualPoints(dups.Pt, pp.Pt) {
 				dups = dups.Next
 			}
 		}
@@ -474,26 +536,25 @@ func Pt2IsBetweenPt1AndPt3(pt1, pt2, pt3 *Point) bool {
 	return (pt2.Y > pt1.Y) == (pt2.Y < pt3.Y)
 }
 
-func HorzSegmentsOverlap(seg1a, seg1b, seg2a, seg2b float64) (bool, float64, float64, float64, float64) {
-
+func HorzSegmentsOverlap(seg1a, seg1b, seg2a, seg2b float64) bool {
 	if seg1a > seg1b {
 		seg1a, seg1b = seg1b, seg1a
 	}
 	if seg2a > seg2b {
 		seg2a, seg2b = seg2b, seg2a
 	}
-	return (seg1a < seg2b) && (seg2a < seg1b), seg1a, seg1b, seg2a, seg2b
-
+	return (seg1a < seg2b) && (seg2a < seg1b)
 }
 
-func RangeTest(pt *Point, useFullRange bool) error {
-	if useFullRange {
+// RangeTest will check that a point can be processed. useFullRange will be switched
+// on if the point is outside of the low range
+func RangeTest(pt *Point, useFullRange *bool) error {
+	if *useFullRange {
 		if pt.X > hiRange || pt.Y > hiRange || -pt.X > hiRange || -pt.Y > hiRange {
 			return ErrOutsideRange
 		}
-	}
-	if pt.X > loRange || pt.Y > loRange || -pt.X > loRange || -pt.Y > loRange {
-		useFullRange = true
+	} else if pt.X > loRange || pt.Y > loRange || -pt.X > loRange || -pt.Y > loRange {
+		*useFullRange = true
 		return RangeTest(pt, useFullRange)
 	}
 	return nil
@@ -502,14 +563,17 @@ func RangeTest(pt *Point, useFullRange bool) error {
 func (edge *TEdge) FindNextLocMin() *TEdge {
 	e := edge
 	for {
-		for !EqualPoints(e.Bot, e.Prev.Bot) || EqualPoints(e.Curr, e.Top) {
+		for e.Bot != e.Prev.Bot || e.Curr == e.Top {
 			e = e.Next
 		}
 
-		if IsHorizontal(e) && !IsHorizontal(e.Prev) {
+		if !IsHorizontal(e) && !IsHorizontal(e.Prev) {
 			break
 		}
 
+		for IsHorizontal(e.Prev) {
+			e = e.Prev
+		}
 		e2 := e
 		for IsHorizontal(e) {
 			e = e.Next
@@ -526,6 +590,261 @@ func (edge *TEdge) FindNextLocMin() *TEdge {
 	return e
 }
 
+func IsMinima(e *TEdge) bool {
+	return e != nil && (e.Prev.NextInLML != e) && (e.Next.NextInLML != e)
+}
+
+func IsMaxima(e *TEdge, y float64) bool {
+	return e != nil && e.Top.Y == y && e.NextInLML == nil
+}
+
+func IsIntermediate(e *TEdge, y float64) bool {
+	return e.Top.Y == y && e.NextInLML != nil
+}
+
+func GetMaximaPair(e *TEdge) *TEdge {
+	if e.Next.Top == e.Top && e.Next.NextInLML == nil {
+		return e.Next
+	} else if e.Prev.Top == e.Top && e.Prev.NextInLML == nil {
+		return e.Prev
+	}
+	return nil
+}
+
+// GetMaximaPairEx is GetMaximaPair but returns nil if MaxPair isn't in AEL (unless it's horizontal)
+func GetMaximaPairEx(e *TEdge) *TEdge {
+	result := GetMaximaPair(e)
+	if result != nil && (result.OutIdx == Skip ||
+		(result.NextInAEL == result.PrevInAEL && !IsHorizontal(result))) {
+		return nil
+	}
+	return result
+}
+
+func GetNextInAEL(e *TEdge, dir Direction) *TEdge {
+	if dir == dLeftToRight {
+		return e.NextInAEL
+	}
+	return e.PrevInAEL
+}
+
+func GetHorzDirection(horzEdge *TEdge) (dir Direction, left float64, right float64) {
+	if horzEdge.Bot.X < horzEdge.Top.X {
+		return dLeftToRight, horzEdge.Bot.X, horzEdge.Top.X
+	}
+	return dRightToLeft, horzEdge.Top.X, horzEdge.Bot.X
+}
+
+func GetLowermostRec(outRec1, outRec2 *OutRec) *OutRec {
+	// work out which polygon fragment has the correct hole state ...
+	if outRec1.BottomPt == nil {
+		outRec1.BottomPt = outRec1.Pts.GetBottomPt()
+	}
+	if outRec2.BottomPt == nil {
+		outRec2.BottomPt = outRec2.Pts.GetBottomPt()
+	}
+	outPt1 := outRec1.BottomPt
+	outPt2 := outRec2.BottomPt
+	switch {
+	case outPt1.Pt.Y > outPt2.Pt.Y:
+		return outRec1
+	case outPt1.Pt.Y < outPt2.Pt.Y:
+		return outRec2
+	case outPt1.Pt.X < outPt2.Pt.X:
+		return outRec1
+	case outPt1.Pt.X > outPt2.Pt.X:
+		return outRec2
+	case outPt1.Next == outPt1:
+		return outRec2
+	case outPt2.Next == outPt2:
+		return outRec1
+	case FirstIsBottomPt(outPt1, outPt2):
+		return outRec1
+	}
+	return outRec2
+}
+
+// OutRec1RightOfOutRec2 will check if outRec2 is owner of outRec1
+func OutRec1RightOfOutRec2(outRec1, outRec2 *OutRec) bool {
+	for {
+		outRec1 = outRec1.FirstLeft
+		if outRec1 == outRec2 {
+			return true
+		}
+		if outRec1 == nil {
+			break
+		}
+	}
+	return false
+}
+
+func PointCount(pts *OutPt) int {
+	if pts == nil {
+		return 0
+	}
+	result := 0
+	p := pts
+	for {
+		result++
+		p = p.Next
+		if p == pts {
+			break
+		}
+	}
+	return result
+}
+
+func E2InsertsBeforeE1(e1, e2 *TEdge) bool {
+	if e2.Curr.X == e1.Curr.X {
+		if e2.Top.Y > e1.Top.Y {
+			return e2.Top.X < e1.TopX(e2.Top.Y)
+		}
+		return e1.Top.X > e2.TopX(e1.Top.Y)
+	}
+	return e2.Curr.X < e1.Curr.X
+}
+
+func GetOverlap(a1, a2, b1, b2 float64) (overlap bool, left float64, right float64) {
+	if a1 < a2 {
+		if b1 < b2 {
+			left, right = math.Max(a1, b1), math.Min(a2, b2)
+		} else {
+			left, right = math.Max(a1, b2), math.Min(a2, b1)
+		}
+	} else {
+		if b1 < b2 {
+			left, right = math.Max(a2, b1), math.Min(a1, b2)
+		} else {
+			left, right = math.Max(a2, b2), math.Min(a1, b1)
+		}
+	}
+	return left < right, left, right
+}
+
+func UpdateOutPtIdxs(outrec *OutRec) {
+	op := outrec.Pts
+	for {
+		op.Idx = outrec.Idx
+		op = op.Prev
+		if op == outrec.Pts {
+			break
+		}
+	}
+}
+
+func DupOutPt(outPt *OutPt, insertAfter bool) *OutPt {
+	result := new(OutPt)
+	result.Pt = NewPoint(outPt.Pt.X, outPt.Pt.Y)
+	result.Idx = outPt.Idx
+	if insertAfter {
+		result.Next = outPt.Next
+		result.Prev = outPt
+		outPt.Next.Prev = result
+		outPt.Next = result
+	} else {
+		result.Prev = outPt.Prev
+		result.Next = outPt
+		outPt.Prev.Next = result
+		outPt.Prev = result
+	}
+	return result
+}
+
+func JoinHorz(op1, op1b, op2, op2b *OutPt, pt Point, discardLeft bool) bool {
+	dir1 := dLeftToRight
+	if op1.Pt.X > op1b.Pt.X {
+		dir1 = dRightToLeft
+	}
+	dir2 := dLeftToRight
+	if op2.Pt.X > op2b.Pt.X {
+		dir2 = dRightToLeft
+	}
+	if dir1 == dir2 {
+		return false
+	}
+
+	//When DiscardLeft, we want Op1b to be on the Left of Op1, otherwise we
+	//want Op1b to be on the Right. (And likewise with Op2 and Op2b.)
+	//So, to facilitate this while inserting Op1b and Op2b ...
+	//when DiscardLeft, make sure we're AT or RIGHT of Pt before adding Op1b,
+	//otherwise make sure we're AT or LEFT of Pt. (Likewise with Op2b.)
+	if dir1 == dLeftToRight {
+		for op1.Next.Pt.X <= pt.X && op1.Next.Pt.X >= op1.Pt.X && op1.Next.Pt.Y == pt.Y {
+			op1 = op1.Next
+		}
+		if discardLeft && op1.Pt.X != pt.X {
+			op1 = op1.Next
+		}
+		op1b = DupOutPt(op1, !discardLeft)
+		if *op1b.Pt != pt {
+			op1 = op1b
+			op1.Pt = NewPoint(pt.X, pt.Y)
+			op1b = DupOutPt(op1, !discardLeft)
+		}
+	} else {
+		for op1.Next.Pt.X >= pt.X && op1.Next.Pt.X <= op1.Pt.X && op1.Next.Pt.Y == pt.Y {
+			op1 = op1.Next
+		}
+		if !discardLeft && op1.Pt.X != pt.X {
+			op1 = op1.Next
+		}
+		op1b = DupOutPt(op1, discardLeft)
+		if *op1b.Pt != pt {
+			op1 = op1b
+			op1.Pt = NewPoint(pt.X, pt.Y)
+			op1b = DupOutPt(op1, discardLeft)
+		}
+	}
+
+	if dir2 == dLeftToRight {
+		for op2.Next.Pt.X <= pt.X && op2.Next.Pt.X >= op2.Pt.X && op2.Next.Pt.Y == pt.Y {
+			op2 = op2.Next
+		}
+		if discardLeft && op2.Pt.X != pt.X {
+			op2 = op2.Next
+		}
+		op2b = DupOutPt(op2, !discardLeft)
+		if *op2b.Pt != pt {
+			op2 = op2b
+			op2.Pt = NewPoint(pt.X, pt.Y)
+			op2b = DupOutPt(op2, !discardLeft)
+		}
+	} else {
+		for op2.Next.Pt.X >= pt.X && op2.Next.Pt.X <= op2.Pt.X && op2.Next.Pt.Y == pt.Y {
+			op2 = op2.Next
+		}
+		if !discardLeft && op2.Pt.X != pt.X {
+			op2 = op2.Next
+		}
+		op2b = DupOutPt(op2, discardLeft)
+		if *op2b.Pt != pt {
+			op2 = op2b
+			op2.Pt = NewPoint(pt.X, pt.Y)
+			op2b = DupOutPt(op2, discardLeft)
+		}
+	}
+
+	if (dir1 == dLeftToRight) == discardLeft {
+		op1.Prev = op2
+		op2.Next = op1
+		op1b.Next = op2b
+		op2b.Prev = op1b
+	} else {
+		op1.Next = op2
+		op2.Prev = op1
+		op1b.Prev = op2b
+		op2b.Next = op1b
+	}
+	return true
+}
+
+func ParseFirstLeft(firstLeft *OutRec) *OutRec {
+	for firstLeft != nil && firstLeft.Pts == nil {
+		firstLeft = firstLeft.FirstLeft
+	}
+	return firstLeft
+}
+
 // Clipper Options
 type ClipperOptions struct {
 	ExecuteLocked     bool
@@ -539,15 +858,26 @@ type ClipperOptions struct {
 
 // clipper
 type Clipper struct {
-	opt  ClipperOptions
-	base *ClipperBase
+	*ClipperBase
+	opt           ClipperOptions
+	joins         []*Join
+	ghostJoins    []*Join
+	intersectList []*IntersectNode
+	clipType      ClipType
+	maxima        []float64
+	sortedEdges   *TEdge
+	clipFillType  PolyFillType
+	subjFillType  PolyFillType
+	usingPolyTree bool
 }
 
 // New Clipper Object
 func NewClipper(options ClipperOptions) *Clipper {
 	clip := new(Clipper)
 	clip.opt = options
-	clip.base = new(ClipperBase)
+	clip.ClipperBase = NewClipperBase()
+	clip.preserveCollinear = options.PreserveCollinear
+	clip.useFullRange = options.UseFullRange
 
 	return clip
 }
@@ -558,25 +888,57 @@ func (clip *Clipper) ZFillFunc() {
 	fmt.Println("ZFill Func not implemented yet.")
 }
 
-func (clip *Clipper) ExecutePolygon(clipType ClipType, solution *Polygon, fillType PolyFillType) error {
+// Execute will run the clip operation on all added paths and return the closed
+// solution polygons. Open paths need ExecutePolyTree
+func (clip *Clipper) Execute(clipType ClipType, subjFillType PolyFillType, clipFillType PolyFillType) (Polygons, error) {
 	if clip.opt.ExecuteLocked {
-		return errors.New("Execution Locked")
+		return nil, ErrExecuteLocked
+	}
+	if clip.hasOpenPaths {
+		return nil, ErrOpenPathsNeedPT
 	}
 
 	clip.opt.ExecuteLocked = true
-	solution.MP.Points.Clear() // Empty Solution
+	defer func() { clip.opt.ExecuteLocked = false }()
 
-	err := clip.ExecuteInternal()
+	clip.subjFillType = subjFillType
+	clip.clipFillType = clipFillType
+	clip.clipType = clipType
+	clip.usingPolyTree = false
 
+	err := clip.ExecuteInternal()
 	if err != nil {
-		fmt.Println("Clip Failed:", err)
-		return err
+		clip.DisposeAllOutRecs()
+		return nil, err
 	}
-	clip.BuildResult(solution)
+	solution := clip.BuildResult()
+	clip.DisposeAllOutRecs()
+	return solution, nil
+}
 
-	clip.base.DisposeAllOutRecs()
-	clip.opt.ExecuteLocked = false
-	return nil
+// ExecutePolyTree will run the clip operation and return the result as a PolyTree
+// so that holes can be matched up with their outer contours
+func (clip *Clipper) ExecutePolyTree(clipType ClipType, subjFillType PolyFillType, clipFillType PolyFillType) (*PolyTree, error) {
+	if clip.opt.ExecuteLocked {
+		return nil, ErrExecuteLocked
+	}
+
+	clip.opt.ExecuteLocked = true
+	defer func() { clip.opt.ExecuteLocked = false }()
+
+	clip.subjFillType = subjFillType
+	clip.clipFillType = clipFillType
+	clip.clipType = clipType
+	clip.usingPolyTree = true
+
+	err := clip.ExecuteInternal()
+	if err != nil {
+		clip.DisposeAllOutRecs()
+		return nil, err
+	}
+	polytree := clip.BuildResult2()
+	clip.DisposeAllOutRecs()
+	return polytree, nil
 }
 
 func (clip *Clipper) FixHoleLinkage(outrec *OutRec) {
@@ -596,16 +958,1936 @@ func (clip *Clipper) FixHoleLinkage(outrec *OutRec) {
 	outrec.FirstLeft = orfl
 }
 
-func (clip *Clipper) ExecuteInternal() error{
-	clip.base.Reset()
-	Maxima := clip.MaximaList()
-	SortedEdges := 0
+func (clip *Clipper) ExecuteInternal() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			ce, ok := r.(clipperError)
+			if !ok {
+				panic(r)
+			}
+			clip.sortedEdges = nil
+			clip.intersectList = nil
+			err = ce
+		}
+		clip.joins = nil
+		clip.ghostJoins = nil
+	}()
+
+	clip.Reset()
+	clip.maxima = make([]float64, 0)
+	clip.sortedEdges = nil
+
+	botY, ok := clip.PopScanbeam()
+	if !ok {
+		return nil // nothing to clip
+	}
+	clip.InsertLocalMinimaIntoAEL(botY)
+
+	for {
+		topY, ok := clip.PopScanbeam()
+		if !ok && !clip.LocalMinimaPending() {
+			// flat open paths at the top of everything only reach the SEL once the
+			// last scanbeam is used up, so they still need processing
+			clip.ProcessHorizontals()
+			break
+		}
+		clip.ProcessHorizontals()
+		clip.ghostJoins = nil
+		if err := clip.ProcessIntersections(topY); err != nil {
+			return err
+		}
+		clip.ProcessEdgesAtTopOfScanbeam(topY)
+		botY = topY
+		clip.InsertLocalMinimaIntoAEL(botY)
+	}
+
+	// fix orientations ...
+	for _, outRec := range clip.polyOuts {
+		if outRec.Pts == nil || outRec.IsOpen {
+			continue
+		}
+		if (outRec.IsHole != clip.opt.ReverseOutput) == (outRec.Area() > 0) {
+			ReversePolyPtLinks(outRec.Pts)
+		}
+	}
+
+	if len(clip.joins) > 0 {
+		clip.JoinCommonEdges()
+	}
+
+	// unfortunately FixupOutPolygon() must be done after JoinCommonEdges()
+	for _, outRec := range clip.polyOuts {
+		if outRec.Pts == nil {
+			continue
+		}
+		if outRec.IsOpen {
+			clip.FixupOutPolyline(outRec)
+		} else {
+			clip.FixupOutPolygon(outRec)
+		}
+	}
+
+	if clip.opt.StrictSimple {
+		clip.DoSimplePolygons()
+	}
+	return nil
+}
+
+func (clip *Clipper) SetWindingCount(edge *TEdge) {
+	e := edge.PrevInAEL
+	// find the edge of the same polytype that immediately preceeds 'edge' in AEL
+	for e != nil && ((e.PolyType != edge.PolyType) || (e.WinDelta == 0)) {
+		e = e.PrevInAEL
+	}
+
+	if e == nil {
+		if edge.WinDelta == 0 {
+			pft := clip.clipFillType
+			if edge.PolyType == ptSubject {
+				pft = clip.subjFillType
+			}
+			if pft == pftNegative {
+				edge.WinCnt = -1
+			} else {
+				edge.WinCnt = 1
+			}
+		} else {
+			edge.WinCnt = edge.WinDelta
+		}
+		edge.WinCnt2 = 0
+		e = clip.activeEdges // ie get ready to calc WinCnt2
+	} else if edge.WinDelta == 0 && clip.clipType != ctUnion {
+		edge.WinCnt = 1
+		edge.WinCnt2 = e.WinCnt2
+		e = e.NextInAEL // ie get ready to calc WinCnt2
+	} else if clip.IsEvenOddFillType(edge) {
+		// EvenOdd filling ...
+		if edge.WinDelta == 0 {
+			// are we inside a subj polygon ...
+			inside := true
+			e2 := e.PrevInAEL
+			for e2 != nil {
+				if e2.PolyType == e.PolyType && e2.WinDelta != 0 {
+					inside = !inside
+				}
+				e2 = e2.PrevInAEL
+			}
+			if inside {
+				edge.WinCnt = 0
+			} else {
+				edge.WinCnt = 1
+			}
+		} else {
+			edge.WinCnt = edge.WinDelta
+		}
+		edge.WinCnt2 = e.WinCnt2
+		e = e.NextInAEL // ie get ready to calc WinCnt2
+	} else {
+		// nonZero, Positive or Negative filling ...
+		if e.WinCnt*e.WinDelta < 0 {
+			// prev edge is 'decreasing' WindCount (WC) toward zero
+			// so we're outside the previous polygon ...
+			if absInt(e.WinCnt) > 1 {
+				// outside prev poly but still inside another.
+				// when reversing direction of prev poly use the same WC
+				if e.WinDelta*edge.WinDelta < 0 {
+					edge.WinCnt = e.WinCnt
+				} else {
+					// otherwise continue to 'decrease' WC ...
+					edge.WinCnt = e.WinCnt + edge.WinDelta
+				}
+			} else {
+				// now outside all polys of same polytype so set own WC ...
+				if edge.WinDelta == 0 {
+					edge.WinCnt = 1
+				} else {
+					edge.WinCnt = edge.WinDelta
+				}
+			}
+		} else {
+			// prev edge is 'increasing' WindCount (WC) away from zero
+			// so we're inside the previous polygon ...
+			if edge.WinDelta == 0 {
+				if e.WinCnt < 0 {
+					edge.WinCnt = e.WinCnt - 1
+				} else {
+					edge.WinCnt = e.WinCnt + 1
+				}
+			} else if e.WinDelta*edge.WinDelta < 0 {
+				// if wind direction is reversing prev then use same WC
+				edge.WinCnt = e.WinCnt
+			} else {
+				// otherwise add to WC ...
+				edge.WinCnt = e.WinCnt + edge.WinDelta
+			}
+		}
+		edge.WinCnt2 = e.WinCnt2
+		e = e.NextInAEL // ie get ready to calc WinCnt2
+	}
+
+	// update WinCnt2 ...
+	if clip.IsEvenOddAltFillType(edge) {
+		// EvenOdd filling ...
+		for e != edge {
+			if e.WinDelta != 0 {
+				if edge.WinCnt2 == 0 {
+					edge.WinCnt2 = 1
+				} else {
+					edge.WinCnt2 = 0
+				}
+			}
+			e = e.NextInAEL
+		}
+	} else {
+		// nonZero, Positive or Negative filling ...
+		for e != edge {
+			edge.WinCnt2 += e.WinDelta
+			e = e.NextInAEL
+		}
+	}
+}
+
+func absInt(val int) int {
+	if val < 0 {
+		return -val
+	}
+	return val
+}
+
+func (clip *Clipper) IsEvenOddFillType(edge *TEdge) bool {
+	if edge.PolyType == ptSubject {
+		return clip.subjFillType == pftEvenOdd
+	}
+	return clip.clipFillType == pftEvenOdd
+}
+
+func (clip *Clipper) IsEvenOddAltFillType(edge *TEdge) bool {
+	if edge.PolyType == ptSubject {
+		return clip.clipFillType == pftEvenOdd
+	}
+	return clip.subjFillType == pftEvenOdd
+}
+
+func (clip *Clipper) IsContributing(edge *TEdge) bool {
+	pft, pft2 := clip.clipFillType, clip.subjFillType
+	if edge.PolyType == ptSubject {
+		pft, pft2 = clip.subjFillType, clip.clipFillType
+	}
 
-	botY, topY := 0, 0 
+	switch pft {
+	case pftEvenOdd:
+		// return false if a subj line has been flagged as inside a subj polygon
+		if edge.WinDelta == 0 && edge.WinCnt != 1 {
+			return false
+		}
+	case pftNonZero:
+		if absInt(edge.WinCnt) != 1 {
+			return false
+		}
+	case pftPositive:
+		if edge.WinCnt != 1 {
+			return false
+		}
+	default: // pftNegative
+		if edge.WinCnt != -1 {
+			return false
+		}
+	}
 
+	// outside tells if the edge is outside of the other polytype,
+	// inside tells if it is inside of it
+	outside := func() bool {
+		switch pft2 {
+		case pftEvenOdd, pftNonZero:
+			return edge.WinCnt2 == 0
+		case pftPositive:
+			return edge.WinCnt2 <= 0
+		}
+		return edge.WinCnt2 >= 0
+	}
+	inside := func() bool {
+		switch pft2 {
+		case pftEvenOdd, pftNonZero:
+			return edge.WinCnt2 != 0
+		case pftPositive:
+			return edge.WinCnt2 > 0
+		}
+		return edge.WinCnt2 < 0
+	}
 
-	if ! clip.base.PopScanbeam()
+	switch clip.clipType {
+	case ctIntersection:
+		return inside()
+	case ctUnion:
+		return outside()
+	case ctDifference:
+		if edge.PolyType == ptSubject {
+			return outside()
+		}
+		return inside()
+	case ctXor:
+		if edge.WinDelta == 0 { // XOr always contributing unless open
+			return outside()
+		}
+		return true
+	}
+	return true
 }
 
-// LINE 1560
-// continue https://github.com/slic3r/Slic3r/blob/master/xs/src/clipper.cpp
+func (clip *Clipper) AddLocalMinPoly(e1, e2 *TEdge, pt Point) *OutPt {
+	var result *OutPt
+	var e, prevE *TEdge
+	if IsHorizontal(e2) || (e1.Dx > e2.Dx) {
+		result = clip.AddOutPt(e1, pt)
+		e2.OutIdx = e1.OutIdx
+		e1.Side = esLeft
+		e2.Side = esRight
+		e = e1
+		if e.PrevInAEL == e2 {
+			prevE = e2.PrevInAEL
+		} else {
+			prevE = e.PrevInAEL
+		}
+	} else {
+		result = clip.AddOutPt(e2, pt)
+		e1.OutIdx = e2.OutIdx
+		e1.Side = esRight
+		e2.Side = esLeft
+		e = e2
+		if e.PrevInAEL == e1 {
+			prevE = e1.PrevInAEL
+		} else {
+			prevE = e.PrevInAEL
+		}
+	}
+
+	if prevE != nil && prevE.OutIdx >= 0 && prevE.Top.Y < pt.Y && e.Top.Y < pt.Y {
+		xPrev := prevE.TopX(pt.Y)
+		xE := e.TopX(pt.Y)
+		if xPrev == xE && (e.WinDelta != 0) && (prevE.WinDelta != 0) &&
+			SlopesEqual4Pt(NewPoint(xPrev, pt.Y), &prevE.Top, NewPoint(xE, pt.Y), &e.Top, clip.useFullRange) {
+			outPt := clip.AddOutPt(prevE, pt)
+			clip.AddJoin(result, outPt, e.Top)
+		}
+	}
+	return result
+}
+
+func (clip *Clipper) AddLocalMaxPoly(e1, e2 *TEdge, pt Point) {
+	clip.AddOutPt(e1, pt)
+	if e2.WinDelta == 0 {
+		clip.AddOutPt(e2, pt)
+	}
+	if e1.OutIdx == e2.OutIdx {
+		e1.OutIdx = Unassigned
+		e2.OutIdx = Unassigned
+	} else if e1.OutIdx < e2.OutIdx {
+		clip.AppendPolygon(e1, e2)
+	} else {
+		clip.AppendPolygon(e2, e1)
+	}
+}
+
+func (clip *Clipper) AddEdgeToSEL(edge *TEdge) {
+	// SEL pointers in PEdge are reused to build a list of horizontal edges.
+	// However, we don't need to worry about order with horizontal edge processing.
+	if clip.sortedEdges == nil {
+		clip.sortedEdges = edge
+		edge.PrevInSEL = nil
+		edge.NextInSEL = nil
+	} else {
+		edge.NextInSEL = clip.sortedEdges
+		edge.PrevInSEL = nil
+		clip.sortedEdges.PrevInSEL = edge
+		clip.sortedEdges = edge
+	}
+}
+
+func (clip *Clipper) PopEdgeFromSEL() (*TEdge, bool) {
+	if clip.sortedEdges == nil {
+		return nil, false
+	}
+	edge := clip.sortedEdges
+	clip.DeleteFromSEL(clip.sortedEdges)
+	return edge, true
+}
+
+func (clip *Clipper) CopyAELToSEL() {
+	e := clip.activeEdges
+	clip.sortedEdges = e
+	for e != nil {
+		e.PrevInSEL = e.PrevInAEL
+		e.NextInSEL = e.NextInAEL
+		e = e.NextInAEL
+	}
+}
+
+func (clip *Clipper) AddJoin(op1, op2 *OutPt, offPt Point) {
+	clip.joins = append(clip.joins, &Join{
+		OutPt1: op1,
+		OutPt2: op2,
+		OffPt:  offPt,
+	})
+}
+
+func (clip *Clipper) AddGhostJoin(op *OutPt, offPt Point) {
+	clip.ghostJoins = append(clip.ghostJoins, &Join{
+		OutPt1: op,
+		OutPt2: nil,
+		OffPt:  offPt,
+	})
+}
+
+func (clip *Clipper) InsertLocalMinimaIntoAEL(botY float64) {
+	for {
+		lm, ok := clip.PopLocalMinima(botY)
+		if !ok {
+			break
+		}
+		lb := lm.LeftBound
+		rb := lm.RightBound
+
+		var op1 *OutPt
+		if lb == nil {
+			// nb: don't insert LB into either AEL or SEL
+			clip.InsertEdgeIntoAEL(rb, nil)
+			clip.SetWindingCount(rb)
+			if clip.IsContributing(rb) {
+				op1 = clip.AddOutPt(rb, rb.Bot)
+			}
+		} else if rb == nil {
+			clip.InsertEdgeIntoAEL(lb, nil)
+			clip.SetWindingCount(lb)
+			if clip.IsContributing(lb) {
+				op1 = clip.AddOutPt(lb, lb.Bot)
+			}
+			clip.InsertScanbeam(lb.Top.Y)
+		} else {
+			clip.InsertEdgeIntoAEL(lb, nil)
+			clip.InsertEdgeIntoAEL(rb, lb)
+			clip.SetWindingCount(lb)
+			rb.WinCnt = lb.WinCnt
+			rb.WinCnt2 = lb.WinCnt2
+			if clip.IsContributing(lb) {
+				op1 = clip.AddLocalMinPoly(lb, rb, lb.Bot)
+			}
+			clip.InsertScanbeam(lb.Top.Y)
+		}
+
+		if rb != nil {
+			if IsHorizontal(rb) {
+				clip.AddEdgeToSEL(rb)
+				if rb.NextInLML != nil {
+					clip.InsertScanbeam(rb.NextInLML.Top.Y)
+				}
+			} else {
+				clip.InsertScanbeam(rb.Top.Y)
+			}
+		}
+
+		if lb == nil || rb == nil {
+			continue
+		}
+
+		// if any output polygons share an edge, they'll need joining later ...
+		if op1 != nil && IsHorizontal(rb) && len(clip.ghostJoins) > 0 && rb.WinDelta != 0 {
+			for _, jr := range clip.ghostJoins {
+				// if the horizontal Rb and a 'ghost' horizontal overlap, then convert
+				// the 'ghost' join to a real join ready for later ...
+				if HorzSegmentsOverlap(jr.OutPt1.Pt.X, jr.OffPt.X, rb.Bot.X, rb.Top.X) {
+					clip.AddJoin(jr.OutPt1, op1, jr.OffPt)
+				}
+			}
+		}
+
+		if lb.OutIdx >= 0 && lb.PrevInAEL != nil &&
+			lb.PrevInAEL.Curr.X == lb.Bot.X &&
+			lb.PrevInAEL.OutIdx >= 0 &&
+			SlopesEqual4Pt(&lb.PrevInAEL.Bot, &lb.PrevInAEL.Top, &lb.Curr, &lb.Top, clip.useFullRange) &&
+			lb.WinDelta != 0 && lb.PrevInAEL.WinDelta != 0 {
+			op2 := clip.AddOutPt(lb.PrevInAEL, lb.Bot)
+			clip.AddJoin(op1, op2, lb.Top)
+		}
+
+		if lb.NextInAEL != rb {
+			if rb.OutIdx >= 0 && rb.PrevInAEL.OutIdx >= 0 &&
+				SlopesEqual4Pt(&rb.PrevInAEL.Curr, &rb.PrevInAEL.Top, &rb.Curr, &rb.Top, clip.useFullRange) &&
+				rb.WinDelta != 0 && rb.PrevInAEL.WinDelta != 0 {
+				op2 := clip.AddOutPt(rb.PrevInAEL, rb.Bot)
+				clip.AddJoin(op1, op2, rb.Top)
+			}
+
+			e := lb.NextInAEL
+			if e != nil {
+				for e != rb {
+					//nb: For calculating winding counts etc, IntersectEdges() assumes
+					//that param1 will be to the Right of param2 ABOVE the intersection ...
+					clip.IntersectEdges(rb, e, lb.Curr) // order important here
+					e = e.NextInAEL
+				}
+			}
+		}
+	}
+}
+
+func (clip *Clipper) DeleteFromSEL(e *TEdge) {
+	selPrev := e.PrevInSEL
+	selNext := e.NextInSEL
+	if selPrev == nil && selNext == nil && e != clip.sortedEdges {
+		return // already deleted
+	}
+	if selPrev != nil {
+		selPrev.NextInSEL = selNext
+	} else {
+		clip.sortedEdges = selNext
+	}
+	if selNext != nil {
+		selNext.PrevInSEL = selPrev
+	}
+	e.NextInSEL = nil
+	e.PrevInSEL = nil
+}
+
+func (clip *Clipper) IntersectEdges(e1, e2 *TEdge, pt Point) {
+	e1Contributing := e1.OutIdx >= 0
+	e2Contributing := e2.OutIdx >= 0
+
+	// if either edge is on an OPEN path ...
+	if e1.WinDelta == 0 || e2.WinDelta == 0 {
+		//ignore subject-subject open path intersections UNLESS they
+		//are both open paths, AND they are both 'contributing maximas' ...
+		if e1.WinDelta == 0 && e2.WinDelta == 0 {
+			return
+		} else if e1.PolyType == e2.PolyType && e1.WinDelta != e2.WinDelta && clip.clipType == ctUnion {
+			// if intersecting a subj line with a subj poly ...
+			if e1.WinDelta == 0 {
+				if e2Contributing {
+					clip.AddOutPt(e1, pt)
+					if e1Contributing {
+						e1.OutIdx = Unassigned
+					}
+				}
+			} else {
+				if e1Contributing {
+					clip.AddOutPt(e2, pt)
+					if e2Contributing {
+						e2.OutIdx = Unassigned
+					}
+				}
+			}
+		} else if e1.PolyType != e2.PolyType {
+			// toggle subj open path OutIdx on/off when Abs(clip.WndCnt) == 1 ...
+			if e1.WinDelta == 0 && absInt(e2.WinCnt) == 1 &&
+				(clip.clipType != ctUnion || e2.WinCnt2 == 0) {
+				clip.AddOutPt(e1, pt)
+				if e1Contributing {
+					e1.OutIdx = Unassigned
+				}
+			} else if e2.WinDelta == 0 && absInt(e1.WinCnt) == 1 &&
+				(clip.clipType != ctUnion || e1.WinCnt2 == 0) {
+				clip.AddOutPt(e2, pt)
+				if e2Contributing {
+					e2.OutIdx = Unassigned
+				}
+			}
+		}
+		return
+	}
+
+	// update winding counts...
+	// assumes that e1 will be to the Right of e2 ABOVE the intersection
+	if e1.PolyType == e2.PolyType {
+		if clip.IsEvenOddFillType(e1) {
+			e1.WinCnt, e2.WinCnt = e2.WinCnt, e1.WinCnt
+		} else {
+			if e1.WinCnt+e2.WinDelta == 0 {
+				e1.WinCnt = -e1.WinCnt
+			} else {
+				e1.WinCnt += e2.WinDelta
+			}
+			if e2.WinCnt-e1.WinDelta == 0 {
+				e2.WinCnt = -e2.WinCnt
+			} else {
+				e2.WinCnt -= e1.WinDelta
+			}
+		}
+	} else {
+		if !clip.IsEvenOddFillType(e2) {
+			e1.WinCnt2 += e2.WinDelta
+		} else if e1.WinCnt2 == 0 {
+			e1.WinCnt2 = 1
+		} else {
+			e1.WinCnt2 = 0
+		}
+		if !clip.IsEvenOddFillType(e1) {
+			e2.WinCnt2 -= e1.WinDelta
+		} else if e2.WinCnt2 == 0 {
+			e2.WinCnt2 = 1
+		} else {
+			e2.WinCnt2 = 0
+		}
+	}
+
+	e1FillType, e1FillType2 := clip.clipFillType, clip.subjFillType
+	if e1.PolyType == ptSubject {
+		e1FillType, e1FillType2 = clip.subjFillType, clip.clipFillType
+	}
+	e2FillType, e2FillType2 := clip.clipFillType, clip.subjFillType
+	if e2.PolyType == ptSubject {
+		e2FillType, e2FillType2 = clip.subjFillType, clip.clipFillType
+	}
+
+	e1Wc := fillWindCount(e1FillType, e1.WinCnt)
+	e2Wc := fillWindCount(e2FillType, e2.WinCnt)
+
+	if e1Contributing && e2Contributing {
+		if (e1Wc != 0 && e1Wc != 1) || (e2Wc != 0 && e2Wc != 1) ||
+			(e1.PolyType != e2.PolyType && clip.clipType != ctXor) {
+			clip.AddLocalMaxPoly(e1, e2, pt)
+		} else {
+			clip.AddOutPt(e1, pt)
+			clip.AddOutPt(e2, pt)
+			SwapSides(e1, e2)
+			SwapPolyIndexes(e1, e2)
+		}
+	} else if e1Contributing {
+		if e2Wc == 0 || e2Wc == 1 {
+			clip.AddOutPt(e1, pt)
+			SwapSides(e1, e2)
+			SwapPolyIndexes(e1, e2)
+		}
+	} else if e2Contributing {
+		if e1Wc == 0 || e1Wc == 1 {
+			clip.AddOutPt(e2, pt)
+			SwapSides(e1, e2)
+			SwapPolyIndexes(e1, e2)
+		}
+	} else if (e1Wc == 0 || e1Wc == 1) && (e2Wc == 0 || e2Wc == 1) {
+		// neither edge is currently contributing ...
+		e1Wc2 := fillWindCount(e1FillType2, e1.WinCnt2)
+		e2Wc2 := fillWindCount(e2FillType2, e2.WinCnt2)
+
+		if e1.PolyType != e2.PolyType {
+			clip.AddLocalMinPoly(e1, e2, pt)
+		} else if e1Wc == 1 && e2Wc == 1 {
+			switch clip.clipType {
+			case ctIntersection:
+				if e1Wc2 > 0 && e2Wc2 > 0 {
+					clip.AddLocalMinPoly(e1, e2, pt)
+				}
+			case ctUnion:
+				if e1Wc2 <= 0 && e2Wc2 <= 0 {
+					clip.AddLocalMinPoly(e1, e2, pt)
+				}
+			case ctDifference:
+				if ((e1.PolyType == ptClip) && (e1Wc2 > 0) && (e2Wc2 > 0)) ||
+					((e1.PolyType == ptSubject) && (e1Wc2 <= 0) && (e2Wc2 <= 0)) {
+					clip.AddLocalMinPoly(e1, e2, pt)
+				}
+			case ctXor:
+				clip.AddLocalMinPoly(e1, e2, pt)
+			}
+		} else {
+			SwapSides(e1, e2)
+		}
+	}
+}
+
+// fillWindCount will normalize a winding count for the supplied fill type
+func fillWindCount(fillType PolyFillType, winCnt int) int {
+	switch fillType {
+	case pftPositive:
+		return winCnt
+	case pftNegative:
+		return -winCnt
+	}
+	return absInt(winCnt)
+}
+
+func (clip *Clipper) SetHoleState(e *TEdge, outrec *OutRec) {
+	e2 := e.PrevInAEL
+	var eTmp *TEdge
+	for e2 != nil {
+		if e2.OutIdx >= 0 && e2.WinDelta != 0 {
+			if eTmp == nil {
+				eTmp = e2
+			} else if eTmp.OutIdx == e2.OutIdx {
+				eTmp = nil
+			}
+		}
+		e2 = e2.PrevInAEL
+	}
+	if eTmp == nil {
+		outrec.FirstLeft = nil
+		outrec.IsHole = false
+	} else {
+		outrec.FirstLeft = clip.polyOuts[eTmp.OutIdx]
+		outrec.IsHole = !outrec.FirstLeft.IsHole
+	}
+}
+
+func (clip *Clipper) GetOutRec(idx int) *OutRec {
+	outrec := clip.polyOuts[idx]
+	for outrec != clip.polyOuts[outrec.Idx] {
+		outrec = clip.polyOuts[outrec.Idx]
+	}
+	return outrec
+}
+
+func (clip *Clipper) AppendPolygon(e1, e2 *TEdge) {
+	// get the start and ends of both output polygons ...
+	outRec1 := clip.polyOuts[e1.OutIdx]
+	outRec2 := clip.polyOuts[e2.OutIdx]
+
+	var holeStateRec *OutRec
+	if OutRec1RightOfOutRec2(outRec1, outRec2) {
+		holeStateRec = outRec2
+	} else if OutRec1RightOfOutRec2(outRec2, outRec1) {
+		holeStateRec = outRec1
+	} else {
+		holeStateRec = GetLowermostRec(outRec1, outRec2)
+	}
+
+	//get the start and ends of both output polygons and
+	//join e2 poly onto e1 poly and delete pointers to e2 ...
+	p1Lft := outRec1.Pts
+	p1Rt := p1Lft.Prev
+	p2Lft := outRec2.Pts
+	p2Rt := p2Lft.Prev
+
+	// join e2 poly onto e1 poly and delete pointers to e2 ...
+	if e1.Side == esLeft {
+		if e2.Side == esLeft {
+			// z y x a b c
+			ReversePolyPtLinks(p2Lft)
+			p2Lft.Next = p1Lft
+			p1Lft.Prev = p2Lft
+			p1Rt.Next = p2Rt
+			p2Rt.Prev = p1Rt
+			outRec1.Pts = p2Rt
+		} else {
+			// x y z a b c
+			p2Rt.Next = p1Lft
+			p1Lft.Prev = p2Rt
+			p2Lft.Prev = p1Rt
+			p1Rt.Next = p2Lft
+			outRec1.Pts = p2Lft
+		}
+	} else {
+		if e2.Side == esRight {
+			// a b c z y x
+			ReversePolyPtLinks(p2Lft)
+			p1Rt.Next = p2Rt
+			p2Rt.Prev = p1Rt
+			p2Lft.Next = p1Lft
+			p1Lft.Prev = p2Lft
+		} else {
+			// a b c x y z
+			p1Rt.Next = p2Lft
+			p2Lft.Prev = p1Rt
+			p1Lft.Prev = p2Rt
+			p2Rt.Next = p1Lft
+		}
+	}
+
+	outRec1.BottomPt = nil
+	if holeStateRec == outRec2 {
+		if outRec2.FirstLeft != outRec1 {
+			outRec1.FirstLeft = outRec2.FirstLeft
+		}
+		outRec1.IsHole = outRec2.IsHole
+	}
+	outRec2.Pts = nil
+	outRec2.BottomPt = nil
+	outRec2.FirstLeft = outRec1
+
+	okIdx := e1.OutIdx
+	obsoleteIdx := e2.OutIdx
+
+	e1.OutIdx = Unassigned // nb: safe because we only get here via AddLocalMaxPoly
+	e2.OutIdx = Unassigned
+
+	e := clip.activeEdges
+	for e != nil {
+		if e.OutIdx == obsoleteIdx {
+			e.OutIdx = okIdx
+			e.Side = e1.Side
+			break
+		}
+		e = e.NextInAEL
+	}
+
+	outRec2.Idx = outRec1.Idx
+}
+
+func (clip *Clipper) AddOutPt(e *TEdge, pt Point) *OutPt {
+	if e.OutIdx < 0 {
+		outRec := clip.CreateOutRec()
+		outRec.IsOpen = e.WinDelta == 0
+		newOp := new(OutPt)
+		outRec.Pts = newOp
+		newOp.Idx = outRec.Idx
+		newOp.Pt = NewPoint(pt.X, pt.Y)
+		newOp.Next = newOp
+		newOp.Prev = newOp
+		if !outRec.IsOpen {
+			clip.SetHoleState(e, outRec)
+		}
+		e.OutIdx = outRec.Idx
+		return newOp
+	}
+
+	outRec := clip.polyOuts[e.OutIdx]
+	// OutRec.Pts is the 'Left-most' point & OutRec.Pts.Prev is the 'Right-most'
+	op := outRec.Pts
+
+	toFront := e.Side == esLeft
+	if toFront && pt == *op.Pt {
+		return op
+	} else if !toFront && pt == *op.Prev.Pt {
+		return op.Prev
+	}
+
+	newOp := new(OutPt)
+	newOp.Idx = outRec.Idx
+	newOp.Pt = NewPoint(pt.X, pt.Y)
+	newOp.Next = op
+	newOp.Prev = op.Prev
+	newOp.Prev.Next = newOp
+	op.Prev = newOp
+	if toFront {
+		outRec.Pts = newOp
+	}
+	return newOp
+}
+
+func (clip *Clipper) GetLastOutPt(e *TEdge) *OutPt {
+	outRec := clip.polyOuts[e.OutIdx]
+	if e.Side == esLeft {
+		return outRec.Pts
+	}
+	return outRec.Pts.Prev
+}
+
+func (clip *Clipper) ProcessHorizontals() {
+	for {
+		horzEdge, ok := clip.PopEdgeFromSEL()
+		if !ok {
+			break
+		}
+		clip.ProcessHorizontal(horzEdge)
+	}
+}
+
+func (clip *Clipper) SwapPositionsInSEL(edge1, edge2 *TEdge) {
+	if edge1.NextInSEL == nil && edge1.PrevInSEL == nil {
+		return
+	}
+	if edge2.NextInSEL == nil && edge2.PrevInSEL == nil {
+		return
+	}
+
+	if edge1.NextInSEL == edge2 {
+		next := edge2.NextInSEL
+		if next != nil {
+			next.PrevInSEL = edge1
+		}
+		prev := edge1.PrevInSEL
+		if prev != nil {
+			prev.NextInSEL = edge2
+		}
+		edge2.PrevInSEL = prev
+		edge2.NextInSEL = edge1
+		edge1.PrevInSEL = edge2
+		edge1.NextInSEL = next
+	} else if edge2.NextInSEL == edge1 {
+		next := edge1.NextInSEL
+		if next != nil {
+			next.PrevInSEL = edge2
+		}
+		prev := edge2.PrevInSEL
+		if prev != nil {
+			prev.NextInSEL = edge1
+		}
+		edge1.PrevInSEL = prev
+		edge1.NextInSEL = edge2
+		edge2.PrevInSEL = edge1
+		edge2.NextInSEL = next
+	} else {
+		next := edge1.NextInSEL
+		prev := edge1.PrevInSEL
+		edge1.NextInSEL = edge2.NextInSEL
+		if edge1.NextInSEL != nil {
+			edge1.NextInSEL.PrevInSEL = edge1
+		}
+		edge1.PrevInSEL = edge2.PrevInSEL
+		if edge1.PrevInSEL != nil {
+			edge1.PrevInSEL.NextInSEL = edge1
+		}
+		edge2.NextInSEL = next
+		if edge2.NextInSEL != nil {
+			edge2.NextInSEL.PrevInSEL = edge2
+		}
+		edge2.PrevInSEL = prev
+		if edge2.PrevInSEL != nil {
+			edge2.PrevInSEL.NextInSEL = edge2
+		}
+	}
+
+	if edge1.PrevInSEL == nil {
+		clip.sortedEdges = edge1
+	} else if edge2.PrevInSEL == nil {
+		clip.sortedEdges = edge2
+	}
+}
+
+// ProcessHorizontal will process a horizontal edge.
+//
+// Notes: Horizontal edges (HEs) at scanline intersections (ie at the Top or
+// Bottom of a scanbeam) are processed as if layered. The order in which HEs
+// are processed doesn't matter. HEs intersect with other HE Bot.Xs only [#]
+// (or they could intersect with Top.Xs only, ie EITHER Bot.Xs OR Top.Xs),
+// and with other non-horizontal edges [*]. Once these intersections are
+// processed, intermediate HEs then 'promote' the Edge above (NextInLML) into
+// the AEL. These 'promoted' edges may in turn intersect [%] with other HEs.
+func (clip *Clipper) ProcessHorizontal(horzEdge *TEdge) {
+	isOpen := horzEdge.WinDelta == 0
+
+	dir, horzLeft, horzRight := GetHorzDirection(horzEdge)
+
+	eLastHorz := horzEdge
+	var eMaxPair *TEdge
+	for eLastHorz.NextInLML != nil && IsHorizontal(eLastHorz.NextInLML) {
+		eLastHorz = eLastHorz.NextInLML
+	}
+	if eLastHorz.NextInLML == nil {
+		eMaxPair = GetMaximaPair(eLastHorz)
+	}
+
+	// maxIdx walks the (sorted) maxima list in the direction of the horizontal
+	maxIdx := -1
+	if len(clip.maxima) > 0 {
+		// get the first maxima in range (X) ...
+		if dir == dLeftToRight {
+			maxIdx = 0
+			for maxIdx < len(clip.maxima) && clip.maxima[maxIdx] <= horzEdge.Bot.X {
+				maxIdx++
+			}
+			if maxIdx < len(clip.maxima) && clip.maxima[maxIdx] >= eLastHorz.Top.X {
+				maxIdx = len(clip.maxima)
+			}
+		} else {
+			maxIdx = len(clip.maxima) - 1
+			for maxIdx >= 0 && clip.maxima[maxIdx] > horzEdge.Bot.X {
+				maxIdx--
+			}
+			if maxIdx >= 0 && clip.maxima[maxIdx] <= eLastHorz.Top.X {
+				maxIdx = -1
+			}
+		}
+	}
+
+	var op1 *OutPt
+
+	for { // loop through consec. horizontal edges
+		isLastHorz := horzEdge == eLastHorz
+		e := GetNextInAEL(horzEdge, dir)
+		for e != nil {
+			//this code block inserts extra coords into horizontal edges (in output
+			//polygons) whereever maxima touch these horizontal edges. This helps
+			//'simplifying' polygons (ie if the Simplify property is set).
+			if len(clip.maxima) > 0 {
+				if dir == dLeftToRight {
+					for maxIdx < len(clip.maxima) && clip.maxima[maxIdx] < e.Curr.X {
+						if horzEdge.OutIdx >= 0 && !isOpen {
+							clip.AddOutPt(horzEdge, Point{X: clip.maxima[maxIdx], Y: horzEdge.Bot.Y})
+						}
+						maxIdx++
+					}
+				} else {
+					for maxIdx >= 0 && clip.maxima[maxIdx] > e.Curr.X {
+						if horzEdge.OutIdx >= 0 && !isOpen {
+							clip.AddOutPt(horzEdge, Point{X: clip.maxima[maxIdx], Y: horzEdge.Bot.Y})
+						}
+						maxIdx--
+					}
+				}
+			}
+
+			if (dir == dLeftToRight && e.Curr.X > horzRight) ||
+				(dir == dRightToLeft && e.Curr.X < horzLeft) {
+				break
+			}
+
+			//Also break if we've got to the end of an intermediate horizontal edge ...
+			//nb: Smaller Dx's are to the right of larger Dx's ABOVE the horizontal.
+			if e.Curr.X == horzEdge.Top.X && horzEdge.NextInLML != nil &&
+				e.Dx < horzEdge.NextInLML.Dx {
+				break
+			}
+
+			if horzEdge.OutIdx >= 0 && !isOpen { // note: may be done multiple times
+				op1 = clip.AddOutPt(horzEdge, e.Curr)
+				eNextHorz := clip.sortedEdges
+				for eNextHorz != nil {
+					if eNextHorz.OutIdx >= 0 &&
+						HorzSegmentsOverlap(horzEdge.Bot.X, horzEdge.Top.X, eNextHorz.Bot.X, eNextHorz.Top.X) {
+						op2 := clip.GetLastOutPt(eNextHorz)
+						clip.AddJoin(op2, op1, eNextHorz.Top)
+					}
+					eNextHorz = eNextHorz.NextInSEL
+				}
+				clip.AddGhostJoin(op1, horzEdge.Bot)
+			}
+
+			//OK, so far we're still in range of the horizontal Edge  but make sure
+			//we're at the last of consec. horizontals when matching with eMaxPair
+			if e == eMaxPair && isLastHorz {
+				if horzEdge.OutIdx >= 0 {
+					clip.AddLocalMaxPoly(horzEdge, eMaxPair, horzEdge.Top)
+				}
+				clip.DeleteFromAEL(horzEdge)
+				clip.DeleteFromAEL(eMaxPair)
+				return
+			}
+
+			pt := Point{X: e.Curr.X, Y: horzEdge.Curr.Y}
+			if dir == dLeftToRight {
+				clip.IntersectEdges(horzEdge, e, pt)
+			} else {
+				clip.IntersectEdges(e, horzEdge, pt)
+			}
+			eNext := GetNextInAEL(e, dir)
+			clip.SwapPositionsInAEL(horzEdge, e)
+			e = eNext
+		}
+
+		// Break out of loop if HorzEdge.NextInLML is not also horizontal ...
+		if horzEdge.NextInLML == nil || !IsHorizontal(horzEdge.NextInLML) {
+			break
+		}
+
+		horzEdge = clip.UpdateEdgeIntoAEL(horzEdge)
+		if horzEdge.OutIdx >= 0 {
+			clip.AddOutPt(horzEdge, horzEdge.Bot)
+		}
+		dir, horzLeft, horzRight = GetHorzDirection(horzEdge)
+	}
+
+	if horzEdge.OutIdx >= 0 && op1 == nil {
+		op1 = clip.GetLastOutPt(horzEdge)
+		eNextHorz := clip.sortedEdges
+		for eNextHorz != nil {
+			if eNextHorz.OutIdx >= 0 &&
+				HorzSegmentsOverlap(horzEdge.Bot.X, horzEdge.Top.X, eNextHorz.Bot.X, eNextHorz.Top.X) {
+				op2 := clip.GetLastOutPt(eNextHorz)
+				clip.AddJoin(op2, op1, eNextHorz.Top)
+			}
+			eNextHorz = eNextHorz.NextInSEL
+		}
+		clip.AddGhostJoin(op1, horzEdge.Top)
+	}
+
+	if horzEdge.NextInLML != nil {
+		if horzEdge.OutIdx >= 0 {
+			op1 = clip.AddOutPt(horzEdge, horzEdge.Top)
+			horzEdge = clip.UpdateEdgeIntoAEL(horzEdge)
+			if horzEdge.WinDelta == 0 {
+				return
+			}
+			// nb: HorzEdge is no longer horizontal here
+			ePrev := horzEdge.PrevInAEL
+			eNext := horzEdge.NextInAEL
+			if ePrev != nil && ePrev.Curr.X == horzEdge.Bot.X &&
+				ePrev.Curr.Y == horzEdge.Bot.Y && ePrev.WinDelta != 0 &&
+				(ePrev.OutIdx >= 0 && ePrev.Curr.Y > ePrev.Top.Y &&
+					SlopesEqual(horzEdge, ePrev, clip.useFullRange)) {
+				op2 := clip.AddOutPt(ePrev, horzEdge.Bot)
+				clip.AddJoin(op1, op2, horzEdge.Top)
+			} else if eNext != nil && eNext.Curr.X == horzEdge.Bot.X &&
+				eNext.Curr.Y == horzEdge.Bot.Y && eNext.WinDelta != 0 &&
+				eNext.OutIdx >= 0 && eNext.Curr.Y > eNext.Top.Y &&
+				SlopesEqual(horzEdge, eNext, clip.useFullRange) {
+				op2 := clip.AddOutPt(eNext, horzEdge.Bot)
+				clip.AddJoin(op1, op2, horzEdge.Top)
+			}
+		} else {
+			clip.UpdateEdgeIntoAEL(horzEdge)
+		}
+	} else {
+		if horzEdge.OutIdx >= 0 {
+			clip.AddOutPt(horzEdge, horzEdge.Top)
+		}
+		clip.DeleteFromAEL(horzEdge)
+	}
+}
+
+func (clip *Clipper) ProcessIntersections(topY float64) error {
+	if clip.activeEdges == nil {
+		return nil
+	}
+
+	clip.BuildIntersectList(topY)
+	if len(clip.intersectList) == 0 {
+		return nil
+	}
+	if len(clip.intersectList) != 1 && !clip.FixupIntersectionOrder() {
+		clip.intersectList = nil
+		return clipperError("ProcessIntersections error")
+	}
+	clip.ProcessIntersectList()
+	clip.sortedEdges = nil
+	return nil
+}
+
+func (clip *Clipper) BuildIntersectList(topY float64) {
+	if clip.activeEdges == nil {
+		return
+	}
+
+	// prepare for sorting ...
+	e := clip.activeEdges
+	clip.sortedEdges = e
+	for e != nil {
+		e.PrevInSEL = e.PrevInAEL
+		e.NextInSEL = e.NextInAEL
+		e.Curr.X = e.TopX(topY)
+		e = e.NextInAEL
+	}
+
+	// bubblesort ...
+	isModified := true
+	for isModified && clip.sortedEdges != nil {
+		isModified = false
+		e = clip.sortedEdges
+		for e.NextInSEL != nil {
+			eNext := e.NextInSEL
+			if e.Curr.X > eNext.Curr.X {
+				pt := IntersectPoint(e, eNext)
+				if pt.Y < topY {
+					pt = Point{X: e.TopX(topY), Y: topY}
+				}
+				clip.intersectList = append(clip.intersectList, &IntersectNode{
+					Edge1: e,
+					Edge2: eNext,
+					Pt:    pt,
+				})
+
+				clip.SwapPositionsInSEL(e, eNext)
+				isModified = true
+			} else {
+				e = eNext
+			}
+		}
+		if e.PrevInSEL != nil {
+			e.PrevInSEL.NextInSEL = nil
+		} else {
+			break
+		}
+	}
+	clip.sortedEdges = nil // important
+}
+
+func (clip *Clipper) ProcessIntersectList() {
+	for _, iNode := range clip.intersectList {
+		clip.IntersectEdges(iNode.Edge1, iNode.Edge2, iNode.Pt)
+		clip.SwapPositionsInAEL(iNode.Edge1, iNode.Edge2)
+	}
+	clip.intersectList = nil
+}
+
+func EdgesAdjacent(inode *IntersectNode) bool {
+	return (inode.Edge1.NextInSEL == inode.Edge2) ||
+		(inode.Edge1.PrevInSEL == inode.Edge2)
+}
+
+func (clip *Clipper) FixupIntersectionOrder() bool {
+	//pre-condition: intersections are sorted Bottom-most first.
+	//Now it's crucial that intersections are made only between adjacent edges,
+	//so to ensure this the order of intersections may need adjusting ...
+	clip.CopyAELToSEL()
+	sort.SliceStable(clip.intersectList, func(i, j int) bool {
+		return clip.intersectList[j].Pt.Y < clip.intersectList[i].Pt.Y
+	})
+
+	cnt := len(clip.intersectList)
+	for i := 0; i < cnt; i++ {
+		if !EdgesAdjacent(clip.intersectList[i]) {
+			j := i + 1
+			for j < cnt && !EdgesAdjacent(clip.intersectList[j]) {
+				j++
+			}
+			if j == cnt {
+				return false
+			}
+			clip.intersectList[i], clip.intersectList[j] = clip.intersectList[j], clip.intersectList[i]
+		}
+		clip.SwapPositionsInSEL(clip.intersectList[i].Edge1, clip.intersectList[i].Edge2)
+	}
+	return true
+}
+
+func (clip *Clipper) DoMaxima(e *TEdge) {
+	eMaxPair := GetMaximaPairEx(e)
+	if eMaxPair == nil {
+		if e.OutIdx >= 0 {
+			clip.AddOutPt(e, e.Top)
+		}
+		clip.DeleteFromAEL(e)
+		return
+	}
+
+	eNext := e.NextInAEL
+	for eNext != nil && eNext != eMaxPair {
+		clip.IntersectEdges(e, eNext, e.Top)
+		clip.SwapPositionsInAEL(e, eNext)
+		eNext = e.NextInAEL
+	}
+
+	if e.OutIdx == Unassigned && eMaxPair.OutIdx == Unassigned {
+		clip.DeleteFromAEL(e)
+		clip.DeleteFromAEL(eMaxPair)
+	} else if e.OutIdx >= 0 && eMaxPair.OutIdx >= 0 {
+		clip.AddLocalMaxPoly(e, eMaxPair, e.Top)
+		clip.DeleteFromAEL(e)
+		clip.DeleteFromAEL(eMaxPair)
+	} else if e.WinDelta == 0 {
+		if e.OutIdx >= 0 {
+			clip.AddOutPt(e, e.Top)
+			e.OutIdx = Unassigned
+		}
+		clip.DeleteFromAEL(e)
+
+		if eMaxPair.OutIdx >= 0 {
+			clip.AddOutPt(eMaxPair, e.Top)
+			eMaxPair.OutIdx = Unassigned
+		}
+		clip.DeleteFromAEL(eMaxPair)
+	} else {
+		panic(clipperError("DoMaxima error"))
+	}
+}
+
+func (clip *Clipper) ProcessEdgesAtTopOfScanbeam(topY float64) {
+	e := clip.activeEdges
+	for e != nil {
+		//1. process maxima, treating them as if they're 'bent' horizontal edges,
+		//   but exclude maxima with horizontal edges. nb: e can't be a horizontal.
+		isMaximaEdge := IsMaxima(e, topY)
+
+		if isMaximaEdge {
+			eMaxPair := GetMaximaPairEx(e)
+			isMaximaEdge = eMaxPair == nil || !IsHorizontal(eMaxPair)
+		}
+
+		if isMaximaEdge {
+			if clip.opt.StrictSimple {
+				clip.maxima = append(clip.maxima, e.Top.X)
+			}
+			ePrev := e.PrevInAEL
+			clip.DoMaxima(e)
+			if ePrev == nil {
+				e = clip.activeEdges
+			} else {
+				e = ePrev.NextInAEL
+			}
+			continue
+		}
+
+		//2. promote horizontal edges, otherwise update Curr.X and Curr.Y ...
+		if IsIntermediate(e, topY) && IsHorizontal(e.NextInLML) {
+			e = clip.UpdateEdgeIntoAEL(e)
+			if e.OutIdx >= 0 {
+				clip.AddOutPt(e, e.Bot)
+			}
+			clip.AddEdgeToSEL(e)
+		} else {
+			e.Curr.X = e.TopX(topY)
+			e.Curr.Y = topY
+		}
+
+		//When StrictlySimple and 'e' is being touched by another edge, then
+		//make sure both edges have a vertex here ...
+		if clip.opt.StrictSimple {
+			ePrev := e.PrevInAEL
+			if e.OutIdx >= 0 && e.WinDelta != 0 && ePrev != nil && ePrev.OutIdx >= 0 &&
+				ePrev.Curr.X == e.Curr.X && ePrev.WinDelta != 0 {
+				pt := e.Curr
+				op := clip.AddOutPt(ePrev, pt)
+				op2 := clip.AddOutPt(e, pt)
+				clip.AddJoin(op, op2, pt) // StrictlySimple (type-3) join
+			}
+		}
+
+		e = e.NextInAEL
+	}
+
+	//3. Process horizontals at the Top of the scanbeam ...
+	sort.Float64s(clip.maxima)
+	clip.ProcessHorizontals()
+	clip.maxima = clip.maxima[:0]
+
+	//4. Promote intermediate vertices ...
+	e = clip.activeEdges
+	for e != nil {
+		if IsIntermediate(e, topY) {
+			var op *OutPt
+			if e.OutIdx >= 0 {
+				op = clip.AddOutPt(e, e.Top)
+			}
+			e = clip.UpdateEdgeIntoAEL(e)
+
+			// if output polygons share an edge, they'll need joining later ...
+			ePrev := e.PrevInAEL
+			eNext := e.NextInAEL
+			if ePrev != nil && ePrev.Curr.X == e.Bot.X &&
+				ePrev.Curr.Y == e.Bot.Y && op != nil &&
+				ePrev.OutIdx >= 0 && ePrev.Curr.Y > ePrev.Top.Y &&
+				SlopesEqual4Pt(&e.Curr, &e.Top, &ePrev.Curr, &ePrev.Top, clip.useFullRange) &&
+				e.WinDelta != 0 && ePrev.WinDelta != 0 {
+				op2 := clip.AddOutPt(ePrev, e.Bot)
+				clip.AddJoin(op, op2, e.Top)
+			} else if eNext != nil && eNext.Curr.X == e.Bot.X &&
+				eNext.Curr.Y == e.Bot.Y && op != nil &&
+				eNext.OutIdx >= 0 && eNext.Curr.Y > eNext.Top.Y &&
+				SlopesEqual4Pt(&e.Curr, &e.Top, &eNext.Curr, &eNext.Top, clip.useFullRange) &&
+				e.WinDelta != 0 && eNext.WinDelta != 0 {
+				op2 := clip.AddOutPt(eNext, e.Bot)
+				clip.AddJoin(op, op2, e.Top)
+			}
+		}
+		e = e.NextInAEL
+	}
+}
+
+func (clip *Clipper) FixupOutPolyline(outrec *OutRec) {
+	pp := outrec.Pts
+	lastPP := pp.Prev
+	for pp != lastPP {
+		pp = pp.Next
+		if EqualPoints(pp.Pt, pp.Prev.Pt) {
+			if pp == lastPP {
+				lastPP = pp.Prev
+			}
+			tmpPP := pp.Prev
+			tmpPP.Next = pp.Next
+			pp.Next.Prev = tmpPP
+			pp = tmpPP
+		}
+	}
+
+	if pp == pp.Prev {
+		DisposeOutPts(pp)
+		outrec.Pts = nil
+	}
+}
+
+// FixupOutPolygon removes duplicate points and simplifies consecutive
+// parallel edges by removing the middle vertex.
+func (clip *Clipper) FixupOutPolygon(outrec *OutRec) {
+	var lastOK *OutPt
+	outrec.BottomPt = nil
+	pp := outrec.Pts
+	preserveCol := clip.preserveCollinear || clip.opt.StrictSimple
+
+	for {
+		if pp.Prev == pp || pp.Prev == pp.Next {
+			DisposeOutPts(pp)
+			outrec.Pts = nil
+			return
+		}
+
+		// test for duplicate points and collinear edges ...
+		if EqualPoints(pp.Pt, pp.Next.Pt) || EqualPoints(pp.Pt, pp.Prev.Pt) ||
+			(SlopesEqual3Pt(pp.Prev.Pt, pp.Pt, pp.Next.Pt, clip.useFullRange) &&
+				(!preserveCol || !Pt2IsBetweenPt1AndPt3(pp.Prev.Pt, pp.Pt, pp.Next.Pt))) {
+			lastOK = nil
+			pp.Prev.Next = pp.Next
+			pp.Next.Prev = pp.Prev
+			pp = pp.Prev
+		} else if pp == lastOK {
+			break
+		} else {
+			if lastOK == nil {
+				lastOK = pp
+			}
+			pp = pp.Next
+		}
+	}
+	outrec.Pts = pp
+}
+
+// BuildResult will collect the output polygons
+func (clip *Clipper) BuildResult() Polygons {
+	polys := make(Polygons, 0, len(clip.polyOuts))
+	for _, outRec := range clip.polyOuts {
+		if outRec.Pts == nil {
+			continue
+		}
+		p := outRec.Pts.Prev
+		cnt := PointCount(p)
+		if cnt < 2 {
+			continue
+		}
+		poly := NewPolygon()
+		for i := 0; i < cnt; i++ {
+			poly.Push(NewPoint(p.Pt.X, p.Pt.Y))
+			p = p.Prev
+		}
+		polys.Push(poly)
+	}
+	return polys
+}
+
+// BuildResult2 will collect the output polygons into a PolyTree
+func (clip *Clipper) BuildResult2() *PolyTree {
+	polytree := NewPolyTree()
+
+	// add each output polygon/contour to polytree ...
+	for _, outRec := range clip.polyOuts {
+		cnt := PointCount(outRec.Pts)
+		if (outRec.IsOpen && cnt < 2) || (!outRec.IsOpen && cnt < 3) {
+			continue
+		}
+		clip.FixHoleLinkage(outRec)
+		pn := NewPolyNode()
+		polytree.AllNodes = append(polytree.AllNodes, pn)
+		outRec.PolyNd = pn
+		op := outRec.Pts.Prev
+		for j := 0; j < cnt; j++ {
+			pn.Contour.Push(NewPoint(op.Pt.X, op.Pt.Y))
+			op = op.Prev
+		}
+	}
+
+	// fixup PolyNode links etc ...
+	for _, outRec := range clip.polyOuts {
+		if outRec.PolyNd == nil {
+			continue
+		}
+		if outRec.IsOpen {
+			outRec.PolyNd.isOpen = true
+			polytree.AddChild(outRec.PolyNd)
+		} else if outRec.FirstLeft != nil && outRec.FirstLeft.PolyNd != nil {
+			outRec.FirstLeft.PolyNd.AddChild(outRec.PolyNd)
+		} else {
+			polytree.AddChild(outRec.PolyNd)
+		}
+	}
+	return polytree
+}
+
+func (clip *Clipper) InsertEdgeIntoAEL(edge, startEdge *TEdge) {
+	if clip.activeEdges == nil {
+		edge.PrevInAEL = nil
+		edge.NextInAEL = nil
+		clip.activeEdges = edge
+	} else if startEdge == nil && E2InsertsBeforeE1(clip.activeEdges, edge) {
+		edge.PrevInAEL = nil
+		edge.NextInAEL = clip.activeEdges
+		clip.activeEdges.PrevInAEL = edge
+		clip.activeEdges = edge
+	} else {
+		if startEdge == nil {
+			startEdge = clip.activeEdges
+		}
+		for startEdge.NextInAEL != nil && !E2InsertsBeforeE1(startEdge.NextInAEL, edge) {
+			startEdge = startEdge.NextInAEL
+		}
+		edge.NextInAEL = startEdge.NextInAEL
+		if startEdge.NextInAEL != nil {
+			startEdge.NextInAEL.PrevInAEL = edge
+		}
+		edge.PrevInAEL = startEdge
+		startEdge.NextInAEL = edge
+	}
+}
+
+func (clip *Clipper) JoinPoints(j *Join, outRec1, outRec2 *OutRec) bool {
+	op1 := j.OutPt1
+	op2 := j.OutPt2
+	var op1b, op2b *OutPt
+
+	//There are 3 kinds of joins for output polygons ...
+	//1. Horizontal joins where Join.OutPt1 & Join.OutPt2 are vertices anywhere
+	//along (horizontal) collinear edges (& Join.OffPt is on the same horizontal).
+	//2. Non-horizontal joins where Join.OutPt1 & Join.OutPt2 are at the same
+	//location at the Bottom of the overlapping segment (& Join.OffPt is above).
+	//3. StrictSimple joins where edges touch but are not collinear and where
+	//Join.OutPt1, Join.OutPt2 & Join.OffPt all share the same point.
+	isHorizontal := j.OutPt1.Pt.Y == j.OffPt.Y
+
+	if isHorizontal && j.OffPt == *j.OutPt1.Pt && j.OffPt == *j.OutPt2.Pt {
+		// Strictly Simple join ...
+		if outRec1 != outRec2 {
+			return false
+		}
+		op1b = j.OutPt1.Next
+		for op1b != op1 && *op1b.Pt == j.OffPt {
+			op1b = op1b.Next
+		}
+		reverse1 := op1b.Pt.Y > j.OffPt.Y
+		op2b = j.OutPt2.Next
+		for op2b != op2 && *op2b.Pt == j.OffPt {
+			op2b = op2b.Next
+		}
+		reverse2 := op2b.Pt.Y > j.OffPt.Y
+		if reverse1 == reverse2 {
+			return false
+		}
+		if reverse1 {
+			op1b = DupOutPt(op1, false)
+			op2b = DupOutPt(op2, true)
+			op1.Prev = op2
+			op2.Next = op1
+			op1b.Next = op2b
+			op2b.Prev = op1b
+			j.OutPt1 = op1
+			j.OutPt2 = op1b
+			return true
+		}
+		op1b = DupOutPt(op1, true)
+		op2b = DupOutPt(op2, false)
+		op1.Next = op2
+		op2.Prev = op1
+		op1b.Prev = op2b
+		op2b.Next = op1b
+		j.OutPt1 = op1
+		j.OutPt2 = op1b
+		return true
+	} else if isHorizontal {
+		//treat horizontal joins differently to non-horizontal joins since with
+		//them we're not yet sure where the overlapping is. OutPt1.Pt & OutPt2.Pt
+		//may be anywhere along the horizontal edge.
+		op1b = op1
+		for op1.Prev.Pt.Y == op1.Pt.Y && op1.Prev != op1b && op1.Prev != op2 {
+			op1 = op1.Prev
+		}
+		for op1b.Next.Pt.Y == op1b.Pt.Y && op1b.Next != op1 && op1b.Next != op2 {
+			op1b = op1b.Next
+		}
+		if op1b.Next == op1 || op1b.Next == op2 {
+			return false // a flat 'polygon'
+		}
+
+		op2b = op2
+		for op2.Prev.Pt.Y == op2.Pt.Y && op2.Prev != op2b && op2.Prev != op1b {
+			op2 = op2.Prev
+		}
+		for op2b.Next.Pt.Y == op2b.Pt.Y && op2b.Next != op2 && op2b.Next != op1 {
+			op2b = op2b.Next
+		}
+		if op2b.Next == op2 || op2b.Next == op1 {
+			return false // a flat 'polygon'
+		}
+
+		// Op1 --> Op1b & Op2 --> Op2b are the extremites of the horizontal edges
+		overlap, left, right := GetOverlap(op1.Pt.X, op1b.Pt.X, op2.Pt.X, op2b.Pt.X)
+		if !overlap {
+			return false
+		}
+
+		//DiscardLeftSide: when overlapping edges are joined, a spike will created
+		//which needs to be cleaned up. However, we don't want Op1 or Op2 caught up
+		//on the discard Side as either may still be needed for other joins ...
+		var pt Point
+		var discardLeftSide bool
+		if op1.Pt.X >= left && op1.Pt.X <= right {
+			pt = *op1.Pt
+			discardLeftSide = op1.Pt.X > op1b.Pt.X
+		} else if op2.Pt.X >= left && op2.Pt.X <= right {
+			pt = *op2.Pt
+			discardLeftSide = op2.Pt.X > op2b.Pt.X
+		} else if op1b.Pt.X >= left && op1b.Pt.X <= right {
+			pt = *op1b.Pt
+			discardLeftSide = op1b.Pt.X > op1.Pt.X
+		} else {
+			pt = *op2b.Pt
+			discardLeftSide = op2b.Pt.X > op2.Pt.X
+		}
+		j.OutPt1 = op1
+		j.OutPt2 = op2
+		return JoinHorz(op1, op1b, op2, op2b, pt, discardLeftSide)
+	}
+
+	//nb: For non-horizontal joins ...
+	//    1. Jr.OutPt1.Pt.Y == Jr.OutPt2.Pt.Y
+	//    2. Jr.OutPt1.Pt > Jr.OffPt.Y
+
+	// make sure the polygons are correctly oriented ...
+	op1b = op1.Next
+	for EqualPoints(op1b.Pt, op1.Pt) && op1b != op1 {
+		op1b = op1b.Next
+	}
+	reverse1 := op1b.Pt.Y > op1.Pt.Y ||
+		!SlopesEqual3Pt(op1.Pt, op1b.Pt, &j.OffPt, clip.useFullRange)
+	if reverse1 {
+		op1b = op1.Prev
+		for EqualPoints(op1b.Pt, op1.Pt) && op1b != op1 {
+			op1b = op1b.Prev
+		}
+		if op1b.Pt.Y > op1.Pt.Y ||
+			!SlopesEqual3Pt(op1.Pt, op1b.Pt, &j.OffPt, clip.useFullRange) {
+			return false
+		}
+	}
+	op2b = op2.Next
+	for EqualPoints(op2b.Pt, op2.Pt) && op2b != op2 {
+		op2b = op2b.Next
+	}
+	reverse2 := op2b.Pt.Y > op2.Pt.Y ||
+		!SlopesEqual3Pt(op2.Pt, op2b.Pt, &j.OffPt, clip.useFullRange)
+	if reverse2 {
+		op2b = op2.Prev
+		for EqualPoints(op2b.Pt, op2.Pt) && op2b != op2 {
+			op2b = op2b.Prev
+		}
+		if op2b.Pt.Y > op2.Pt.Y ||
+			!SlopesEqual3Pt(op2.Pt, op2b.Pt, &j.OffPt, clip.useFullRange) {
+			return false
+		}
+	}
+
+	if op1b == op1 || op2b == op2 || op1b == op2b ||
+		(outRec1 == outRec2 && reverse1 == reverse2) {
+		return false
+	}
+
+	if reverse1 {
+		op1b = DupOutPt(op1, false)
+		op2b = DupOutPt(op2, true)
+		op1.Prev = op2
+		op2.Next = op1
+		op1b.Next = op2b
+		op2b.Prev = op1b
+		j.OutPt1 = op1
+		j.OutPt2 = op1b
+		return true
+	}
+	op1b = DupOutPt(op1, true)
+	op2b = DupOutPt(op2, false)
+	op1.Next = op2
+	op2.Prev = op1
+	op1b.Prev = op2b
+	op2b.Next = op1b
+	j.OutPt1 = op1
+	j.OutPt2 = op1b
+	return true
+}
+
+// FixupFirstLefts1 tests if newOutRec contains the polygon before reassigning FirstLeft
+func (clip *Clipper) FixupFirstLefts1(oldOutRec, newOutRec *OutRec) {
+	for _, outRec := range clip.polyOuts {
+		firstLeft := ParseFirstLeft(outRec.FirstLeft)
+		if outRec.Pts != nil && firstLeft == oldOutRec {
+			if Poly2ContainsPoly1(outRec.Pts, newOutRec.Pts) {
+				outRec.FirstLeft = newOutRec
+			}
+		}
+	}
+}
+
+// FixupFirstLefts2 is used when a polygon has split into two such that one is now
+// the inner of the other. It's possible that these polygons now wrap around other
+// polygons, so check every polygon that's also contained by OuterOutRec's FirstLeft
+// container (including nil) to see if they've become inner to the new inner polygon
+func (clip *Clipper) FixupFirstLefts2(innerOutRec, outerOutRec *OutRec) {
+	orfl := outerOutRec.FirstLeft
+	for _, outRec := range clip.polyOuts {
+		if outRec.Pts == nil || outRec == outerOutRec || outRec == innerOutRec {
+			continue
+		}
+		firstLeft := ParseFirstLeft(outRec.FirstLeft)
+		if firstLeft != orfl && firstLeft != innerOutRec && firstLeft != outerOutRec {
+			continue
+		}
+		if Poly2ContainsPoly1(outRec.Pts, innerOutRec.Pts) {
+			outRec.FirstLeft = innerOutRec
+		} else if Poly2ContainsPoly1(outRec.Pts, outerOutRec.Pts) {
+			outRec.FirstLeft = outerOutRec
+		} else if outRec.FirstLeft == innerOutRec || outRec.FirstLeft == outerOutRec {
+			outRec.FirstLeft = orfl
+		}
+	}
+}
+
+// FixupFirstLefts3 reassigns FirstLeft WITHOUT testing if newOutRec contains the polygon
+func (clip *Clipper) FixupFirstLefts3(oldOutRec, newOutRec *OutRec) {
+	for _, outRec := range clip.polyOuts {
+		firstLeft := ParseFirstLeft(outRec.FirstLeft)
+		if outRec.Pts != nil && firstLeft == oldOutRec {
+			outRec.FirstLeft = newOutRec
+		}
+	}
+}
+
+func (clip *Clipper) JoinCommonEdges() {
+	for _, join := range clip.joins {
+		outRec1 := clip.GetOutRec(join.OutPt1.Idx)
+		outRec2 := clip.GetOutRec(join.OutPt2.Idx)
+
+		if outRec1.Pts == nil || outRec2.Pts == nil {
+			continue
+		}
+		if outRec1.IsOpen || outRec2.IsOpen {
+			continue
+		}
+
+		//get the polygon fragment with the correct hole state (FirstLeft)
+		//before calling JoinPoints() ...
+		var holeStateRec *OutRec
+		if outRec1 == outRec2 {
+			holeStateRec = outRec1
+		} else if OutRec1RightOfOutRec2(outRec1, outRec2) {
+			holeStateRec = outRec2
+		} else if OutRec1RightOfOutRec2(outRec2, outRec1) {
+			holeStateRec = outRec1
+		} else {
+			holeStateRec = GetLowermostRec(outRec1, outRec2)
+		}
+
+		if !clip.JoinPoints(join, outRec1, outRec2) {
+			continue
+		}
+
+		if outRec1 == outRec2 {
+			//instead of joining two polygons, we've just created a new one by
+			//splitting one polygon into two.
+			outRec1.Pts = join.OutPt1
+			outRec1.BottomPt = nil
+			outRec2 = clip.CreateOutRec()
+			outRec2.Pts = join.OutPt2
+
+			// update all OutRec2.Pts Idx's ...
+			UpdateOutPtIdxs(outRec2)
+
+			if Poly2ContainsPoly1(outRec2.Pts, outRec1.Pts) {
+				// outRec1 contains outRec2 ...
+				outRec2.IsHole = !outRec1.IsHole
+				outRec2.FirstLeft = outRec1
+
+				if clip.usingPolyTree {
+					clip.FixupFirstLefts2(outRec2, outRec1)
+				}
+
+				if (outRec2.IsHole != clip.opt.ReverseOutput) == (outRec2.Area() > 0) {
+					ReversePolyPtLinks(outRec2.Pts)
+				}
+			} else if Poly2ContainsPoly1(outRec1.Pts, outRec2.Pts) {
+				// outRec2 contains outRec1 ...
+				outRec2.IsHole = outRec1.IsHole
+				outRec1.IsHole = !outRec2.IsHole
+				outRec2.FirstLeft = outRec1.FirstLeft
+				outRec1.FirstLeft = outRec2
+
+				if clip.usingPolyTree {
+					clip.FixupFirstLefts2(outRec1, outRec2)
+				}
+
+				if (outRec1.IsHole != clip.opt.ReverseOutput) == (outRec1.Area() > 0) {
+					ReversePolyPtLinks(outRec1.Pts)
+				}
+			} else {
+				// the 2 polygons are completely separate ...
+				outRec2.IsHole = outRec1.IsHole
+				outRec2.FirstLeft = outRec1.FirstLeft
+
+				// fixup FirstLeft pointers that may need reassigning to OutRec2
+				if clip.usingPolyTree {
+					clip.FixupFirstLefts1(outRec1, outRec2)
+				}
+			}
+		} else {
+			// joined 2 polygons together ...
+			outRec2.Pts = nil
+			outRec2.BottomPt = nil
+			outRec2.Idx = outRec1.Idx
+
+			outRec1.IsHole = holeStateRec.IsHole
+			if holeStateRec == outRec2 {
+				outRec1.FirstLeft = outRec2.FirstLeft
+			}
+			outRec2.FirstLeft = outRec1
+
+			if clip.usingPolyTree {
+				clip.FixupFirstLefts3(outRec2, outRec1)
+			}
+		}
+	}
+}
+
+func (clip *Clipper) DoSimplePolygons() {
+	for i := 0; i < len(clip.polyOuts); i++ {
+		outrec := clip.polyOuts[i]
+		op := outrec.Pts
+		if op == nil || outrec.IsOpen {
+			continue
+		}
+		for { // for each Pt in Polygon until duplicate found do ...
+			op2 := op.Next
+			for op2 != outrec.Pts {
+				if EqualPoints(op.Pt, op2.Pt) && op2.Next != op && op2.Prev != op {
+					// split the polygon into two ...
+					op3 := op.Prev
+					op4 := op2.Prev
+					op.Prev = op4
+					op4.Next = op
+					op2.Prev = op3
+					op3.Next = op2
+
+					outrec.Pts = op
+					outrec2 := clip.CreateOutRec()
+					outrec2.Pts = op2
+					UpdateOutPtIdxs(outrec2)
+					if Poly2ContainsPoly1(outrec2.Pts, outrec.Pts) {
+						// OutRec2 is contained by OutRec1 ...
+						outrec2.IsHole = !outrec.IsHole
+						outrec2.FirstLeft = outrec
+						if clip.usingPolyTree {
+							clip.FixupFirstLefts2(outrec2, outrec)
+						}
+					} else if Poly2ContainsPoly1(outrec.Pts, outrec2.Pts) {
+						// OutRec1 is contained by OutRec2 ...
+						outrec2.IsHole = outrec.IsHole
+						outrec.IsHole = !outrec2.IsHole
+						outrec2.FirstLeft = outrec.FirstLeft
+						outrec.FirstLeft = outrec2
+						if clip.usingPolyTree {
+							clip.FixupFirstLefts2(outrec, outrec2)
+						}
+					} else {
+						// the 2 polygons are separate ...
+						outrec2.IsHole = outrec.IsHole
+						outrec2.FirstLeft = outrec.FirstLeft
+						if clip.usingPolyTree {
+							clip.FixupFirstLefts1(outrec, outrec2)
+						}
+					}
+					op2 = op // ie get ready for the Next iteration
+				}
+				op2 = op2.Next
+			}
+			op = op.Next
+			if op == outrec.Pts {
+				break
+			}
+		}
+	}
+}
+
+// SimplifyPolygon will remove self-intersections from a polygon by performing a
+// boolean union on it with the supplied fill type
+func SimplifyPolygon(poly *Polygon, fillType PolyFillType) (Polygons, error) {
+	return SimplifyPolygons(Polygons{poly}, fillType)
+}
+
+// SimplifyPolygons will remove self-intersections from polygons by performing a
+// boolean union on them with the supplied fill type
+func SimplifyPolygons(polys Polygons, fillType PolyFillType) (Polygons, error) {
+	clip := NewClipper(ClipperOptions{StrictSimple: true})
+	if _, err := clip.AddPaths(polys, ptSubject, true); err != nil {
+		return nil, err
+	}
+	return clip.Execute(ctUnion, fillType, fillType)
+}
+
+// minkowski will build a quad between every pair of neighbouring pattern copies placed at
+// each point of the path. The quads are all counter clockwise so they can be unioned
+func minkowski(pattern Points, path Points, isSum bool, isClosed bool) Polygons {
+	delta := 0
+	if isClosed {
+		delta = 1
+	}
+	polyCnt := len(pattern)
+	pathCnt := len(path)
+
+	pp := make([]Points, 0, pathCnt)
+	for _, pathPt := range path {
+		p := make(Points, 0, polyCnt)
+		for _, patternPt := range pattern {
+			if isSum {
+				p.Push(NewPoint(pathPt.X+patternPt.X, pathPt.Y+patternPt.Y))
+			} else {
+				p.Push(NewPoint(pathPt.X-patternPt.X, pathPt.Y-patternPt.Y))
+			}
+		}
+		pp = append(pp, p)
+	}
+
+	solution := make(Polygons, 0, (pathCnt+delta)*(polyCnt+1))
+	for i := 0; i < pathCnt-1+delta; i++ {
+		for j := 0; j < polyCnt; j++ {
+			quad := NewPolygon()
+			quad.MP.Points.Push(
+				pp[i%pathCnt][j%polyCnt],
+				pp[(i+1)%pathCnt][j%polyCnt],
+				pp[(i+1)%pathCnt][(j+1)%polyCnt],
+				pp[i%pathCnt][(j+1)%polyCnt])
+			quad.MakeCounterClockwise()
+			solution.Push(quad)
+		}
+	}
+	return solution
+}
+
+// minkowskiCopy will place a copy of points at origin. The copy is negated for differences
+func minkowskiCopy(points Points, origin *Point, isSum bool) *Polygon {
+	poly := NewPolygon()
+	for _, point := range points {
+		if isSum {
+			poly.Push(NewPoint(origin.X+point.X, origin.Y+point.Y))
+		} else {
+			poly.Push(NewPoint(origin.X-point.X, origin.Y-point.Y))
+		}
+	}
+	poly.MakeCounterClockwise()
+	return poly
+}
+
+// minkowskiUnion will union the swept quads with a copy of the pattern at the start of the
+// path, and for closed paths a copy of the path at the first pattern point so the
+// enclosed area is filled
+func minkowskiUnion(pattern Points, path Points, isSum bool, isClosed bool) (Polygons, error) {
+	if len(pattern) == 0 || len(path) == 0 {
+		return NewPolygons(), nil
+	}
+
+	polys := minkowski(pattern, path, isSum, isClosed)
+	if isSum {
+		polys.Push(minkowskiCopy(pattern, path[0], true))
+	} else {
+		// path - pattern is the same as the negated pattern placed on the path
+		polys.Push(minkowskiCopy(pattern, path[0], false))
+	}
+	if isClosed {
+		pathCopy := NewPolygon()
+		for _, point := range path {
+			if isSum {
+				pathCopy.Push(NewPoint(point.X+pattern[0].X, point.Y+pattern[0].Y))
+			} else {
+				pathCopy.Push(NewPoint(point.X-pattern[0].X, point.Y-pattern[0].Y))
+			}
+		}
+		pathCopy.MakeCounterClockwise()
+		polys.Push(pathCopy)
+	}
+	return Union(polys)
+}
+
+// MinkowskiSum will sweep the pattern around a closed path. The area enclosed by the
+// path is part of the result
+func MinkowskiSum(pattern *Polygon, path *Polygon) (Polygons, error) {
+	return minkowskiUnion(pattern.MP.Points, path.MP.Points, true, true)
+}
+
+// MinkowskiSumPolyline will sweep the pattern along an open path
+func MinkowskiSumPolyline(pattern *Polygon, path *Polyline) (Polygons, error) {
+	return minkowskiUnion(pattern.MP.Points, path.MP.Points, true, false)
+}
+
+// MinkowskiDiff will find every difference between a point of poly2 and a point of poly1.
+// The result overlaps the origin when the polygons overlap, which makes it the no fit
+// polygon of poly1 moving around poly2
+func MinkowskiDiff(poly1 *Polygon, poly2 *Polygon) (Polygons, error) {
+	return minkowskiUnion(poly1.MP.Points, poly2.MP.Points, false, true)
+}
@@ -80,3 +80,16 @@ const TOLERANCE = 1.0e-20
 
 const loRange = 0x3FFFFFFF
 const hiRange = 0x3FFFFFFFFFFFFFFF
+
+// Exported join and end types for the offset wrappers in ClipperUtils
+const (
+	JoinSquare = jtSquare
+	JoinRound  = jtRound
+	JoinMiter  = jtMiter
+
+	EndClosedPolygon = etClosedPolygon
+	EndClosedLine    = etClosedLine
+	EndOpenButt      = etOpenButt
+	EndOpenSquare    = etOpenSquare
+	EndOpenRound     = etOpenRound
+)
@@ -0,0 +1,443 @@
+package slice
+
+import "math"
+
+// ClipperOffset is a port of ClipperOffset from clipper 6.4.2. It grows (delta > 0)
+// or shrinks (delta < 0) closed polygons and turns open paths into closed outlines
+
+// DoublePoint is an unrounded point used for edge normals
+type DoublePoint struct {
+	X float64
+	Y float64
+}
+
+// offsetPath is a path waiting to be offset along with how it should be joined and ended
+type offsetPath struct {
+	contour  Points
+	joinType JoinType
+	endType  EndType
+}
+
+// ClipperOffset holds the paths to offset
+type ClipperOffset struct {
+	MiterLimit   float64
+	ArcTolerance float64
+
+	paths       []*offsetPath
+	destPolys   Polygons
+	srcPoly     Points
+	destPoly    Points
+	normals     []DoublePoint
+	delta       float64
+	sinA        float64
+	sin         float64
+	cos         float64
+	miterLim    float64
+	stepsPerRad float64
+	lowest      offsetIndex // of the lowest point of the closed paths
+}
+
+// offsetIndex is the index of a point of a path, path is -1 before any closed path
+type offsetIndex struct {
+	path  int
+	point int
+}
+
+// NewClipperOffset will construct a ClipperOffset. Clipper's defaults are a miter
+// limit of 2 and an arc tolerance of 0.25
+func NewClipperOffset(miterLimit float64, arcTolerance float64) *ClipperOffset {
+	co := new(ClipperOffset)
+	co.MiterLimit = miterLimit
+	co.ArcTolerance = arcTolerance
+	co.Clear()
+	return co
+}
+
+// Clear will remove every path
+func (co *ClipperOffset) Clear() {
+	co.paths = make([]*offsetPath, 0)
+	co.lowest.path = -1
+}
+
+// AddPath will add a path to be offset. Points are rounded like everything else clipper takes in
+func (co *ClipperOffset) AddPath(path Points, joinType JoinType, endType EndType) {
+	highI := len(path) - 1
+	if highI < 0 {
+		return
+	}
+	node := &offsetPath{joinType: joinType, endType: endType}
+
+	// strip duplicate points from path and also get index to the lowest point ...
+	if endType == etClosedLine || endType == etClosedPolygon {
+		for highI > 0 && path[0].CoincidesWith(path[highI]) {
+			highI--
+		}
+	}
+	node.contour = make(Points, 0, highI+1)
+	node.contour.Push(NewPoint(math.Round(path[0].X), math.Round(path[0].Y)))
+	j, k := 0, 0
+	for i := 1; i <= highI; i++ {
+		pt := NewPoint(math.Round(path[i].X), math.Round(path[i].Y))
+		if node.contour[j].CoincidesWith(pt) {
+			continue
+		}
+		j++
+		node.contour.Push(pt)
+		if pt.Y > node.contour[k].Y || (pt.Y == node.contour[k].Y && pt.X < node.contour[k].X) {
+			k = j
+		}
+	}
+	if endType == etClosedPolygon && j < 2 {
+		return
+	}
+	co.paths = append(co.paths, node)
+
+	// if this path's lowest pt is lower than all the others then update m_lowest
+	if endType != etClosedPolygon {
+		return
+	}
+	if co.lowest.path < 0 {
+		co.lowest = offsetIndex{len(co.paths) - 1, k}
+	} else {
+		ip := co.paths[co.lowest.path].contour[co.lowest.point]
+		if node.contour[k].Y > ip.Y || (node.contour[k].Y == ip.Y && node.contour[k].X < ip.X) {
+			co.lowest = offsetIndex{len(co.paths) - 1, k}
+		}
+	}
+}
+
+// AddPaths will add every polygon
+func (co *ClipperOffset) AddPaths(paths Polygons, joinType JoinType, endType EndType) {
+	for _, path := range paths {
+		co.AddPath(path.MP.Points, joinType, endType)
+	}
+}
+
+func orientationPoints(points Points) bool {
+	poly := NewPolygon()
+	poly.MP.Points = points
+	return poly.Area() >= 0
+}
+
+func reversePoints(points Points) {
+	for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+		points[i], points[j] = points[j], points[i]
+	}
+}
+
+// FixOrientations will make sure the lowest closed polygon is an outer and orient
+// closed lines to match
+func (co *ClipperOffset) FixOrientations() {
+	if co.lowest.path >= 0 && !orientationPoints(co.paths[co.lowest.path].contour) {
+		for _, node := range co.paths {
+			if node.endType == etClosedPolygon || (node.endType == etClosedLine && orientationPoints(node.contour)) {
+				reversePoints(node.contour)
+			}
+		}
+	} else {
+		for _, node := range co.paths {
+			if node.endType == etClosedLine && !orientationPoints(node.contour) {
+				reversePoints(node.contour)
+			}
+		}
+	}
+}
+
+// Execute will offset every path by delta and union the results
+func (co *ClipperOffset) Execute(delta float64) (Polygons, error) {
+	co.FixOrientations()
+	co.DoOffset(delta)
+
+	// now clean up 'corners' ...
+	if delta > 0 {
+		clipper := NewClipper(ClipperOptions{})
+		if _, err := clipper.AddPaths(co.destPolys, ptSubject, true); err != nil {
+			return nil, err
+		}
+		return clipper.Execute(ctUnion, pftPositive, pftPositive)
+	}
+
+	clipper := NewClipper(ClipperOptions{ReverseOutput: true})
+	if _, err := clipper.AddPaths(co.destPolys, ptSubject, true); err != nil {
+		return nil, err
+	}
+	r := clipper.GetBounds()
+	outer := NewPolygon()
+	outer.MP.Points.Push(
+		NewPoint(r.Left-10, r.Bottom+10),
+		NewPoint(r.Right+10, r.Bottom+10),
+		NewPoint(r.Right+10, r.Top-10),
+		NewPoint(r.Left-10, r.Top-10))
+	if _, err := clipper.AddPath(outer, ptSubject, true); err != nil {
+		return nil, err
+	}
+	solution, err := clipper.Execute(ctUnion, pftNegative, pftNegative)
+	if err != nil {
+		return nil, err
+	}
+	if len(solution) > 0 {
+		solution = solution[1:]
+	}
+	return solution, nil
+}
+
+// getUnitNormal will return the unit normal to the right of pt1 -> pt2
+func getUnitNormal(pt1 *Point, pt2 *Point) DoublePoint {
+	if pt2.X == pt1.X && pt2.Y == pt1.Y {
+		return DoublePoint{0, 0}
+	}
+	dx := pt2.X - pt1.X
+	dy := pt2.Y - pt1.Y
+	f := 1 / math.Sqrt(dx*dx+dy*dy)
+	dx *= f
+	dy *= f
+	return DoublePoint{dy, -dx}
+}
+
+func (co *ClipperOffset) pushOffset(src *Point, x float64, y float64) {
+	co.destPoly.Push(NewPoint(math.Round(src.X+x), math.Round(src.Y+y)))
+}
+
+// DoOffset will fill destPolys with the raw, uncleaned offsets of every path
+func (co *ClipperOffset) DoOffset(delta float64) {
+	co.destPolys = NewPolygons()
+	co.delta = delta
+
+	// if Zero offset, just copy any CLOSED polygons to m_p and return ...
+	if NearZero(delta) {
+		for _, node := range co.paths {
+			if node.endType == etClosedPolygon {
+				co.pushDest(node.contour.GetCopy())
+			}
+		}
+		return
+	}
+
+	// see offset_triginometry3.svg in the documentation folder ...
+	if co.MiterLimit > 2 {
+		co.miterLim = 2 / (co.MiterLimit * co.MiterLimit)
+	} else {
+		co.miterLim = 0.5
+	}
+
+	var y float64
+	if co.ArcTolerance <= 0.0 {
+		y = defArcTolerance
+	} else if co.ArcTolerance > math.Abs(delta)*defArcTolerance {
+		y = math.Abs(delta) * defArcTolerance
+	} else {
+		y = co.ArcTolerance
+	}
+	// see offset_triginometry2.svg in the documentation folder ...
+	steps := pi / math.Acos(1-y/math.Abs(delta))
+	if steps > math.Abs(delta)*pi {
+		steps = math.Abs(delta) * pi // ie excessive precision check
+	}
+	co.sin = math.Sin(twoPi / steps)
+	co.cos = math.Cos(twoPi / steps)
+	co.stepsPerRad = steps / twoPi
+	if delta < 0.0 {
+		co.sin = -co.sin
+	}
+
+	for _, node := range co.paths {
+		co.srcPoly = node.contour
+		length := len(co.srcPoly)
+		if length == 0 || (delta <= 0 && (length < 3 || node.endType != etClosedPolygon)) {
+			continue
+		}
+		co.destPoly = NewPoints()
+
+		if length == 1 {
+			src := co.srcPoly[0]
+			if node.joinType == jtRound {
+				X, Y := 1.0, 0.0
+				for j := 1; float64(j) <= steps; j++ {
+					co.pushOffset(src, X*delta, Y*delta)
+					X2 := X
+					X = X*co.cos - co.sin*Y
+					Y = X2*co.sin + Y*co.cos
+				}
+			} else {
+				X, Y := -1.0, -1.0
+				for j := 0; j < 4; j++ {
+					co.pushOffset(src, X*delta, Y*delta)
+					if X < 0 {
+						X = 1
+					} else if Y < 0 {
+						Y = 1
+					} else {
+						X = -1
+					}
+				}
+			}
+			co.pushDest(co.destPoly)
+			continue
+		}
+
+		// build m_normals ...
+		co.normals = make([]DoublePoint, 0, length)
+		for j := 0; j < length-1; j++ {
+			co.normals = append(co.normals, getUnitNormal(co.srcPoly[j], co.srcPoly[j+1]))
+		}
+		if node.endType == etClosedLine || node.endType == etClosedPolygon {
+			co.normals = append(co.normals, getUnitNormal(co.srcPoly[length-1], co.srcPoly[0]))
+		} else {
+			co.normals = append(co.normals, co.normals[length-2])
+		}
+
+		if node.endType == etClosedPolygon {
+			k := length - 1
+			for j := 0; j < length; j++ {
+				k = co.offsetPoint(j, k, node.joinType)
+			}
+			co.pushDest(co.destPoly)
+		} else if node.endType == etClosedLine {
+			k := length - 1
+			for j := 0; j < length; j++ {
+				k = co.offsetPoint(j, k, node.joinType)
+			}
+			co.pushDest(co.destPoly)
+			co.destPoly = NewPoints()
+			// re-build m_normals ...
+			n := co.normals[length-1]
+			for j := length - 1; j > 0; j-- {
+				co.normals[j] = DoublePoint{-co.normals[j-1].X, -co.normals[j-1].Y}
+			}
+			co.normals[0] = DoublePoint{-n.X, -n.Y}
+			k = 0
+			for j := length - 1; j >= 0; j-- {
+				k = co.offsetPoint(j, k, node.joinType)
+			}
+			co.pushDest(co.destPoly)
+		} else {
+			k := 0
+			for j := 1; j < length-1; j++ {
+				k = co.offsetPoint(j, k, node.joinType)
+			}
+
+			if node.endType == etOpenButt {
+				j := length - 1
+				co.pushOffset(co.srcPoly[j], co.normals[j].X*delta, co.normals[j].Y*delta)
+				co.pushOffset(co.srcPoly[j], -co.normals[j].X*delta, -co.normals[j].Y*delta)
+			} else {
+				j := length - 1
+				k = length - 2
+				co.sinA = 0
+				co.normals[j] = DoublePoint{-co.normals[j].X, -co.normals[j].Y}
+				if node.endType == etOpenSquare {
+					co.doSquare(j, k)
+				} else {
+					co.doRound(j, k)
+				}
+			}
+
+			// re-build m_normals ...
+			for j := length - 1; j > 0; j-- {
+				co.normals[j] = DoublePoint{-co.normals[j-1].X, -co.normals[j-1].Y}
+			}
+			co.normals[0] = DoublePoint{-co.normals[1].X, -co.normals[1].Y}
+
+			k = length - 1
+			for j := k - 1; j > 0; j-- {
+				k = co.offsetPoint(j, k, node.joinType)
+			}
+
+			if node.endType == etOpenButt {
+				co.pushOffset(co.srcPoly[0], -co.normals[0].X*delta, -co.normals[0].Y*delta)
+				co.pushOffset(co.srcPoly[0], co.normals[0].X*delta, co.normals[0].Y*delta)
+			} else {
+				co.sinA = 0
+				if node.endType == etOpenSquare {
+					co.doSquare(0, 1)
+				} else {
+					co.doRound(0, 1)
+				}
+			}
+			co.pushDest(co.destPoly)
+		}
+	}
+}
+
+func (co *ClipperOffset) pushDest(points Points) {
+	poly := NewPolygon()
+	poly.MP.Points = points
+	co.destPolys.Push(poly)
+}
+
+// offsetPoint will offset point j of the source with k being the previous point.
+// The new k is returned
+func (co *ClipperOffset) offsetPoint(j int, k int, joinType JoinType) int {
+	// cross product ...
+	co.sinA = co.normals[k].X*co.normals[j].Y - co.normals[j].X*co.normals[k].Y
+	if math.Abs(co.sinA*co.delta) < 1.0 {
+		// dot product ...
+		cosA := co.normals[k].X*co.normals[j].X + co.normals[j].Y*co.normals[k].Y
+		if cosA > 0 { // angle => 0 degrees
+			co.pushOffset(co.srcPoly[j], co.normals[k].X*co.delta, co.normals[k].Y*co.delta)
+			return k
+		}
+		// else angle => 180 degrees
+	} else if co.sinA > 1.0 {
+		co.sinA = 1.0
+	} else if co.sinA < -1.0 {
+		co.sinA = -1.0
+	}
+
+	if co.sinA*co.delta < 0 {
+		co.pushOffset(co.srcPoly[j], co.normals[k].X*co.delta, co.normals[k].Y*co.delta)
+		co.destPoly.Push(NewPoint(co.srcPoly[j].X, co.srcPoly[j].Y))
+		co.pushOffset(co.srcPoly[j], co.normals[j].X*co.delta, co.normals[j].Y*co.delta)
+	} else {
+		switch joinType {
+		case jtMiter:
+			r := 1 + (co.normals[j].X*co.normals[k].X + co.normals[j].Y*co.normals[k].Y)
+			if r >= co.miterLim {
+				co.doMiter(j, k, r)
+			} else {
+				co.doSquare(j, k)
+			}
+		case jtSquare:
+			co.doSquare(j, k)
+		case jtRound:
+			co.doRound(j, k)
+		}
+	}
+	return j
+}
+
+func (co *ClipperOffset) doSquare(j int, k int) {
+	dx := math.Tan(math.Atan2(co.sinA,
+		co.normals[k].X*co.normals[j].X+co.normals[k].Y*co.normals[j].Y) / 4)
+	co.pushOffset(co.srcPoly[j],
+		co.delta*(co.normals[k].X-co.normals[k].Y*dx),
+		co.delta*(co.normals[k].Y+co.normals[k].X*dx))
+	co.pushOffset(co.srcPoly[j],
+		co.delta*(co.normals[j].X+co.normals[j].Y*dx),
+		co.delta*(co.normals[j].Y-co.normals[j].X*dx))
+}
+
+func (co *ClipperOffset) doMiter(j int, k int, r float64) {
+	q := co.delta / r
+	co.pushOffset(co.srcPoly[j],
+		(co.normals[k].X+co.normals[j].X)*q,
+		(co.normals[k].Y+co.normals[j].Y)*q)
+}
+
+func (co *ClipperOffset) doRound(j int, k int) {
+	a := math.Atan2(co.sinA,
+		co.normals[k].X*co.normals[j].X+co.normals[k].Y*co.normals[j].Y)
+	steps := int(math.Round(co.stepsPerRad * math.Abs(a)))
+	if steps < 1 {
+		steps = 1
+	}
+
+	X, Y := co.normals[k].X, co.normals[k].Y
+	for i := 0; i < steps; i++ {
+		co.pushOffset(co.srcPoly[j], X*co.delta, Y*co.delta)
+		X2 := X
+		X = X*co.cos - co.sin*Y
+		Y = X2*co.sin + Y*co.cos
+	}
+	co.pushOffset(co.srcPoly[j], co.normals[j].X*co.delta, co.normals[j].Y*co.delta)
+}
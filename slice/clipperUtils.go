@@ -0,0 +1,204 @@
+package slice
+
+// These follow Slic3r's ClipperUtils. They wrap the Clipper so the rest of
+// the slicer can work on Polygons and PolygonExs directly.
+
+// clipPolygons will run a clip operation between subject and clip polygons
+func clipPolygons(clipType ClipType, subject Polygons, clip Polygons, fillType PolyFillType) (Polygons, error) {
+	clipper := NewClipper(ClipperOptions{})
+	if _, err := clipper.AddPaths(subject, ptSubject, true); err != nil {
+		return nil, err
+	}
+	if _, err := clipper.AddPaths(clip, ptClip, true); err != nil {
+		return nil, err
+	}
+	return clipper.Execute(clipType, fillType, fillType)
+}
+
+// clipPolygonsEx will run a clip operation and return the result as PolygonExs
+func clipPolygonsEx(clipType ClipType, subject Polygons, clip Polygons, fillType PolyFillType) (PolygonExs, error) {
+	clipper := NewClipper(ClipperOptions{})
+	if _, err := clipper.AddPaths(subject, ptSubject, true); err != nil {
+		return nil, err
+	}
+	if _, err := clipper.AddPaths(clip, ptClip, true); err != nil {
+		return nil, err
+	}
+	tree, err := clipper.ExecutePolyTree(clipType, fillType, fillType)
+	if err != nil {
+		return nil, err
+	}
+	return PolyTreeToPolygonExs(tree), nil
+}
+
+// Union will merge all of the subject polygons. Overlapping and self intersecting
+// polygons are resolved with the nonzero fill rule
+func Union(subject Polygons) (Polygons, error) {
+	return clipPolygons(ctUnion, subject, NewPolygons(), pftNonZero)
+}
+
+// UnionEx will merge all of the subject polygons and match holes with their contours
+func UnionEx(subject Polygons) (PolygonExs, error) {
+	return clipPolygonsEx(ctUnion, subject, NewPolygons(), pftNonZero)
+}
+
+// SimplifyPolygonsEx will remove self intersections from the polygons and match
+// holes with their contours
+func SimplifyPolygonsEx(subject Polygons) (PolygonExs, error) {
+	clipper := NewClipper(ClipperOptions{StrictSimple: true})
+	if _, err := clipper.AddPaths(subject, ptSubject, true); err != nil {
+		return nil, err
+	}
+	tree, err := clipper.ExecutePolyTree(ctUnion, pftNonZero, pftNonZero)
+	if err != nil {
+		return nil, err
+	}
+	return PolyTreeToPolygonExs(tree), nil
+}
+
+// PolyTreeToPolygonExs will convert a PolyTree into PolygonExs. Outer contours
+// nested inside holes become PolygonExs of their own
+func PolyTreeToPolygonExs(tree *PolyTree) PolygonExs {
+	pgxs := NewPolygonExs()
+	for _, node := range tree.Childs {
+		if !node.IsOpen() {
+			addOuterPolyNodeToPolygonExs(node, &pgxs)
+		}
+	}
+	return pgxs
+}
+
+func addOuterPolyNodeToPolygonExs(node *PolyNode, pgxs *PolygonExs) {
+	pgx := NewPolygonEx()
+	pgx.Contour = node.Contour
+	for _, hole := range node.Childs {
+		pgx.Holes.Push(hole.Contour)
+		// add outer polygons contained by (nested within) holes ...
+		for _, outer := range hole.Childs {
+			addOuterPolyNodeToPolygonExs(outer, pgxs)
+		}
+	}
+	pgxs.Push(pgx)
+}
+
+// DefaultMiterLimit is the miter limit Slic3r offsets with
+const DefaultMiterLimit float64 = 3
+
+// newClipperOffset will set up a ClipperOffset the way Slic3r does. For round joins
+// the miter limit is used as the arc tolerance
+func newClipperOffset(joinType JoinType, miterLimit float64) *ClipperOffset {
+	if joinType == jtRound {
+		return NewClipperOffset(2, miterLimit)
+	}
+	return NewClipperOffset(miterLimit, defArcTolerance)
+}
+
+// Offset will grow (delta > 0) or shrink (delta < 0) the polygons with mitered corners
+func Offset(polygons Polygons, delta float64) (Polygons, error) {
+	return OffsetJoin(polygons, delta, jtMiter, DefaultMiterLimit)
+}
+
+// OffsetJoin will grow or shrink the polygons using the supplied join type
+func OffsetJoin(polygons Polygons, delta float64, joinType JoinType, miterLimit float64) (Polygons, error) {
+	co := newClipperOffset(joinType, miterLimit)
+	co.AddPaths(polygons, joinType, etClosedPolygon)
+	return co.Execute(delta)
+}
+
+// OffsetEx will grow or shrink the polygons and match holes with their contours
+func OffsetEx(polygons Polygons, delta float64) (PolygonExs, error) {
+	offset, err := Offset(polygons, delta)
+	if err != nil {
+		return nil, err
+	}
+	return UnionEx(offset)
+}
+
+// OffsetPolylines will turn open polylines into closed outlines delta wide on each side
+func OffsetPolylines(polylines Polylines, delta float64, joinType JoinType, endType EndType, miterLimit float64) (Polygons, error) {
+	co := newClipperOffset(joinType, miterLimit)
+	for _, pline := range polylines {
+		co.AddPath(pline.MP.Points, joinType, endType)
+	}
+	return co.Execute(delta)
+}
+
+// Offset2 will offset the polygons by delta1 and then the result by delta2. Shrinking
+// then growing removes parts narrower than the shrink
+func Offset2(polygons Polygons, delta1 float64, delta2 float64) (Polygons, error) {
+	offset, err := Offset(polygons, delta1)
+	if err != nil {
+		return nil, err
+	}
+	return Offset(offset, delta2)
+}
+
+// Offset2Ex is Offset2 returning PolygonExs
+func Offset2Ex(polygons Polygons, delta1 float64, delta2 float64) (PolygonExs, error) {
+	offset, err := Offset(polygons, delta1)
+	if err != nil {
+		return nil, err
+	}
+	return OffsetEx(offset, delta2)
+}
+
+// Diff will subtract clip from subject
+func Diff(subject Polygons, clip Polygons) (Polygons, error) {
+	return clipPolygons(ctDifference, subject, clip, pftNonZero)
+}
+
+// DiffEx will subtract clip from subject and match holes with their contours
+func DiffEx(subject Polygons, clip Polygons) (PolygonExs, error) {
+	return clipPolygonsEx(ctDifference, subject, clip, pftNonZero)
+}
+
+// Intersection will keep the areas covered by both subject and clip
+func Intersection(subject Polygons, clip Polygons) (Polygons, error) {
+	return clipPolygons(ctIntersection, subject, clip, pftNonZero)
+}
+
+// IntersectionEx will keep the areas covered by both subject and clip and match holes
+// with their contours
+func IntersectionEx(subject Polygons, clip Polygons) (PolygonExs, error) {
+	return clipPolygonsEx(ctIntersection, subject, clip, pftNonZero)
+}
+
+// clipPolylines will clip open polylines against closed polygons
+func clipPolylines(clipType ClipType, subject Polylines, clip Polygons) (Polylines, error) {
+	clipper := NewClipper(ClipperOptions{})
+	for _, pline := range subject {
+		poly := NewPolygon()
+		poly.MP.Points = pline.MP.Points
+		if _, err := clipper.AddPath(poly, ptSubject, false); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := clipper.AddPaths(clip, ptClip, true); err != nil {
+		return nil, err
+	}
+	tree, err := clipper.ExecutePolyTree(clipType, pftNonZero, pftNonZero)
+	if err != nil {
+		return nil, err
+	}
+
+	plines := NewPolylines()
+	for _, node := range tree.AllNodes {
+		if !node.IsOpen() {
+			continue
+		}
+		pline := NewPolyline()
+		pline.MP.Points = node.Contour.MP.Points
+		plines.Push(pline)
+	}
+	return plines, nil
+}
+
+// IntersectionPolylines will keep the parts of the polylines inside clip
+func IntersectionPolylines(subject Polylines, clip Polygons) (Polylines, error) {
+	return clipPolylines(ctIntersection, subject, clip)
+}
+
+// DiffPolylines will keep the parts of the polylines outside clip
+func DiffPolylines(subject Polylines, clip Polygons) (Polylines, error) {
+	return clipPolylines(ctDifference, subject, clip)
+}
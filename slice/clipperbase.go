@@ -3,6 +3,7 @@ package slice
 import (
 	"errors"
 	"math"
+	"sort"
 )
 
 var ErrOutsideRange = errors.New("coordinate outside allowed range")
@@ -94,9 +95,9 @@ func (cb *ClipperBase) ProcessBound(e *TEdge, NextIsForward bool) *TEdge {
 		if IsHorizontal(estart) { //ie an adjoining horizontal skip edge
 			if estart.Bot.X != e.Bot.X && estart.Top.X != e.Bot.X {
 				e.ReverseHorizontal()
-			} else if estart.Bot.X != e.Bot.X {
-				e.ReverseHorizontal()
 			}
+		} else if estart.Bot.X != e.Bot.X {
+			e.ReverseHorizontal()
 		}
 	}
 
@@ -161,19 +162,27 @@ func (cb *ClipperBase) ProcessBound(e *TEdge, NextIsForward bool) *TEdge {
 	return result
 }
 
+// AddPath will add a path to the clipper. Points are rounded to whole scaled units
+// since the clipper works on integer coordinates. false is returned when the path
+// is degenerate and was ignored
 func (cb *ClipperBase) AddPath(pg *Polygon, Ptype PolyType, Closed bool) (bool, error) {
 	if !Closed && Ptype == ptClip {
 		return false, errors.New("add path: open path must be subject")
 	}
 
-	highI := len(pg.MP.Points) - 1
+	pts := make([]Point, len(pg.MP.Points))
+	for i, pt := range pg.MP.Points {
+		pts[i] = Point{X: math.Round(pt.X), Y: math.Round(pt.Y)}
+	}
+
+	highI := len(pts) - 1
 	if Closed {
-		for highI > 0 && EqualPoints(pg.MP.Points.EntryAtIndex(highI), pg.MP.Points.EntryAtIndex(0)) {
+		for highI > 0 && pts[highI] == pts[0] {
 			highI -= 1
 		}
 	}
 
-	for highI > 0 && EqualPoints(pg.MP.Points.EntryAtIndex(highI), pg.MP.Points.EntryAtIndex(highI-1)) {
+	for highI > 0 && pts[highI] == pts[highI-1] {
 		highI -= 1
 	}
 
@@ -182,33 +191,36 @@ func (cb *ClipperBase) AddPath(pg *Polygon, Ptype PolyType, Closed bool) (bool,
 	}
 
 	// create a new edge array
-	var edges []*TEdge = make([]*TEdge, highI+1)
+	edges := make([]*TEdge, highI+1)
+	for i := range edges {
+		edges[i] = new(TEdge)
+	}
 	isFlat := true
 
-	// Basic Initialization
-	edges[1].Curr = pg.MP.Points.EntryAtIndex(1)
-	err1 := RangeTest(pg.MP.Points.First(), cb.useFullRange)
-	err2 := RangeTest(pg.MP.Points.EntryAtIndex(highI), cb.useFullRange)
+	//1. Basic (first) edge initialization ...
+	edges[1].Curr = pts[1]
+	err1 := RangeTest(&pts[0], &cb.useFullRange)
+	err2 := RangeTest(&pts[highI], &cb.useFullRange)
 	if err1 != nil || err2 != nil {
-		return false, errors.New("add path: range test failed")
+		return false, ErrOutsideRange
 	}
-	edges[0].InitEdge(edges[1], edges[highI], pg.MP.Points.EntryAtIndex(0))
-	edges[highI].InitEdge(edges[0], edges[highI-1], pg.MP.Points.EntryAtIndex(highI))
+	edges[0].InitEdge(edges[1], edges[highI], &pts[0])
+	edges[highI].InitEdge(edges[0], edges[highI-1], &pts[highI])
 
 	for i := highI - 1; i >= 1; i-- {
-		err := RangeTest(pg.MP.Points.EntryAtIndex(i), cb.useFullRange)
-		if err != nil {
-			return false, errors.New("add path: range test failed")
+		if err := RangeTest(&pts[i], &cb.useFullRange); err != nil {
+			return false, err
 		}
-		edges[i].InitEdge(edges[i+1], edges[i-1], pg.MP.Points.EntryAtIndex(i))
+		edges[i].InitEdge(edges[i+1], edges[i-1], &pts[i])
 	}
 
 	eStart := edges[0]
 
-	//2. Remove duplicate vertices, and when closed collinear edges
+	//2. Remove duplicate vertices, and (when closed) collinear edges ...
 	E, eLoopStop := eStart, eStart
 
 	for {
+		//nb: allows matching start and end points when not Closed ...
 		if E.Curr == E.Next.Curr && (Closed || E.Next != eStart) {
 			if E == E.Next {
 				break
@@ -217,7 +229,7 @@ func (cb *ClipperBase) AddPath(pg *Polygon, Ptype PolyType, Closed bool) (bool,
 				eStart = E.Next
 			}
 
-			E.RemoveEdge()
+			E = E.RemoveEdge()
 			eLoopStop = E
 			continue
 		}
@@ -225,8 +237,8 @@ func (cb *ClipperBase) AddPath(pg *Polygon, Ptype PolyType, Closed bool) (bool,
 		if E.Prev == E.Next {
 			break //only two vertices
 		} else if Closed &&
-			SlopesEqual3Pt(E.Prev.Curr, E.Curr, E.Next.Curr) &&
-			(!cb.preserveCollinear || !Pt2IsBetweenPt1AndPt3(E.Prev.Curr, E.Curr, E.Next.Curr)) {
+			SlopesEqual3Pt(&E.Prev.Curr, &E.Curr, &E.Next.Curr, cb.useFullRange) &&
+			(!cb.preserveCollinear || !Pt2IsBetweenPt1AndPt3(&E.Prev.Curr, &E.Curr, &E.Next.Curr)) {
 
 			//Collinear edges are allowed for open paths but in closed paths
 			//the default is to merge adjacent collinear edges into a single edge.
@@ -236,7 +248,7 @@ func (cb *ClipperBase) AddPath(pg *Polygon, Ptype PolyType, Closed bool) (bool,
 			if E == eStart {
 				eStart = E.Next
 			}
-			E.RemoveEdge()
+			E = E.RemoveEdge()
 			E = E.Prev
 			eLoopStop = E
 			continue
@@ -257,7 +269,7 @@ func (cb *ClipperBase) AddPath(pg *Polygon, Ptype PolyType, Closed bool) (bool,
 		eStart.Prev.OutIdx = Skip
 	}
 
-	// 3. Do second stage of edge initialization
+	//3. Do second stage of edge initialization ...
 	E = eStart
 	for {
 		E.InitEdgeWithPolyType(Ptype)
@@ -271,7 +283,7 @@ func (cb *ClipperBase) AddPath(pg *Polygon, Ptype PolyType, Closed bool) (bool,
 		}
 	}
 
-	//4. Finally, add edge bounds to LocalMinima list
+	//4. Finally, add edge bounds to LocalMinima list ...
 
 	//Totally flat paths must be handled differently when adding them
 	//to LocalMinima list to avoid endless loops etc ...
@@ -312,12 +324,12 @@ func (cb *ClipperBase) AddPath(pg *Polygon, Ptype PolyType, Closed bool) (bool,
 
 	//workaround to avoid an endless loop in the while loop below when
 	//open paths have matching start and end points ...
-	if EqualPoints(E.Prev.Bot, E.Prev.Top) {
+	if E.Prev.Bot == E.Prev.Top {
 		E = E.Next
 	}
 
 	for {
-		E.FindNextLocMin()
+		E = E.FindNextLocMin()
 		if E == EMin {
 			break
 		} else if EMin == nil {
@@ -373,14 +385,16 @@ func (cb *ClipperBase) AddPath(pg *Polygon, Ptype PolyType, Closed bool) (bool,
 	return true, nil
 }
 
+// AddPaths will add every path to the clipper. result is true if any of the paths were added
 func (cb *ClipperBase) AddPaths(paths []*Polygon, Ptype PolyType, Closed bool) (result bool, err error) {
 	for _, path := range paths {
-		result, err = cb.AddPath(path, Ptype, Closed)
+		added, err := cb.AddPath(path, Ptype, Closed)
 		if err != nil {
-			break
+			return result, err
 		}
+		result = result || added
 	}
-	return result, err
+	return result, nil
 }
 
 func (cb *ClipperBase) Clear() {
@@ -392,15 +406,14 @@ func (cb *ClipperBase) Clear() {
 
 func (cb *ClipperBase) Reset() {
 	cb.currentLM = 0
-	if cb.minimaList.EntryAtIndex(cb.currentLM) == cb.minimaList.Last() {
+	// clear / reset priority queue
+	cb.scanBeamList = make([]float64, 0)
+	if cb.minimaList.Empty() {
 		return // Nothing to process
 	}
 
 	SortLocalMinimum(cb.minimaList)
 
-	// clear / reset priority queue
-	cb.scanBeamList = make([]float64, 0)
-
 	for _, lm := range cb.minimaList {
 		cb.InsertScanbeam(lm.Y)
 		e := lm.LeftBound
@@ -410,7 +423,6 @@ func (cb *ClipperBase) Reset() {
 			e.OutIdx = Unassigned
 		}
 
-		e = nil
 		e = lm.RightBound
 		if e != nil {
 			e.Curr = e.Bot
@@ -421,17 +433,15 @@ func (cb *ClipperBase) Reset() {
 
 	cb.activeEdges = nil
 	cb.currentLM = 0
-
 }
 
 func (cb *ClipperBase) DisposeLocalMinimaList() {
-	cb.minimaList.Clear()
+	cb.minimaList = NewLocalMinimums()
 	cb.currentLM = 0
 }
 
 func (cb *ClipperBase) PopLocalMinima(y float64) (*LocalMinimum, bool) {
-	if cb.minimaList.EntryAtIndex(cb.currentLM) == cb.minimaList.Last() ||
-		(cb.minimaList.EntryAtIndex(cb.currentLM).Y != y) {
+	if !cb.LocalMinimaPending() || cb.minimaList.EntryAtIndex(cb.currentLM).Y != y {
 		return nil, false
 	}
 	locmin := cb.minimaList.EntryAtIndex(cb.currentLM)
@@ -441,99 +451,65 @@ func (cb *ClipperBase) PopLocalMinima(y float64) (*LocalMinimum, bool) {
 
 func (cb *ClipperBase) GetBounds() FloatRect {
 	result := FloatRect{}
-	lmIdx := 0
-	lm := cb.minimaList.EntryAtIndex(lmIdx)
-	if lm == cb.minimaList.Last() {
-		return FloatRect{
-			Top:    0,
-			Bottom: 0,
-			Left:   0,
-			Right:  0,
-		}
-	}
-
-	result.Left = lm.LeftBound.Bot.X
-	result.Top = lm.LeftBound.Bot.Y
-	result.Right = lm.LeftBound.Bot.X
-	result.Bottom = lm.LeftBound.Bot.Y
-
-	for lm != cb.minimaList.Last() {
-		result.Bottom = math.Max(result.Bottom, lm.LeftBound.Bot.Y)
+	first := true
 
-		e := lm.LeftBound
-		for {
-			bottomE := e
+	for _, lm := range cb.minimaList {
+		for _, e := range []*TEdge{lm.LeftBound, lm.RightBound} {
+			if e == nil {
+				continue
+			}
+			if first {
+				result.Left, result.Right = e.Bot.X, e.Bot.X
+				result.Top, result.Bottom = e.Bot.Y, e.Bot.Y
+				first = false
+			}
+			result.Bottom = math.Max(result.Bottom, e.Bot.Y)
 			for e.NextInLML != nil {
-				if e.Bot.X < result.Left {
-					result.Left = e.Bot.X
-				}
-				if e.Bot.X > result.Right {
-					result.Right = e.Bot.X
-				}
+				result.Left = math.Min(result.Left, e.Bot.X)
+				result.Right = math.Max(result.Right, e.Bot.X)
 				e = e.NextInLML
 			}
-			result.Left = math.Min(result.Left, e.Bot.X)
-			result.Right = math.Max(result.Right, e.Bot.X)
-
-			result.Left = math.Min(result.Left, e.Top.X)
-			result.Right = math.Max(result.Right, e.Top.X)
-
+			result.Left = math.Min(result.Left, math.Min(e.Bot.X, e.Top.X))
+			result.Right = math.Max(result.Right, math.Max(e.Bot.X, e.Top.X))
 			result.Top = math.Min(result.Top, e.Top.Y)
-
-			if bottomE == lm.LeftBound {
-				e = lm.RightBound
-			} else {
-				break
-			}
-			lmIdx++
-			lm = cb.minimaList.EntryAtIndex(lmIdx)
 		}
 	}
 	return result
 }
 
+// InsertScanbeam will add a y value to the scanbeam list. The list is kept
+// sorted from the largest y to the smallest without duplicates
 func (cb *ClipperBase) InsertScanbeam(y float64) {
-	cb.scanBeamList = append(cb.scanBeamList, y)
+	idx := sort.Search(len(cb.scanBeamList), func(i int) bool {
+		return cb.scanBeamList[i] <= y
+	})
+	if idx < len(cb.scanBeamList) && cb.scanBeamList[idx] == y {
+		return
+	}
+	cb.scanBeamList = append(cb.scanBeamList, 0)
+	copy(cb.scanBeamList[idx+1:], cb.scanBeamList[idx:])
+	cb.scanBeamList[idx] = y
 }
 
 func (cb *ClipperBase) PopScanbeam() (float64, bool) {
 	if len(cb.scanBeamList) == 0 {
 		return 0, false
 	}
-	// pop first value and shift
 	var y float64
 	y, cb.scanBeamList = cb.scanBeamList[0], cb.scanBeamList[1:]
-	for len(cb.scanBeamList) != 0 && y == cb.scanBeamList[0] {
-		// remove duplicate points
-		cb.scanBeamList = cb.scanBeamList[1:]
-	}
 	return y, true
 }
 
 func (cb *ClipperBase) DisposeAllOutRecs() {
-	for idx := range cb.polyOuts {
-		cb.DisposeOutRec(idx)
+	for _, outRec := range cb.polyOuts {
+		if outRec.Pts != nil {
+			DisposeOutPts(outRec.Pts)
+			outRec.Pts = nil
+		}
 	}
 	cb.polyOuts = make([]*OutRec, 0)
 }
 
-func (cb *ClipperBase) DisposeOutRec(idx int) {
-	if len(cb.polyOuts) < idx {
-		return
-	}
-	OutRec := cb.polyOuts[idx]
-	if OutRec.Pts != nil {
-		DisposeOutPts(OutRec.Pts)
-	}
-
-	// Delete index
-	if idx < len(cb.polyOuts)-1 {
-		copy(cb.polyOuts[idx:], cb.polyOuts[idx+1:])
-	}
-	cb.polyOuts[len(cb.polyOuts)-1] = nil // or the zero value of T
-	cb.polyOuts = cb.polyOuts[:len(cb.polyOuts)-1]
-}
-
 func (cb *ClipperBase) DeleteFromAEL(e *TEdge) {
 	AELPrev := e.PrevInAEL
 	AELNext := e.NextInAEL
@@ -568,7 +544,7 @@ func (cb *ClipperBase) CreateOutRec() *OutRec {
 	return result
 }
 
-func (cb *ClipperBase) SwapPositionInAEL(edge1 *TEdge, edge2 *TEdge) {
+func (cb *ClipperBase) SwapPositionsInAEL(edge1 *TEdge, edge2 *TEdge) {
 	// Check that one or other edge hasn't already been removed from AEL
 	if edge1.NextInAEL == edge1.PrevInAEL ||
 		edge2.NextInAEL == edge2.PrevInAEL {
@@ -633,9 +609,11 @@ func (cb *ClipperBase) SwapPositionInAEL(edge1 *TEdge, edge2 *TEdge) {
 	}
 }
 
-func (cb *ClipperBase) UpdateEdgeIntoAEL(e *TEdge) error {
+// UpdateEdgeIntoAEL will replace the edge in the AEL with the next edge in its bound
+// and return the new edge
+func (cb *ClipperBase) UpdateEdgeIntoAEL(e *TEdge) *TEdge {
 	if e.NextInLML == nil {
-		return errors.New("UpdateEdgeIntoAEL: invalid call")
+		panic(clipperError("UpdateEdgeIntoAEL: invalid call"))
 	}
 
 	e.NextInLML.OutIdx = e.OutIdx
@@ -664,9 +642,9 @@ func (cb *ClipperBase) UpdateEdgeIntoAEL(e *TEdge) error {
 	if !IsHorizontal(e) {
 		cb.InsertScanbeam(e.Top.Y)
 	}
-	return nil
+	return e
 }
 
 func (cb *ClipperBase) LocalMinimaPending() bool {
-	return cb.currentLM != len(cb.minimaList)
+	return cb.currentLM < len(cb.minimaList)
 }
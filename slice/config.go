@@ -0,0 +1,478 @@
+package slice
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Configs follow Slic3r's Config.cpp. Every option is defined once in a
+// ConfigOptionDef with its type, default and bounds. Typed config structs tag their
+// fields with the option key, and are read and written through the text form options
+// have in Slic3r .ini files.
+
+// ErrUnknownOption is returned when setting or getting an option that is not defined
+var ErrUnknownOption = errors.New("Unknown config option")
+
+// ErrInvalidOptionValue is returned when an option value can't be parsed or is out of bounds
+var ErrInvalidOptionValue = errors.New("Invalid config option value")
+
+// ErrInvalidConfig is returned when the options of a config don't work together
+var ErrInvalidConfig = errors.New("Invalid config")
+
+// ConfigOptionType tells how an option is stored and written
+type ConfigOptionType int
+
+// Config option types. The plural types hold one value per extruder
+const (
+	CoFloat          ConfigOptionType = iota // float64
+	CoFloats                                 // []float64
+	CoInt                                    // int
+	CoInts                                   // []int
+	CoString                                 // string, new lines written as \n
+	CoStrings                                // []string, separated by ;
+	CoPercent                                // float64 of percents, written with %
+	CoPercents                               // []float64 of percents
+	CoFloatOrPercent                         // FloatOrPercent
+	CoBool                                   // bool, written as 1 or 0
+	CoBools                                  // []bool
+	CoEnum                                   // an int type with a String method and a Parse function in configEnums
+	CoPoints                                 // Points, unscaled and written as XxY
+)
+
+// ConfigOptionDef defines an option. Values are checked against Min and Max when Max
+// is above Min, and enums against EnumValues
+type ConfigOptionDef struct {
+	Key        string
+	Type       ConfigOptionType
+	Label      string
+	Default    string // as written in .ini files
+	Min        float64
+	Max        float64
+	EnumValues []string
+}
+
+// ConfigDef holds option definitions by key
+type ConfigDef map[string]*ConfigOptionDef
+
+// newConfigDef will index the definitions by key
+func newConfigDef(defs ...*ConfigOptionDef) ConfigDef {
+	configDef := make(ConfigDef)
+	for _, def := range defs {
+		configDef[def.Key] = def
+	}
+	return configDef
+}
+
+// bounded will tell if the option has bounds to check
+func (def *ConfigOptionDef) bounded() bool {
+	return def.Max > def.Min
+}
+
+// invalid will return the error of a bad value for the option
+func (def *ConfigOptionDef) invalid(value string, format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s = %q %s", ErrInvalidOptionValue, def.Key, value, fmt.Sprintf(format, args...))
+}
+
+// configFields will map the option keys of a config struct to its fields, looking
+// into embedded configs
+func configFields(config reflect.Value, fields map[string]reflect.Value) map[string]reflect.Value {
+	if fields == nil {
+		fields = make(map[string]reflect.Value)
+	}
+	config = reflect.Indirect(config)
+	for i := 0; i < config.NumField(); i++ {
+		field := config.Type().Field(i)
+		if key, ok := field.Tag.Lookup("config"); ok {
+			fields[key] = config.Field(i)
+		} else if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			configFields(config.Field(i), fields)
+		}
+	}
+	return fields
+}
+
+// configKeys will return the sorted option keys of a config struct
+func configKeys(config interface{}) []string {
+	keys := make([]string, 0)
+	for key := range configFields(reflect.ValueOf(config), nil) {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// applyConfigDefaults will set every option of a config struct to its default
+func applyConfigDefaults(config interface{}) {
+	for key, field := range configFields(reflect.ValueOf(config), nil) {
+		def := PrintConfigDef[key]
+		if err := def.parse(field, def.Default); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// setConfigOption will parse the value into the option of a config struct
+func setConfigOption(config interface{}, key string, value string) error {
+	field, ok := configFields(reflect.ValueOf(config), nil)[key]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownOption, key)
+	}
+	return PrintConfigDef[key].parse(field, value)
+}
+
+// getConfigOption will write the option of a config struct as in .ini files
+func getConfigOption(config interface{}, key string) (string, error) {
+	field, ok := configFields(reflect.ValueOf(config), nil)[key]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownOption, key)
+	}
+	return PrintConfigDef[key].serialize(field), nil
+}
+
+// hasConfigOption will tell if the config struct has the option key
+func hasConfigOption(config interface{}, key string) bool {
+	_, ok := configFields(reflect.ValueOf(config), nil)[key]
+	return ok
+}
+
+// applyConfigOverrides will set the overridden options the config struct has, in key
+// order so the same overrides always fail the same way
+func applyConfigOverrides(config interface{}, overrides map[string]string) error {
+	keys := make([]string, 0, len(overrides))
+	for key := range overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := configFields(reflect.ValueOf(config), nil)
+	for _, key := range keys {
+		if field, ok := fields[key]; ok {
+			if err := PrintConfigDef[key].parse(field, overrides[key]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkConfigBounds will return the problems with the options of a config struct
+// which are out of their bounds, sorted by key
+func checkConfigBounds(config interface{}) []string {
+	problems := make([]string, 0)
+	fields := configFields(reflect.ValueOf(config), nil)
+	for _, key := range configKeys(config) {
+		if err := PrintConfigDef[key].check(fields[key]); err != nil {
+			problems = append(problems, strings.TrimPrefix(err.Error(), ErrInvalidOptionValue.Error()+": "))
+		}
+	}
+	return problems
+}
+
+// configError will join the problems into an ErrInvalidConfig, nil without problems
+func configError(problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidConfig, strings.Join(problems, "; "))
+}
+
+// splitConfigList will split the values of a plural option
+func splitConfigList(value string, separator string) []string {
+	if strings.TrimSpace(value) == "" {
+		return []string{}
+	}
+	parts := strings.Split(value, separator)
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
+
+// formatConfigFloat will write a float the shortest way
+func formatConfigFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// escapeConfigString will write new lines and backslashes so a string fits one line
+func escapeConfigString(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "\n", "\\n", "\r", "\\r").Replace(s)
+}
+
+// unescapeConfigString will undo escapeConfigString
+func unescapeConfigString(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			default:
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// parseFloats will parse the floats of a plural option, with an optional % sign
+func (def *ConfigOptionDef) parseFloats(value string, percent bool) ([]float64, error) {
+	parts := splitConfigList(value, ",")
+	floats := make([]float64, len(parts))
+	for i, part := range parts {
+		if percent {
+			part = strings.TrimSuffix(part, "%")
+		}
+		f, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return nil, def.invalid(value, "is not a number")
+		}
+		floats[i] = f
+	}
+	return floats, nil
+}
+
+// parse will set field from the text form of the option
+func (def *ConfigOptionDef) parse(field reflect.Value, value string) error {
+	value = strings.TrimSpace(value)
+	switch def.Type {
+	case CoFloat, CoPercent:
+		floats, err := def.parseFloats(value, def.Type == CoPercent)
+		if err != nil || len(floats) != 1 {
+			return def.invalid(value, "is not a number")
+		}
+		field.SetFloat(floats[0])
+	case CoFloats, CoPercents:
+		floats, err := def.parseFloats(value, def.Type == CoPercents)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(floats))
+	case CoInt:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return def.invalid(value, "is not an integer")
+		}
+		field.SetInt(int64(i))
+	case CoInts:
+		parts := splitConfigList(value, ",")
+		ints := make([]int, len(parts))
+		for i, part := range parts {
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return def.invalid(value, "is not a list of integers")
+			}
+			ints[i] = n
+		}
+		field.Set(reflect.ValueOf(ints))
+	case CoString:
+		field.SetString(unescapeConfigString(value))
+	case CoStrings:
+		parts := splitConfigList(value, ";")
+		for i := range parts {
+			parts[i] = unescapeConfigString(parts[i])
+		}
+		field.Set(reflect.ValueOf(parts))
+	case CoFloatOrPercent:
+		f, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		if err != nil {
+			return def.invalid(value, "is not a number or percentage")
+		}
+		field.Set(reflect.ValueOf(FloatOrPercent{Value: f, Percent: strings.HasSuffix(value, "%")}))
+	case CoBool:
+		b, err := parseConfigBool(value)
+		if err != nil {
+			return def.invalid(value, "is not 1 or 0")
+		}
+		field.SetBool(b)
+	case CoBools:
+		parts := splitConfigList(value, ",")
+		bools := make([]bool, len(parts))
+		for i, part := range parts {
+			b, err := parseConfigBool(part)
+			if err != nil {
+				return def.invalid(value, "is not a list of 1 or 0")
+			}
+			bools[i] = b
+		}
+		field.Set(reflect.ValueOf(bools))
+	case CoEnum:
+		return def.parseEnum(field, value)
+	case CoPoints:
+		points := NewPoints()
+		for _, part := range splitConfigList(value, ",") {
+			xy := strings.Split(part, "x")
+			if len(xy) != 2 {
+				return def.invalid(value, "is not a list of XxY points")
+			}
+			x, errX := strconv.ParseFloat(xy[0], 64)
+			y, errY := strconv.ParseFloat(xy[1], 64)
+			if errX != nil || errY != nil {
+				return def.invalid(value, "is not a list of XxY points")
+			}
+			points.Push(NewPoint(x, y))
+		}
+		field.Set(reflect.ValueOf(points))
+	}
+	return nil
+}
+
+// parseConfigBool will parse the bools of Slic3r configs
+func parseConfigBool(value string) (bool, error) {
+	switch value {
+	case "1", "true":
+		return true, nil
+	case "0", "false":
+		return false, nil
+	}
+	return false, strconv.ErrSyntax
+}
+
+// configEnums will parse the names of the enum types options hold, by type
+var configEnums = map[reflect.Type]func(string) (int64, error){
+	reflect.TypeOf(SeamPosition(0)): func(name string) (int64, error) {
+		position, err := ParseSeamPosition(name)
+		return int64(position), err
+	},
+	reflect.TypeOf(SupportPattern(0)): func(name string) (int64, error) {
+		pattern, err := ParseSupportPattern(name)
+		return int64(pattern), err
+	},
+	reflect.TypeOf(FillPattern(0)): func(name string) (int64, error) {
+		pattern, err := ParseFillPattern(name)
+		return int64(pattern), err
+	},
+	reflect.TypeOf(GCodeFlavor(0)): func(name string) (int64, error) {
+		flavor, err := ParseGCodeFlavor(name)
+		return int64(flavor), err
+	},
+	reflect.TypeOf(BrimType(0)): func(name string) (int64, error) {
+		brimType, err := ParseBrimType(name)
+		return int64(brimType), err
+	},
+}
+
+// parseEnum will set the enum field to the value named value
+func (def *ConfigOptionDef) parseEnum(field reflect.Value, value string) error {
+	known := false
+	for _, name := range def.EnumValues {
+		known = known || name == value
+	}
+	if parse, ok := configEnums[field.Type()]; ok && known {
+		if i, err := parse(value); err == nil {
+			field.SetInt(i)
+			return nil
+		}
+	}
+	return def.invalid(value, "is not one of %s", strings.Join(def.EnumValues, ", "))
+}
+
+// serialize will write field in the text form of the option
+func (def *ConfigOptionDef) serialize(field reflect.Value) string {
+	switch def.Type {
+	case CoFloat:
+		return formatConfigFloat(field.Float())
+	case CoPercent:
+		return formatConfigFloat(field.Float()) + "%"
+	case CoFloats, CoPercents:
+		parts := make([]string, field.Len())
+		for i := range parts {
+			parts[i] = formatConfigFloat(field.Index(i).Float())
+			if def.Type == CoPercents {
+				parts[i] += "%"
+			}
+		}
+		return strings.Join(parts, ",")
+	case CoInt:
+		return strconv.FormatInt(field.Int(), 10)
+	case CoInts:
+		parts := make([]string, field.Len())
+		for i := range parts {
+			parts[i] = strconv.FormatInt(field.Index(i).Int(), 10)
+		}
+		return strings.Join(parts, ",")
+	case CoString:
+		return escapeConfigString(field.String())
+	case CoStrings:
+		parts := make([]string, field.Len())
+		for i := range parts {
+			parts[i] = escapeConfigString(field.Index(i).String())
+		}
+		return strings.Join(parts, ";")
+	case CoFloatOrPercent:
+		fp := field.Interface().(FloatOrPercent)
+		if fp.Percent {
+			return formatConfigFloat(fp.Value) + "%"
+		}
+		return formatConfigFloat(fp.Value)
+	case CoBool:
+		return serializeConfigBool(field.Bool())
+	case CoBools:
+		parts := make([]string, field.Len())
+		for i := range parts {
+			parts[i] = serializeConfigBool(field.Index(i).Bool())
+		}
+		return strings.Join(parts, ",")
+	case CoEnum:
+		return field.Interface().(fmt.Stringer).String()
+	case CoPoints:
+		points := field.Interface().(Points)
+		parts := make([]string, len(points))
+		for i, point := range points {
+			parts[i] = formatConfigFloat(point.X) + "x" + formatConfigFloat(point.Y)
+		}
+		return strings.Join(parts, ",")
+	}
+	return ""
+}
+
+func serializeConfigBool(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// check will tell if the numbers of field are within the bounds of the option
+func (def *ConfigOptionDef) check(field reflect.Value) error {
+	if !def.bounded() {
+		return nil
+	}
+	values := make([]float64, 0)
+	switch def.Type {
+	case CoFloat, CoPercent:
+		values = append(values, field.Float())
+	case CoInt:
+		values = append(values, float64(field.Int()))
+	case CoFloats, CoPercents:
+		values = append(values, field.Interface().([]float64)...)
+	case CoInts:
+		for _, i := range field.Interface().([]int) {
+			values = append(values, float64(i))
+		}
+	case CoFloatOrPercent:
+		values = append(values, field.Interface().(FloatOrPercent).Value)
+	}
+
+	for _, value := range values {
+		if value < def.Min {
+			return def.invalid(def.serialize(field), "is below the minimum of %s", formatConfigFloat(def.Min))
+		}
+		if value > def.Max {
+			return def.invalid(def.serialize(field), "is above the maximum of %s", formatConfigFloat(def.Max))
+		}
+	}
+	return nil
+}
+
+// noMax bounds options from below only
+var noMax = math.Inf(1)
@@ -0,0 +1,117 @@
+package slice
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Slic3r .ini profiles have one "key = value" option per line. Blank lines and lines
+// starting with # or ; are ignored. Object files also have [section] lines, the
+// options after one belonging to it.
+
+// readIni will call handle with every option of an .ini file and the section it is
+// in, "" before the first [section] line
+func readIni(r io.Reader, handle func(line int, section string, key string, value string) error) error {
+	scanner := bufio.NewScanner(r)
+	// G-code options can be long lines
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	section := ""
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, ";") {
+			continue
+		}
+		if strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]") {
+			section = strings.TrimSpace(text[1 : len(text)-1])
+			if err := handle(line, section, "", ""); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			continue
+		}
+		parts := strings.SplitN(text, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("line %d: %w: %q is not key = value", line, ErrInvalidOptionValue, text)
+		}
+		if err := handle(line, section, strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// writeIniOptions will write the options sorted by key
+func writeIniOptions(out *strings.Builder, options map[string]string) {
+	keys := make([]string, 0, len(options))
+	for key := range options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		out.WriteString(key + " = " + options[key] + "\n")
+	}
+}
+
+// ReadIni will set the options of an .ini profile. Options that are not known are
+// kept in Extra
+func (cfg *FullPrintConfig) ReadIni(r io.Reader) error {
+	if cfg.Extra == nil {
+		cfg.Extra = make(map[string]string)
+	}
+	return readIni(r, func(line int, section string, key string, value string) error {
+		if section != "" {
+			return fmt.Errorf("%w: profiles have no sections, found [%s]", ErrInvalidOptionValue, section)
+		}
+		if _, ok := PrintConfigDef[key]; !ok {
+			cfg.Extra[key] = value
+			return nil
+		}
+		return cfg.Set(key, value)
+	})
+}
+
+// WriteTo will write the options as an .ini profile, sorted by key
+func (cfg *FullPrintConfig) WriteTo(w io.Writer) (int64, error) {
+	values := make(map[string]string)
+	for key, value := range cfg.Extra {
+		values[key] = value
+	}
+	for _, key := range cfg.Keys() {
+		values[key], _ = cfg.Get(key)
+	}
+
+	out := new(strings.Builder)
+	out.WriteString("# generated by goSlicer\n")
+	writeIniOptions(out, values)
+	n, err := io.WriteString(w, out.String())
+	return int64(n), err
+}
+
+// LoadIni will read an .ini profile file
+func (cfg *FullPrintConfig) LoadIni(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return cfg.ReadIni(file)
+}
+
+// SaveIni will write the options to an .ini profile file
+func (cfg *FullPrintConfig) SaveIni(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	buf := bufio.NewWriter(file)
+	if _, err = cfg.WriteTo(buf); err == nil {
+		err = buf.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,133 @@
+package slice
+
+import "math"
+
+// Extruder tracks the E axis of one extruder. This follows Slic3r's Extruder.cpp,
+// lengths are unscaled millimeters of filament or cubic millimeters with volumetric E
+type Extruder struct {
+	ID                  int
+	FilamentDiameter    float64
+	ExtrusionMultiplier float64
+	RetractLength       float64
+	RetractRestartExtra float64
+	RetractSpeed        float64 // mm/s
+	DeretractSpeed      float64 // mm/s, 0 uses RetractSpeed
+	RetractBeforeTravel float64 // travels shorter than this don't retract
+	RetractLayerChange  bool
+
+	// RetractLengthToolchange and RetractRestartExtraToolchange replace the
+	// retraction when switching extruders
+	RetractLengthToolchange       float64
+	RetractRestartExtraToolchange float64
+
+	// RetractLift raises Z while retracted, only from RetractLiftAbove up to
+	// RetractLiftBelow. A RetractLiftBelow of 0 has no upper limit
+	RetractLift      float64
+	RetractLiftAbove float64
+	RetractLiftBelow float64
+
+	// Wipe moves the nozzle back along the last path while retracting, with
+	// RetractBeforeWipe of the retraction done before moving
+	Wipe              bool
+	RetractBeforeWipe float64
+
+	E            float64 // as written to the G-code, reset before every move with relative E
+	AbsoluteE    float64 // filament used so far
+	Retracted    float64
+	RestartExtra float64
+
+	relativeE  bool
+	volumetric bool
+}
+
+// NewExtruder will construct an Extruder with Slic3r's default retraction
+func NewExtruder(id int, filamentDiameter float64) *Extruder {
+	extruder := new(Extruder)
+	extruder.ID = id
+	extruder.FilamentDiameter = filamentDiameter
+	extruder.ExtrusionMultiplier = 1
+	extruder.RetractLength = 2
+	extruder.RetractSpeed = 40
+	extruder.RetractBeforeTravel = 2
+	extruder.RetractLengthToolchange = 10
+	return extruder
+}
+
+// Extrude will move the E axis by dE and return it
+func (extruder *Extruder) Extrude(dE float64) float64 {
+	// in case of relative E distances we always reset to 0 before any output
+	if extruder.relativeE {
+		extruder.E = 0
+	}
+	extruder.E += dE
+	extruder.AbsoluteE += dE
+	if dE < 0 {
+		extruder.Retracted -= dE
+	}
+	return dE
+}
+
+// Retract will pull back the filament up to length in total and return how much it
+// moved. restartExtra is pushed in on top of the length when unretracting
+func (extruder *Extruder) Retract(length float64, restartExtra float64) float64 {
+	if extruder.relativeE {
+		extruder.E = 0
+	}
+	toRetract := math.Max(0, length-extruder.Retracted)
+	if toRetract > 0 {
+		extruder.E -= toRetract
+		extruder.AbsoluteE -= toRetract
+		extruder.Retracted += toRetract
+		extruder.RestartExtra = restartExtra
+	}
+	return toRetract
+}
+
+// Unretract will push the filament back in and return how much it moved
+func (extruder *Extruder) Unretract() float64 {
+	dE := extruder.Retracted + extruder.RestartExtra
+	extruder.Extrude(dE)
+	extruder.Retracted = 0
+	extruder.RestartExtra = 0
+	return dE
+}
+
+// ResetE will set the written E back to 0
+func (extruder *Extruder) ResetE() {
+	extruder.E = 0
+}
+
+// FilamentCrossSection will return the area of the filament
+func (extruder *Extruder) FilamentCrossSection() float64 {
+	return extruder.FilamentDiameter * extruder.FilamentDiameter * math.Pi / 4
+}
+
+// EPerMM3 will return how far E moves to extrude a cubic millimeter
+func (extruder *Extruder) EPerMM3() float64 {
+	if extruder.volumetric {
+		return extruder.ExtrusionMultiplier
+	}
+	return extruder.ExtrusionMultiplier / extruder.FilamentCrossSection()
+}
+
+// EPerMM will return how far E moves per millimeter of an extrusion with the cross
+// section mm3PerMM
+func (extruder *Extruder) EPerMM(mm3PerMM float64) float64 {
+	return mm3PerMM * extruder.EPerMM3()
+}
+
+// UsedFilament will return the length of filament used so far
+func (extruder *Extruder) UsedFilament() float64 {
+	if extruder.volumetric {
+		return extruder.AbsoluteE / extruder.FilamentCrossSection()
+	}
+	return extruder.AbsoluteE
+}
+
+// deretractSpeed will return the speed filament is pushed back in at
+func (extruder *Extruder) deretractSpeed() float64 {
+	if extruder.DeretractSpeed > 0 {
+		return extruder.DeretractSpeed
+	}
+	return extruder.RetractSpeed
+}
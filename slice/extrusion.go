@@ -0,0 +1,644 @@
+package slice
+
+import "math"
+
+// This follows Slic3r's ExtrusionEntity.cpp. Paths are the leaves, loops and multi
+// paths chain several paths together, and collections group any of them.
+
+// ExtrusionRole tells what an extrusion is printed for
+type ExtrusionRole int
+
+// Extrusion roles
+const (
+	ErNone ExtrusionRole = iota
+	ErPerimeter
+	ErExternalPerimeter
+	ErOverhangPerimeter
+	ErInternalInfill
+	ErSolidInfill
+	ErTopSolidInfill
+	ErBridgeInfill
+	ErGapFill
+	ErSkirt
+	ErSupportMaterial
+	ErSupportMaterialInterface
+	ErMixed // collections holding more than one role
+)
+
+var extrusionRoleNames = map[ExtrusionRole]string{
+	ErNone:                     "none",
+	ErPerimeter:                "perimeter",
+	ErExternalPerimeter:        "external perimeter",
+	ErOverhangPerimeter:        "overhang perimeter",
+	ErInternalInfill:           "internal infill",
+	ErSolidInfill:              "solid infill",
+	ErTopSolidInfill:           "top solid infill",
+	ErBridgeInfill:             "bridge infill",
+	ErGapFill:                  "gap fill",
+	ErSkirt:                    "skirt",
+	ErSupportMaterial:          "support material",
+	ErSupportMaterialInterface: "support material interface",
+	ErMixed:                    "mixed",
+}
+
+// String will return the name of the role
+func (role ExtrusionRole) String() string {
+	if name, ok := extrusionRoleNames[role]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// IsPerimeter will tell if the role is any kind of perimeter
+func (role ExtrusionRole) IsPerimeter() bool {
+	return role == ErPerimeter || role == ErExternalPerimeter || role == ErOverhangPerimeter
+}
+
+// IsInfill will tell if the role is any kind of infill
+func (role ExtrusionRole) IsInfill() bool {
+	return role == ErInternalInfill || role.IsSolidInfill()
+}
+
+// IsSolidInfill will tell if the role is solid, top solid or bridge infill
+func (role ExtrusionRole) IsSolidInfill() bool {
+	return role == ErSolidInfill || role == ErTopSolidInfill || role == ErBridgeInfill
+}
+
+// IsBridge will tell if the role is printed over air
+func (role ExtrusionRole) IsBridge() bool {
+	return role == ErBridgeInfill || role == ErOverhangPerimeter
+}
+
+// ExtrusionLoopRole tells what a loop is printed for
+type ExtrusionLoopRole int
+
+// Extrusion loop roles
+const (
+	ElrDefault ExtrusionLoopRole = iota
+	ElrContourInternalPerimeter
+	ElrSkirt
+)
+
+// ExtrusionEntity is anything that can be extruded
+type ExtrusionEntity interface {
+	GetRole() ExtrusionRole
+	IsCollection() bool
+	IsLoop() bool
+	CanReverse() bool
+	Reverse()
+	FirstPoint() *Point
+	LastPoint() *Point
+	Length() float64
+	AsPolyline() *Polyline
+	Clone() ExtrusionEntity
+}
+
+// copyPolyline will copy the polyline and its points
+func copyPolyline(pl *Polyline) *Polyline {
+	copied := NewPolyline()
+	copied.Width = pl.Width
+	for _, point := range pl.MP.Points {
+		copied.MP.Points.Push(NewPoint(point.X, point.Y))
+	}
+	return copied
+}
+
+// ExtrusionPath is a polyline extruded with a single role and flow. Width and
+// Height are scaled, MM3PerMM is the volume per unscaled millimeter
+type ExtrusionPath struct {
+	Polyline *Polyline
+	Role     ExtrusionRole
+	MM3PerMM float64
+	Width    float64
+	Height   float64
+}
+
+// NewExtrusionPath will construct an empty ExtrusionPath
+func NewExtrusionPath(role ExtrusionRole, mm3PerMM float64, width float64, height float64) *ExtrusionPath {
+	path := new(ExtrusionPath)
+	path.Polyline = NewPolyline()
+	path.Role = role
+	path.MM3PerMM = mm3PerMM
+	path.Width = width
+	path.Height = height
+	return path
+}
+
+// NewExtrusionPathFromPolyline will construct an ExtrusionPath with the same flow as
+// path along another polyline
+func NewExtrusionPathFromPolyline(pl *Polyline, path *ExtrusionPath) *ExtrusionPath {
+	copied := NewExtrusionPath(path.Role, path.MM3PerMM, path.Width, path.Height)
+	copied.Polyline = pl
+	return copied
+}
+
+// GetRole will return the role of the path
+func (path *ExtrusionPath) GetRole() ExtrusionRole {
+	return path.Role
+}
+
+// IsCollection will return false
+func (path *ExtrusionPath) IsCollection() bool {
+	return false
+}
+
+// IsLoop will return false
+func (path *ExtrusionPath) IsLoop() bool {
+	return false
+}
+
+// CanReverse will return true, paths can be printed in either direction
+func (path *ExtrusionPath) CanReverse() bool {
+	return true
+}
+
+// Reverse will reverse the direction of the path
+func (path *ExtrusionPath) Reverse() {
+	path.Polyline.MP.Reverse()
+}
+
+// FirstPoint will return where the path starts
+func (path *ExtrusionPath) FirstPoint() *Point {
+	return path.Polyline.MP.Points.First()
+}
+
+// LastPoint will return where the path ends
+func (path *ExtrusionPath) LastPoint() *Point {
+	return path.Polyline.MP.Points.Last()
+}
+
+// Length will return the length of the path
+func (path *ExtrusionPath) Length() float64 {
+	return path.Polyline.MP.Length()
+}
+
+// AsPolyline will return the polyline of the path
+func (path *ExtrusionPath) AsPolyline() *Polyline {
+	return path.Polyline
+}
+
+// Clone will deep copy the path
+func (path *ExtrusionPath) Clone() ExtrusionEntity {
+	return NewExtrusionPathFromPolyline(copyPolyline(path.Polyline), path)
+}
+
+// IsValid will tell if the path has at least one segment
+func (path *ExtrusionPath) IsValid() bool {
+	return len(path.Polyline.MP.Points) >= 2
+}
+
+// ClipEnd will shorten the path by distance
+func (path *ExtrusionPath) ClipEnd(distance float64) {
+	path.Polyline.ClipEnd(distance)
+}
+
+// Simplify will drop points that stray less than tolerance from the path
+func (path *ExtrusionPath) Simplify(tolerance float64) {
+	path.Polyline.Simplify(tolerance)
+}
+
+// clipped will wrap every clipped polyline in a copy of the path
+func (path *ExtrusionPath) clipped(plines Polylines, err error) (ExtrusionPaths, error) {
+	if err != nil {
+		return nil, err
+	}
+	paths := NewExtrusionPaths()
+	for _, pline := range plines {
+		paths.Push(NewExtrusionPathFromPolyline(pline, path))
+	}
+	return paths, nil
+}
+
+// Intersect will return the pieces of the path inside the PolygonExs
+func (path *ExtrusionPath) Intersect(pgxs PolygonExs) (ExtrusionPaths, error) {
+	return path.clipped(IntersectionPolylines(Polylines{path.Polyline}, pgxs.Polygons()))
+}
+
+// Subtract will return the pieces of the path outside the PolygonExs
+func (path *ExtrusionPath) Subtract(pgxs PolygonExs) (ExtrusionPaths, error) {
+	return path.clipped(DiffPolylines(Polylines{path.Polyline}, pgxs.Polygons()))
+}
+
+// ExtrusionMultiPath is a sequence of paths printed one after the other without
+// travel, for example when the flow changes part way along a line
+type ExtrusionMultiPath struct {
+	Paths ExtrusionPaths
+}
+
+// NewExtrusionMultiPath will construct an ExtrusionMultiPath from the paths
+func NewExtrusionMultiPath(paths ...*ExtrusionPath) *ExtrusionMultiPath {
+	multi := new(ExtrusionMultiPath)
+	multi.Paths = NewExtrusionPaths()
+	multi.Paths.Push(paths...)
+	return multi
+}
+
+// GetRole will return the role of the first path
+func (multi *ExtrusionMultiPath) GetRole() ExtrusionRole {
+	if multi.Paths.Empty() {
+		return ErNone
+	}
+	return multi.Paths.First().Role
+}
+
+// IsCollection will return false
+func (multi *ExtrusionMultiPath) IsCollection() bool {
+	return false
+}
+
+// IsLoop will return false
+func (multi *ExtrusionMultiPath) IsLoop() bool {
+	return false
+}
+
+// CanReverse will return true
+func (multi *ExtrusionMultiPath) CanReverse() bool {
+	return true
+}
+
+// Reverse will reverse the order of the paths and each of them
+func (multi *ExtrusionMultiPath) Reverse() {
+	multi.Paths.Reverse()
+}
+
+// FirstPoint will return where the first path starts
+func (multi *ExtrusionMultiPath) FirstPoint() *Point {
+	return multi.Paths.First().FirstPoint()
+}
+
+// LastPoint will return where the last path ends
+func (multi *ExtrusionMultiPath) LastPoint() *Point {
+	return multi.Paths.Last().LastPoint()
+}
+
+// Length will return the length of all paths
+func (multi *ExtrusionMultiPath) Length() float64 {
+	return multi.Paths.Length()
+}
+
+// AsPolyline will join the paths into one polyline
+func (multi *ExtrusionMultiPath) AsPolyline() *Polyline {
+	return multi.Paths.AsPolyline()
+}
+
+// Clone will deep copy the multi path
+func (multi *ExtrusionMultiPath) Clone() ExtrusionEntity {
+	return NewExtrusionMultiPath(multi.Paths.Clone()...)
+}
+
+// ExtrusionLoop is a closed sequence of paths. The last point of the last path is
+// the first point of the first one
+type ExtrusionLoop struct {
+	Paths    ExtrusionPaths
+	LoopRole ExtrusionLoopRole
+}
+
+// NewExtrusionLoop will construct an ExtrusionLoop from the paths
+func NewExtrusionLoop(role ExtrusionLoopRole, paths ...*ExtrusionPath) *ExtrusionLoop {
+	loop := new(ExtrusionLoop)
+	loop.Paths = NewExtrusionPaths()
+	loop.Paths.Push(paths...)
+	loop.LoopRole = role
+	return loop
+}
+
+// NewExtrusionLoopFromPolygon will construct a loop of a single path around the polygon
+func NewExtrusionLoopFromPolygon(pg *Polygon, role ExtrusionRole, loopRole ExtrusionLoopRole, mm3PerMM float64, width float64, height float64) *ExtrusionLoop {
+	path := NewExtrusionPath(role, mm3PerMM, width, height)
+	path.Polyline = pg.SplitAtFirstPoint()
+	return NewExtrusionLoop(loopRole, path)
+}
+
+// GetRole will return the role of the first path
+func (loop *ExtrusionLoop) GetRole() ExtrusionRole {
+	if loop.Paths.Empty() {
+		return ErNone
+	}
+	return loop.Paths.First().Role
+}
+
+// IsCollection will return false
+func (loop *ExtrusionLoop) IsCollection() bool {
+	return false
+}
+
+// IsLoop will return true
+func (loop *ExtrusionLoop) IsLoop() bool {
+	return true
+}
+
+// CanReverse will return false, loops keep their winding when chained
+func (loop *ExtrusionLoop) CanReverse() bool {
+	return false
+}
+
+// Reverse will flip the winding of the loop
+func (loop *ExtrusionLoop) Reverse() {
+	loop.Paths.Reverse()
+}
+
+// FirstPoint will return where the loop starts
+func (loop *ExtrusionLoop) FirstPoint() *Point {
+	return loop.Paths.First().FirstPoint()
+}
+
+// LastPoint will return where the loop ends, which is where it started
+func (loop *ExtrusionLoop) LastPoint() *Point {
+	return loop.Paths.First().FirstPoint()
+}
+
+// Length will return the length of all paths
+func (loop *ExtrusionLoop) Length() float64 {
+	return loop.Paths.Length()
+}
+
+// AsPolyline will return the loop as a polyline closed on its first point
+func (loop *ExtrusionLoop) AsPolyline() *Polyline {
+	return loop.Paths.AsPolyline()
+}
+
+// Clone will deep copy the loop
+func (loop *ExtrusionLoop) Clone() ExtrusionEntity {
+	return NewExtrusionLoop(loop.LoopRole, loop.Paths.Clone()...)
+}
+
+// Polygon will return the loop as a polygon
+func (loop *ExtrusionLoop) Polygon() *Polygon {
+	pg := NewPolygon()
+	for _, path := range loop.Paths {
+		// for each polyline, append all points except the last one (because it coincides with the first one of the next polyline)
+		points := path.Polyline.MP.Points
+		if points.Empty() {
+			continue
+		}
+		for _, point := range points[:len(points)-1] {
+			pg.MP.Points.Push(point)
+		}
+	}
+	return pg
+}
+
+// IsCounterClockwise will tell the winding of the loop
+func (loop *ExtrusionLoop) IsCounterClockwise() bool {
+	return loop.Polygon().IsCounterClockwise()
+}
+
+// MakeCounterClockwise will reverse the loop if it is clockwise. True is returned when reversed
+func (loop *ExtrusionLoop) MakeCounterClockwise() bool {
+	if !loop.IsCounterClockwise() {
+		loop.Reverse()
+		return true
+	}
+	return false
+}
+
+// MakeClockwise will reverse the loop if it is counter clockwise. True is returned when reversed
+func (loop *ExtrusionLoop) MakeClockwise() bool {
+	if loop.IsCounterClockwise() {
+		loop.Reverse()
+		return true
+	}
+	return false
+}
+
+// SplitAtVertex will rotate the loop so it starts at one of its vertices. False is
+// returned if the point is not a vertex of the loop
+func (loop *ExtrusionLoop) SplitAtVertex(point *Point) bool {
+	for pathIdx, path := range loop.Paths {
+		idx, err := path.Polyline.MP.FindPoint(point)
+		if err != nil {
+			continue
+		}
+
+		if len(loop.Paths) == 1 {
+			// just change the order of points
+			pg := NewPolygon()
+			pg.MP.Points = path.Polyline.MP.Points[:len(path.Polyline.MP.Points)-1].GetCopy()
+			path.Polyline.MP.Points = pg.SplitAtIndex(idx % len(pg.MP.Points)).MP.Points
+			return true
+		}
+
+		// new paths list starts with the second half of current path
+		paths := NewExtrusionPaths()
+		second := NewExtrusionPathFromPolyline(NewPolyline(), path)
+		second.Polyline.MP.Points = path.Polyline.MP.Points[idx:].GetCopy()
+		if second.IsValid() {
+			paths.Push(second)
+		}
+		// then we add all paths until the end of current path list, and the ones before it
+		paths.Push(loop.Paths[pathIdx+1:]...)
+		paths.Push(loop.Paths[:pathIdx]...)
+		// finally we add the first half of current path
+		first := NewExtrusionPathFromPolyline(NewPolyline(), path)
+		first.Polyline.MP.Points = path.Polyline.MP.Points[:idx+1].GetCopy()
+		if first.IsValid() {
+			paths.Push(first)
+		}
+		loop.Paths = paths
+		return true
+	}
+	return false
+}
+
+// SplitAt will rotate the loop so it starts at the point on it nearest to point
+func (loop *ExtrusionLoop) SplitAt(point *Point) {
+	if loop.Paths.Empty() {
+		return
+	}
+
+	// find the closest point on any segment of any path
+	minDist := math.Inf(1)
+	var nearest *Point
+	pathIdx, lineIdx := 0, 0
+	for i, path := range loop.Paths {
+		for j, line := range path.Polyline.Lines() {
+			projection := point.ProjectionOntoLine(line)
+			if dist := point.DistanceTo(projection); dist < minDist {
+				minDist = dist
+				nearest = projection
+				pathIdx, lineIdx = i, j
+			}
+		}
+	}
+	if nearest == nil {
+		return
+	}
+
+	nearest = NewPoint(math.Round(nearest.X), math.Round(nearest.Y))
+	if !loop.SplitAtVertex(nearest) {
+		// insert the new vertex into its segment and split there
+		points := &loop.Paths[pathIdx].Polyline.MP.Points
+		*points = append((*points)[:lineIdx+1], append(Points{nearest}, (*points)[lineIdx+1:]...)...)
+		loop.SplitAtVertex(nearest)
+	}
+}
+
+// ClipEnd will return copies of the paths with distance removed from the end
+func (loop *ExtrusionLoop) ClipEnd(distance float64) ExtrusionPaths {
+	paths := loop.Paths.Clone()
+	for distance > 0 && !paths.Empty() {
+		last := paths.Last()
+		length := last.Length()
+		if length <= distance {
+			paths.EraseAt(len(paths) - 1)
+			distance -= length
+			continue
+		}
+		last.ClipEnd(distance)
+		break
+	}
+	return paths
+}
+
+// ExtrusionEntityCollection groups entities. Unless NoSort is set they may be
+// reordered and reversed to shorten travel
+type ExtrusionEntityCollection struct {
+	Entities ExtrusionEntities
+	NoSort   bool
+}
+
+// NewExtrusionEntityCollection will construct a collection of the entities
+func NewExtrusionEntityCollection(entities ...ExtrusionEntity) *ExtrusionEntityCollection {
+	collection := new(ExtrusionEntityCollection)
+	collection.Entities = NewExtrusionEntities()
+	collection.Entities.Push(entities...)
+	return collection
+}
+
+// Append will add entities to the end of the collection
+func (collection *ExtrusionEntityCollection) Append(entities ...ExtrusionEntity) {
+	collection.Entities.Push(entities...)
+}
+
+// AppendPaths will add every path to the end of the collection
+func (collection *ExtrusionEntityCollection) AppendPaths(paths ExtrusionPaths) {
+	for _, path := range paths {
+		collection.Entities.Push(path)
+	}
+}
+
+// Empty will tell if the collection has no entities
+func (collection *ExtrusionEntityCollection) Empty() bool {
+	return collection.Entities.Empty()
+}
+
+// GetRole will return the role shared by every entity, or ErMixed
+func (collection *ExtrusionEntityCollection) GetRole() ExtrusionRole {
+	role := ErNone
+	for _, entity := range collection.Entities {
+		entityRole := entity.GetRole()
+		if role == ErNone {
+			role = entityRole
+		} else if role != entityRole {
+			return ErMixed
+		}
+	}
+	return role
+}
+
+// IsCollection will return true
+func (collection *ExtrusionEntityCollection) IsCollection() bool {
+	return true
+}
+
+// IsLoop will return false
+func (collection *ExtrusionEntityCollection) IsLoop() bool {
+	return false
+}
+
+// CanReverse will return true unless the order is fixed
+func (collection *ExtrusionEntityCollection) CanReverse() bool {
+	return !collection.NoSort
+}
+
+// Reverse will reverse the order of the entities and each entity that can be reversed
+func (collection *ExtrusionEntityCollection) Reverse() {
+	for _, entity := range collection.Entities {
+		// don't reverse it if it's a loop, as it doesn't change anything in terms of elements ordering
+		// and caller might rely on winding order
+		if entity.CanReverse() {
+			entity.Reverse()
+		}
+	}
+	entities := collection.Entities
+	for i, j := 0, len(entities)-1; i < j; i, j = i+1, j-1 {
+		entities[i], entities[j] = entities[j], entities[i]
+	}
+}
+
+// FirstPoint will return where the first entity starts
+func (collection *ExtrusionEntityCollection) FirstPoint() *Point {
+	return collection.Entities.First().FirstPoint()
+}
+
+// LastPoint will return where the last entity ends
+func (collection *ExtrusionEntityCollection) LastPoint() *Point {
+	return collection.Entities.Last().LastPoint()
+}
+
+// Length will return the extruded length of every entity
+func (collection *ExtrusionEntityCollection) Length() float64 {
+	var length float64 = 0
+	for _, entity := range collection.Entities {
+		length += entity.Length()
+	}
+	return length
+}
+
+// AsPolyline will join the polylines of every entity
+func (collection *ExtrusionEntityCollection) AsPolyline() *Polyline {
+	pline := NewPolyline()
+	for _, entity := range collection.Entities {
+		pline.MP.Points.Push(entity.AsPolyline().MP.Points...)
+	}
+	return pline
+}
+
+// Clone will deep copy the collection and its entities
+func (collection *ExtrusionEntityCollection) Clone() ExtrusionEntity {
+	copied := NewExtrusionEntityCollection()
+	copied.NoSort = collection.NoSort
+	for _, entity := range collection.Entities {
+		copied.Append(entity.Clone())
+	}
+	return copied
+}
+
+// ItemsCount will count the entities in the collection and nested collections
+func (collection *ExtrusionEntityCollection) ItemsCount() int {
+	count := 0
+	for _, entity := range collection.Entities {
+		if nested, ok := entity.(*ExtrusionEntityCollection); ok {
+			count += nested.ItemsCount()
+		} else {
+			count++
+		}
+	}
+	return count
+}
+
+// Flatten will return a collection with the entities of every nested collection
+func (collection *ExtrusionEntityCollection) Flatten() *ExtrusionEntityCollection {
+	flat := NewExtrusionEntityCollection()
+	for _, entity := range collection.Entities {
+		if nested, ok := entity.(*ExtrusionEntityCollection); ok {
+			flat.Append(nested.Flatten().Entities...)
+		} else {
+			flat.Append(entity)
+		}
+	}
+	return flat
+}
+
+// ChainedPathFrom will return a collection ordered so that each entity starts near
+// where the previous one ended, starting near startNear. Entities that can be reversed
+// are reversed when their end is closer, unless noReverse is set
+func (collection *ExtrusionEntityCollection) ChainedPathFrom(startNear *Point, noReverse bool) *ExtrusionEntityCollection {
+	if collection.NoSort {
+		return collection.Clone().(*ExtrusionEntityCollection)
+	}
+
+	chained := NewExtrusionEntityCollection()
+	chained.Append(ChainEntities(collection.Entities, startNear, noReverse)...)
+	return chained
+}
@@ -0,0 +1,428 @@
+package slice
+
+import (
+	"errors"
+	"math"
+)
+
+// Fills follow Slic3r's Fill directory. Every pattern fills a single PolygonEx and
+// returns the polylines to extrude. Spacings are scaled and angles are in radians
+
+// ErrUnknownFillPattern is returned when asking for a pattern that does not exist
+var ErrUnknownFillPattern = errors.New("Unknown fill pattern")
+
+// InfillOverlapOverSpacing is how far, relative to the spacing, lines are extended
+// past the region so they bond with the perimeters
+const InfillOverlapOverSpacing float64 = 0.3
+
+// FillPattern names an infill pattern
+type FillPattern int
+
+// Fill patterns
+const (
+	FpRectilinear FillPattern = iota
+	FpGrid
+	FpLine
+	FpCubic
+	FpTriangles
+	FpHoneycomb
+	Fp3DHoneycomb
+	FpGyroid
+	FpHilbertCurve
+	FpArchimedeanChords
+	FpOctagramSpiral
+	FpConcentric
+	FpAlignedRectilinear
+	FpStars
+)
+
+var fillPatternNames = map[FillPattern]string{
+	FpRectilinear:        "rectilinear",
+	FpGrid:               "grid",
+	FpLine:               "line",
+	FpCubic:              "cubic",
+	FpTriangles:          "triangles",
+	FpHoneycomb:          "honeycomb",
+	Fp3DHoneycomb:        "3dhoneycomb",
+	FpGyroid:             "gyroid",
+	FpHilbertCurve:       "hilbertcurve",
+	FpArchimedeanChords:  "archimedeanchords",
+	FpOctagramSpiral:     "octagramspiral",
+	FpConcentric:         "concentric",
+	FpAlignedRectilinear: "alignedrectilinear",
+	FpStars:              "stars",
+}
+
+// String will return the name of the pattern as used in Slic3r configs
+func (pattern FillPattern) String() string {
+	if name, ok := fillPatternNames[pattern]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseFillPattern will find the pattern by its name
+func ParseFillPattern(name string) (FillPattern, error) {
+	for pattern, patternName := range fillPatternNames {
+		if patternName == name {
+			return pattern, nil
+		}
+	}
+	return FpRectilinear, ErrUnknownFillPattern
+}
+
+// FillParams holds what a fill needs besides the region
+type FillParams struct {
+	LayerID int
+	Z       float64 // unscaled height of the layer, for patterns that change along Z
+	Density float64 // 0 to 1
+	Angle   float64 // base angle of the pattern
+	Spacing float64 // distance between lines at full density
+
+	// BoundingBox of the whole object so patterns line up between layers. When nil
+	// the bounding box of the region is used
+	BoundingBox *BoundingBox
+
+	DontConnect bool // keep every line separate
+	DontAdjust  bool // keep the spacing of solid fills instead of fitting the region
+	Complete    bool // close every loop of patterns made of loops
+	Bridge      bool // Angle is a bridge direction which is not turned between layers
+}
+
+// NewFillParams will construct FillParams
+func NewFillParams(layerID int, density float64, angle float64, spacing float64) *FillParams {
+	params := new(FillParams)
+	params.LayerID = layerID
+	params.Density = density
+	params.Angle = angle
+	params.Spacing = spacing
+	return params
+}
+
+// NewFillParamsForSurface will construct FillParams for a surface, bridges with a
+// detected angle are filled along it
+func NewFillParamsForSurface(surface *Surface, layerID int, density float64, angle float64, spacing float64) *FillParams {
+	params := NewFillParams(layerID, density, angle, spacing)
+	if surface.IsBridge() && surface.BridgeAngle >= 0 {
+		params.Angle = surface.BridgeAngle
+		params.Bridge = true
+	}
+	return params
+}
+
+// Fill generates the polylines that fill a region
+type Fill interface {
+	FillSurface(pgx *PolygonEx, params *FillParams) (Polylines, error)
+}
+
+// NewFill will construct the fill for a pattern
+func NewFill(pattern FillPattern) (Fill, error) {
+	switch pattern {
+	case FpRectilinear:
+		return new(FillRectilinear), nil
+	case FpGrid:
+		return new(FillGrid), nil
+	case FpLine:
+		return new(FillLine), nil
+	case FpCubic:
+		return new(FillCubic), nil
+	case FpTriangles:
+		return new(FillTriangles), nil
+	case FpHoneycomb:
+		return new(FillHoneycomb), nil
+	case Fp3DHoneycomb:
+		return new(Fill3DHoneycomb), nil
+	case FpGyroid:
+		return new(FillGyroid), nil
+	case FpHilbertCurve:
+		return new(FillHilbertCurve), nil
+	case FpArchimedeanChords:
+		return new(FillArchimedeanChords), nil
+	case FpOctagramSpiral:
+		return new(FillOctagramSpiral), nil
+	case FpConcentric:
+		return new(FillConcentric), nil
+	case FpAlignedRectilinear:
+		return new(FillAlignedRectilinear), nil
+	case FpStars:
+		return new(FillStars), nil
+	}
+	return nil, ErrUnknownFillPattern
+}
+
+// alternatingLayerAngle will turn the pattern a quarter turn on every other layer
+func alternatingLayerAngle(layerID int) float64 {
+	if layerID%2 == 1 {
+		return math.Pi / 2
+	}
+	return 0
+}
+
+// infillDirection will return the angle of the pattern and the point it is aligned to
+func infillDirection(pgx *PolygonEx, params *FillParams, layerAngle func(int) float64) (float64, *Point) {
+	angle := params.Angle
+	if layerAngle != nil && !params.Bridge {
+		angle += layerAngle(params.LayerID)
+	}
+	// the bounding box is only undefined in unit tests
+	if params.BoundingBox != nil && params.BoundingBox.Defined() {
+		return angle + math.Pi/2, params.BoundingBox.Center()
+	}
+	return angle + math.Pi/2, NewBoundingBox(pgx.Contour.MP.Points...).Center()
+}
+
+// alignToGrid will round coord down onto the grid of spacing passing through base
+func alignToGrid(coord float64, spacing float64, base float64) float64 {
+	return base + math.Floor((coord-base)/spacing)*spacing
+}
+
+// AdjustSolidSpacing will shrink distance so a whole number of lines fits width,
+// without widening the lines by more than 20%
+func AdjustSolidSpacing(width float64, distance float64) float64 {
+	intervals := math.Floor(width / distance)
+	if intervals == 0 {
+		return distance
+	}
+	distanceNew := math.Floor(width / intervals)
+	// how much could the extrusion width be increased? By 20%
+	if factorMax := 1.2; distanceNew/distance > factorMax {
+		distanceNew = math.Floor(distance*factorMax + 0.5)
+	}
+	return distanceNew
+}
+
+// rectilinearFill holds the variations of the rectilinear fill
+type rectilinearFill struct {
+	layerAngle      func(int) float64
+	horizontalLines bool
+	oscillate       bool
+	spacingFactor   float64 // grids spread their lines over two directions
+	angleBase       float64 // added to the direction of the lines
+	patternShift    float64 // scaled shift of the lines across their direction
+}
+
+// FillRectilinear fills with parallel lines, turning a quarter turn every layer
+type FillRectilinear struct{}
+
+// FillSurface will fill the region with parallel lines
+func (fill *FillRectilinear) FillSurface(pgx *PolygonEx, params *FillParams) (Polylines, error) {
+	return rectilinearFill{layerAngle: alternatingLayerAngle, spacingFactor: 1}.fillSurface(pgx, params)
+}
+
+// FillAlignedRectilinear fills with parallel lines at the same angle on every layer
+type FillAlignedRectilinear struct{}
+
+// FillSurface will fill the region with parallel lines
+func (fill *FillAlignedRectilinear) FillSurface(pgx *PolygonEx, params *FillParams) (Polylines, error) {
+	return rectilinearFill{spacingFactor: 1}.fillSurface(pgx, params)
+}
+
+// FillGrid fills with lines in two directions, keeping the same angle every layer
+type FillGrid struct{}
+
+// FillSurface will fill the region with a grid
+func (fill *FillGrid) FillSurface(pgx *PolygonEx, params *FillParams) (Polylines, error) {
+	return rectilinearFill{horizontalLines: true, spacingFactor: 2}.fillSurface(pgx, params)
+}
+
+// FillLine fills with lines that lean back and forth to use up the spare spacing
+type FillLine struct{}
+
+// FillSurface will fill the region with leaning lines
+func (fill *FillLine) FillSurface(pgx *PolygonEx, params *FillParams) (Polylines, error) {
+	return rectilinearFill{layerAngle: alternatingLayerAngle, oscillate: true, spacingFactor: 1}.fillSurface(pgx, params)
+}
+
+// FillCubic fills with three sets of lines 60 degrees apart that shift with Z,
+// stacking into cubes standing on a corner. The angle stays the same on every layer
+// for the cubes to line up
+type FillCubic struct{}
+
+// FillSurface will fill the region with one layer of cubes
+func (fill *FillCubic) FillSurface(pgx *PolygonEx, params *FillParams) (Polylines, error) {
+	dx := Scale(math.Sqrt(0.5) * params.Z)
+	return fillDirections(pgx, params, []rectilinearFill{
+		{spacingFactor: 3, patternShift: dx},
+		{spacingFactor: 3, angleBase: math.Pi / 3, patternShift: -dx},
+		// rotated by PI*2/3 + PI to achieve reverse sloping wall
+		{spacingFactor: 3, angleBase: math.Pi * 2 / 3, patternShift: dx},
+	})
+}
+
+// FillTriangles fills with three sets of lines 60 degrees apart crossing in the same points,
+// keeping the same angle every layer
+type FillTriangles struct{}
+
+// FillSurface will fill the region with triangles
+func (fill *FillTriangles) FillSurface(pgx *PolygonEx, params *FillParams) (Polylines, error) {
+	return fillDirections(pgx, params, []rectilinearFill{
+		{spacingFactor: 3},
+		{spacingFactor: 3, angleBase: math.Pi / 3},
+		{spacingFactor: 3, angleBase: math.Pi * 2 / 3},
+	})
+}
+
+// FillStars fills like FillTriangles with the third set of lines shifted by half a
+// line, so they cross the others in six pointed stars. The angle stays the same on
+// every layer
+type FillStars struct{}
+
+// FillSurface will fill the region with stars
+func (fill *FillStars) FillSurface(pgx *PolygonEx, params *FillParams) (Polylines, error) {
+	shift := 0.0
+	if params.Density > 0 {
+		shift = math.Round(params.Spacing*3/params.Density) / 2
+	}
+	return fillDirections(pgx, params, []rectilinearFill{
+		{spacingFactor: 3},
+		{spacingFactor: 3, angleBase: math.Pi / 3},
+		{spacingFactor: 3, angleBase: math.Pi * 2 / 3, patternShift: shift},
+	})
+}
+
+// fillDirections will fill the region once per direction. Only the first direction is connected
+func fillDirections(pgx *PolygonEx, params *FillParams, fills []rectilinearFill) (Polylines, error) {
+	plines := NewPolylines()
+	for i, rf := range fills {
+		dirParams := *params
+		dirParams.DontConnect = params.DontConnect || i > 0
+		filled, err := rf.fillSurface(pgx, &dirParams)
+		if err != nil {
+			return nil, err
+		}
+		plines.Push(filled...)
+	}
+	return plines, nil
+}
+
+func (rf rectilinearFill) fillSurface(pgx *PolygonEx, params *FillParams) (Polylines, error) {
+	if params.Density <= 0 || params.Spacing <= 0 {
+		return NewPolylines(), nil
+	}
+
+	// rotate polygons so that we can work with vertical lines here
+	angle, shift := infillDirection(pgx, params, rf.layerAngle)
+	angle += rf.angleBase
+	region := pgx.GetCopy()
+	region.Rotate(-angle)
+
+	minSpacing := params.Spacing
+	lineSpacing := math.Round(minSpacing * rf.spacingFactor / params.Density)
+	bb := NewBoundingBox(region.Contour.MP.Points...)
+	if params.Density > 0.9999 && !params.DontAdjust && !rf.horizontalLines {
+		lineSpacing = AdjustSolidSpacing(bb.Size().X, lineSpacing)
+	} else {
+		// extend bounding box so that our pattern will be aligned with other layers
+		base := shift.Rotated(-angle)
+		base.X -= rf.patternShift
+		bb.MergePoint(NewPoint(alignToGrid(bb.Min.X, lineSpacing, base.X), alignToGrid(bb.Min.Y, lineSpacing, base.Y)))
+	}
+	diagonalDistance := lineSpacing * 2
+	var oscillation float64 = 0
+	if rf.oscillate {
+		oscillation = lineSpacing - minSpacing
+	}
+
+	// generate the basic pattern
+	lines := NewPolylines()
+	for x, i := bb.Min.X, 0; x <= bb.Max.X+ScaledEpsilon; x, i = x+lineSpacing, i+1 {
+		var osc float64 = 0
+		if i%2 == 1 {
+			osc = oscillation
+		}
+		lines.Push(polylineOf(NewPoint(x-osc, bb.Min.Y), NewPoint(x+osc, bb.Max.Y)))
+	}
+	if rf.horizontalLines {
+		for y := bb.Min.Y; y <= bb.Max.Y+ScaledEpsilon; y += lineSpacing {
+			lines.Push(polylineOf(NewPoint(bb.Min.X, y), NewPoint(bb.Max.X, y)))
+		}
+	}
+
+	// clip paths against a slightly larger region, so that the first and last paths
+	// are kept even if the region has vertical sides
+	grown, err := Offset(region.Polygons(), Scale(0.02))
+	if err != nil {
+		return nil, err
+	}
+	plines, err := IntersectionPolylines(lines, grown)
+	if err != nil {
+		return nil, err
+	}
+
+	// extend the lines past the region along their axis so they bond with the perimeters
+	extra := math.Floor(minSpacing*InfillOverlapOverSpacing + 0.5)
+	for _, pline := range plines {
+		points := pline.MP.Points
+		first, last := 0, len(points)-1
+		start, end := NewPoint(points[first].X, points[first].Y), NewPoint(points[last].X, points[last].Y)
+		if math.Abs(end.Y-start.Y) >= math.Abs(end.X-start.X) {
+			if start.Y > end.Y {
+				start, end, first, last = end, start, last, first
+			}
+			start.Y -= extra
+			end.Y += extra
+		} else {
+			if start.X > end.X {
+				start, end, first, last = end, start, last, first
+			}
+			start.X -= extra
+			end.X += extra
+		}
+		points[first], points[last] = start, end
+	}
+
+	if !params.DontConnect && !plines.Empty() {
+		connect := func(dx, dy float64) bool {
+			tolerance := 10 * ScaledEpsilon
+			return dx >= lineSpacing-oscillation-tolerance && dx <= lineSpacing+oscillation+tolerance &&
+				dy <= diagonalDistance
+		}
+		if plines, err = connectFillLines(plines, region, minSpacing/2, connect); err != nil {
+			return nil, err
+		}
+	}
+
+	// paths must be rotated back
+	for _, pline := range plines {
+		pline.MP.Rotate(angle)
+	}
+	return plines, nil
+}
+
+// connectFillLines will chain the lines and join the ends that can be connected by a
+// short link staying inside the region grown by margin
+func connectFillLines(plines Polylines, region *PolygonEx, margin float64, canConnect func(dx, dy float64) bool) (Polylines, error) {
+	boundary, err := Offset(region.Polygons(), margin)
+	if err != nil {
+		return nil, err
+	}
+	inside := func(line *Line) bool {
+		outside, err := DiffPolylines(Polylines{polylineOf(line.A, line.B)}, boundary)
+		return err == nil && outside.Empty()
+	}
+
+	connected := NewPolylines()
+	for _, pline := range plines.ChainedPathFrom(plines.LeftmostPoint(), false) {
+		if !connected.Empty() {
+			// figure out what joint to use
+			last := connected.Last()
+			first, end := pline.MP.Points.First(), last.MP.Points.Last()
+			// TODO: we should also check that both points are on a fill_boundary to avoid
+			// connecting paths on the boundaries of internal regions
+			if canConnect(math.Abs(first.X-end.X), math.Abs(first.Y-end.Y)) && inside(NewLine(end, first)) {
+				last.MP.Points.Push(pline.MP.Points...)
+				continue
+			}
+		}
+		// the lines cannot be connected
+		connected.Push(pline)
+	}
+	return connected, nil
+}
+
+// polylineOf will make a polyline through the points
+func polylineOf(points ...*Point) *Polyline {
+	pline := NewPolyline()
+	pline.MP.Points.Push(points...)
+	return pline
+}
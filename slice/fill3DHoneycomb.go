@@ -0,0 +1,134 @@
+package slice
+
+import "math"
+
+// Fill3DHoneycomb fills with horizontal slices of the edges of a space filling
+// truncated octahedron tessellation. The octahedrons are oriented so that the
+// square faces are in the horizontal plane with edges parallel to the X and Y axes.
+// Credits: David Eccles (gringer). The pattern cannot be rotated
+type Fill3DHoneycomb struct{}
+
+// colinearPoints will generate the coordinates along the printing direction
+// (Y for columns, X for rows). A negative offset only changes the perpendicular direction
+func colinearPoints(offset float64, baseLocation int, gridLength int) []float64 {
+	offset2 := math.Abs(offset / 2)
+	points := make([]float64, 0, 2*gridLength+2)
+	base := float64(baseLocation)
+	points = append(points, base-offset2)
+	for i := 0; i < gridLength; i++ {
+		points = append(points, base+float64(i)+offset2, base+float64(i)+1-offset2)
+	}
+	return append(points, base+float64(gridLength)+offset2)
+}
+
+// perpendPoints will generate the coordinates across the printing direction
+// (X for columns, Y for rows)
+func perpendPoints(offset float64, baseLocation int, gridLength int) []float64 {
+	offset2 := offset / 2
+	side := float64(2*(baseLocation&1) - 1)
+	points := make([]float64, 0, 2*gridLength+2)
+	base := float64(baseLocation)
+	points = append(points, base-offset2*side)
+	for i := 0; i < gridLength; i++ {
+		side = float64(2*((i+baseLocation)&1) - 1)
+		points = append(points, base+offset2*side, base+offset2*side)
+	}
+	return append(points, base-offset2*side)
+}
+
+// zipTrimmed will pair up the coordinates, clamping them to the grid
+func zipTrimmed(xs []float64, ys []float64, maxX float64, maxY float64) Points {
+	points := make(Points, 0, len(xs))
+	for i := range xs {
+		points = append(points, NewPoint(math.Max(0, math.Min(maxX, xs[i])), math.Max(0, math.Min(maxY, ys[i]))))
+	}
+	return points
+}
+
+// makeNormalisedGrid will generate the curves of a horizontal slice of a truncated
+// regular octahedron with edge length 1. curveType 1 gives columns, 2 rows and 3 both
+func makeNormalisedGrid(z float64, gridWidth int, gridHeight int, curveType int) []Points {
+	// offset required to create a regular octagram
+	octagramGap := 0.5
+
+	// sawtooth wave function for range f(z) = [-octagramGap .. octagramGap]
+	a := math.Sqrt(2) // period
+	wave := math.Abs(math.Mod(z, a)-a/2)/a*4 - 1
+	offset := wave * octagramGap
+
+	curves := make([]Points, 0)
+	if curveType&1 != 0 {
+		for x := 0; x <= gridWidth; x++ {
+			points := zipTrimmed(perpendPoints(offset, x, gridHeight), colinearPoints(offset, 0, gridHeight),
+				float64(gridWidth), float64(gridHeight))
+			if x&1 == 1 {
+				reversePoints(points)
+			}
+			curves = append(curves, points)
+		}
+	}
+	if curveType&2 != 0 {
+		for y := 0; y <= gridHeight; y++ {
+			points := zipTrimmed(colinearPoints(offset, 0, gridWidth), perpendPoints(offset, y, gridWidth),
+				float64(gridWidth), float64(gridHeight))
+			if y&1 == 1 {
+				reversePoints(points)
+			}
+			curves = append(curves, points)
+		}
+	}
+	return curves
+}
+
+// makeGrid will generate the curves of a horizontal slice of a truncated octahedron
+// tessellation with a scaled grid square size
+func makeGrid(z float64, gridSize float64, gridWidth int, gridHeight int, curveType int) Polylines {
+	plines := NewPolylines()
+	for _, curve := range makeNormalisedGrid(z/gridSize, gridWidth, gridHeight, curveType) {
+		pline := NewPolyline()
+		for _, point := range curve {
+			pline.MP.Points.Push(NewPoint(math.Trunc(point.X*gridSize), math.Trunc(point.Y*gridSize)))
+		}
+		plines.Push(pline)
+	}
+	return plines
+}
+
+// FillSurface will fill the region with the slice of the tessellation at the layer Z
+func (fill *Fill3DHoneycomb) FillSurface(pgx *PolygonEx, params *FillParams) (Polylines, error) {
+	if params.Density <= 0 || params.Spacing <= 0 {
+		return NewPolylines(), nil
+	}
+
+	// no rotation is supported for this infill pattern
+	bb := NewBoundingBox(pgx.Contour.MP.Points...)
+	distance := math.Trunc(params.Spacing / params.Density)
+
+	// align bounding box to a multiple of our honeycomb grid module (a module is
+	// 2*distance since one distance half-module is growing while the other
+	// distance half-module is shrinking)
+	bb.MergePoint(NewPoint(alignToGrid(bb.Min.X, 2*distance, 0), alignToGrid(bb.Min.Y, 2*distance, 0)))
+
+	// generate pattern
+	size := bb.Size()
+	plines := makeGrid(Scale(params.Z), distance,
+		int(math.Ceil(size.X/distance))+1, int(math.Ceil(size.Y/distance))+1,
+		params.LayerID%2+1)
+
+	// move pattern in place
+	for _, pline := range plines {
+		pline.MP.Translate(bb.Min)
+	}
+
+	// clip pattern to boundaries
+	plines, err := IntersectionPolylines(plines, pgx.Polygons())
+	if err != nil || plines.Empty() || params.DontConnect {
+		return plines, err
+	}
+
+	// connect lines
+	canConnect := func(dx, dy float64) bool {
+		return math.Hypot(dx, dy) <= 1.5*distance
+	}
+	return connectFillLines(plines, pgx, ScaledEpsilon, canConnect)
+}
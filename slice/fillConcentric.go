@@ -0,0 +1,58 @@
+package slice
+
+import "math"
+
+// FillConcentric fills with loops following the outline of the region, offset
+// inwards by the line distance until nothing is left. Used for top surfaces and
+// small regions
+type FillConcentric struct{}
+
+// concentricClipping is the share of the spacing clipped from the end of every loop,
+// so the extruder doesn't get exactly on the first point of the loop
+const concentricClipping float64 = 0.15
+
+// FillSurface will fill the region with concentric loops
+func (fill *FillConcentric) FillSurface(pgx *PolygonEx, params *FillParams) (Polylines, error) {
+	if params.Density <= 0 || params.Spacing <= 0 {
+		return NewPolylines(), nil
+	}
+
+	minSpacing := params.Spacing
+	distance := math.Round(minSpacing / params.Density)
+	if params.Density > 0.9999 && !params.DontAdjust {
+		// fit a whole number of loops across the region
+		bb := NewBoundingBox(pgx.Contour.MP.Points...)
+		distance = AdjustSolidSpacing(bb.Size().X, distance)
+	}
+
+	loops, err := concentricLoops(pgx, minSpacing, distance)
+	if err != nil || loops.Empty() {
+		return NewPolylines(), err
+	}
+
+	// split the loops at the vertex nearest the previous loop's end
+	plines := NewPolylines()
+	for _, pline := range ChainPolygons(loops, NewPoint(0, 0)) {
+		pline.ClipEnd(concentricClipping * minSpacing)
+		if len(pline.MP.Points) >= 2 {
+			plines.Push(pline)
+		}
+	}
+	return plines, nil
+}
+
+// concentricLoops will offset the region inwards by distance until it vanishes,
+// outermost loops first. The region is already inset from the perimeters, so the
+// first loop is its outline
+func concentricLoops(pgx *PolygonEx, minSpacing float64, distance float64) (Polygons, error) {
+	loops := pgx.Polygons()
+	var err error
+	for last := loops; !last.Empty(); {
+		// the second offset drops the slivers thinner than a line
+		if last, err = Offset2(last, -(distance + minSpacing/2), minSpacing/2); err != nil {
+			return nil, err
+		}
+		loops = append(loops, last...)
+	}
+	return loops, nil
+}
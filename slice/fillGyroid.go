@@ -0,0 +1,226 @@
+package slice
+
+import (
+	"math"
+	"sort"
+)
+
+// FillGyroid follows PrusaSlicer's FillGyroid.cpp. Each layer is a horizontal slice of
+// the gyroid surface sin(x)cos(y) + sin(y)cos(z) + sin(z)cos(x) = 0, approximated by
+// waves that run along X or Y depending on Z
+type FillGyroid struct{}
+
+const (
+	// gyroidPatternTolerance is the maximum deviation from the wave in unscaled mm
+	gyroidPatternTolerance float64 = 0.2
+	// gyroidDensityAdjust keeps the weight of the infill close to the requested density
+	gyroidDensityAdjust float64 = 2.44
+	// gyroidCorrectionAngle lines the waves up with the rectilinear patterns
+	gyroidCorrectionAngle float64 = -45
+)
+
+// gyroidWave will return the wave of the gyroid slice at x
+func gyroidWave(x float64, zSin float64, zCos float64, vertical bool, flip bool) float64 {
+	if vertical {
+		phaseOffset := math.Pi
+		if zCos < 0 {
+			phaseOffset += math.Pi
+		}
+		a := math.Sin(x + phaseOffset)
+		b := -zCos
+		flipOffset := 0.0
+		if flip {
+			flipOffset = math.Pi
+		}
+		res := zSin * math.Cos(x+phaseOffset+flipOffset)
+		r := math.Sqrt(a*a + b*b)
+		return math.Asin(a/r) + math.Asin(res/r) + math.Pi
+	}
+
+	phaseOffset := 0.0
+	if zSin < 0 {
+		phaseOffset = math.Pi
+	}
+	a := math.Cos(x + phaseOffset)
+	b := -zSin
+	flipOffset := math.Pi
+	if flip {
+		flipOffset = 0
+	}
+	res := zCos * math.Sin(x+phaseOffset+flipOffset)
+	r := math.Sqrt(a*a + b*b)
+	return math.Asin(a/r) + math.Asin(res/r) + 0.5*math.Pi
+}
+
+// gyroidPeriod will sample one period of the wave finely enough to stay within tolerance
+func gyroidPeriod(width float64, zCos float64, zSin float64, vertical bool, flip bool, tolerance float64) Points {
+	points := NewPoints()
+	limit := math.Min(2*math.Pi, width)
+	// exact coordinates on main inflexion lobes
+	for x := 0.0; x < limit-Epsilon; x += math.Pi / 2 {
+		points.Push(NewPoint(x, gyroidWave(x, zSin, zCos, vertical, flip)))
+	}
+	points.Push(NewPoint(limit, gyroidWave(limit, zSin, zCos, vertical, flip)))
+
+	// piecewise increase in resolution up to requested tolerance
+	for {
+		size := len(points)
+		for i := 1; i < size; i++ {
+			lp, rp := points[i-1], points[i]
+			x := lp.X + (rp.X-lp.X)/2
+			ip := NewPoint(x, gyroidWave(x, zSin, zCos, vertical, flip))
+			cross := (ip.X-lp.X)*(ip.Y-rp.Y) - (ip.Y-lp.Y)*(ip.X-rp.X)
+			if math.Abs(cross) > tolerance*tolerance {
+				points.Push(ip)
+			}
+		}
+		if size == len(points) {
+			break
+		}
+		// insert new points in order
+		sort.Slice(points, func(i, j int) bool { return points[i].X < points[j].X })
+	}
+	return points
+}
+
+// gyroidWavePolyline will repeat one period of the wave across width, shift it by
+// offset and scale it into a polyline
+func gyroidWavePolyline(onePeriod Points, width float64, height float64, offset float64, scaleFactor float64,
+	zCos float64, zSin float64, vertical bool, flip bool) *Polyline {
+	points := make(Points, 0, len(onePeriod))
+	for _, point := range onePeriod {
+		points.Push(NewPoint(point.X, point.Y))
+	}
+	period := points.Last().X
+	// do not extend if already truncated
+	if width != period {
+		points.PopBack()
+		n := len(points)
+		for {
+			points.Push(NewPoint(points[len(points)-n].X+period, points[len(points)-n].Y))
+			if points.Last().X >= width-Epsilon {
+				break
+			}
+		}
+		points.Push(NewPoint(width, gyroidWave(width, zSin, zCos, vertical, flip)))
+	}
+
+	// and construct the final polyline to return
+	pline := NewPolyline()
+	for _, point := range points {
+		x, y := point.X, math.Max(0, math.Min(height, point.Y+offset))
+		if vertical {
+			x, y = y, x
+		}
+		pline.MP.Points.Push(NewPoint(math.Trunc(x*scaleFactor), math.Trunc(y*scaleFactor)))
+	}
+	return pline
+}
+
+// gyroidWaves will generate the waves of the gyroid slice at gridZ. Width and
+// height are in units of the wave period
+func gyroidWaves(gridZ float64, densityAdjusted float64, lineSpacing float64, width float64, height float64) Polylines {
+	scaleFactor := lineSpacing / densityAdjusted
+
+	// tolerance in wave units. Clamp the maximum tolerance as there's no
+	// processing-speed benefit to do so beyond a certain point
+	tolerance := math.Min(UnScale(lineSpacing)/2, gyroidPatternTolerance) / UnScale(scaleFactor)
+
+	z := gridZ / scaleFactor
+	zSin, zCos := math.Sin(z), math.Cos(z)
+
+	vertical := math.Abs(zSin) <= math.Abs(zCos)
+	lowerBound, upperBound := 0.0, height
+	flip := true
+	if vertical {
+		flip = false
+		lowerBound = -math.Pi
+		upperBound = width - math.Pi/2
+		width, height = height, width
+	}
+
+	// one period of the waves so it doesn't have to be recalculated all the time
+	onePeriodOdd := gyroidPeriod(width, zCos, zSin, vertical, flip, tolerance)
+	// even polylines are a bit shifted
+	flip = !flip
+	onePeriodEven := gyroidPeriod(width, zCos, zSin, vertical, flip, tolerance)
+
+	plines := NewPolylines()
+	for y0 := lowerBound; y0 < upperBound+Epsilon; y0 += math.Pi {
+		// creates odd polylines
+		plines.Push(gyroidWavePolyline(onePeriodOdd, width, height, y0, scaleFactor, zCos, zSin, vertical, flip))
+		// creates even polylines
+		y0 += math.Pi
+		if y0 < upperBound+Epsilon {
+			plines.Push(gyroidWavePolyline(onePeriodEven, width, height, y0, scaleFactor, zCos, zSin, vertical, flip))
+		}
+	}
+	return plines
+}
+
+// FillSurface will fill the region with the gyroid slice at the layer Z
+func (fill *FillGyroid) FillSurface(pgx *PolygonEx, params *FillParams) (Polylines, error) {
+	if params.Density <= 0 || params.Spacing <= 0 {
+		return NewPolylines(), nil
+	}
+
+	angle := params.Angle + gyroidCorrectionAngle*2*math.Pi/360
+	region := pgx.GetCopy()
+	region.Rotate(-angle)
+
+	bb := NewBoundingBox(region.Contour.MP.Points...)
+	// density adjusted to have a good % of weight
+	densityAdjusted := params.Density * gyroidDensityAdjust
+	// distance between the gyroid waves in scaled coordinates
+	distance := math.Trunc(params.Spacing / densityAdjusted)
+
+	// align bounding box to a multiple of our grid module
+	module := 2 * math.Pi * distance
+	bb.MergePoint(NewPoint(alignToGrid(bb.Min.X, module, 0), alignToGrid(bb.Min.Y, module, 0)))
+
+	// generate pattern
+	size := bb.Size()
+	plines := gyroidWaves(Scale(params.Z), densityAdjusted, params.Spacing,
+		math.Ceil(size.X/distance)+1, math.Ceil(size.Y/distance)+1)
+
+	// shift the polylines to the grid origin
+	for _, pline := range plines {
+		pline.MP.Translate(bb.Min)
+	}
+
+	plines, err := IntersectionPolylines(plines, region.Polygons())
+	if err != nil {
+		return nil, err
+	}
+
+	// remove very small bits, but be careful to not remove infill lines connecting
+	// thin walls. The infill perimeter lines should be separated by around a single
+	// infill line width
+	minLength := 0.8 * params.Spacing
+	kept := NewPolylines()
+	for _, pline := range plines {
+		if pline.MP.Length() >= minLength {
+			kept.Push(pline)
+		}
+	}
+	if kept.Empty() {
+		return kept, nil
+	}
+
+	if params.DontConnect {
+		kept = kept.ChainedPathFrom(kept.LeftmostPoint(), false)
+	} else {
+		canConnect := func(dx, dy float64) bool {
+			return math.Hypot(dx, dy) <= 2*distance
+		}
+		if kept, err = connectFillLines(kept, region, params.Spacing/2, canConnect); err != nil {
+			return nil, err
+		}
+	}
+
+	// new paths must be rotated back
+	for _, pline := range kept {
+		pline.MP.Rotate(angle)
+	}
+	return kept, nil
+}
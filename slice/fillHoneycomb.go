@@ -0,0 +1,100 @@
+package slice
+
+import "math"
+
+// FillHoneycomb fills with hexagons. Neighbouring columns share their walls, so
+// every wall is printed twice
+type FillHoneycomb struct{}
+
+// FillSurface will fill the region with hexagons
+func (fill *FillHoneycomb) FillSurface(pgx *PolygonEx, params *FillParams) (Polylines, error) {
+	if params.Density <= 0 || params.Spacing <= 0 {
+		return NewPolylines(), nil
+	}
+
+	// hexagons math
+	minSpacing := params.Spacing
+	distance := math.Round(minSpacing / params.Density)
+	hexSide := distance / (math.Sqrt(3) / 2)
+	hexWidth := distance * 2 // hexWidth == hexSide * sqrt(3)
+	yShort := distance * math.Sqrt(3) / 3
+	xOffset := minSpacing / 2
+	yOffset := xOffset * math.Sqrt(3) / 3
+	hexHeight := hexSide * 2
+	patternHeight := hexHeight + hexSide
+	hexCenter := NewPoint(hexWidth/2, hexSide)
+	angle, _ := infillDirection(pgx, params, alternatingLayerAngle)
+
+	// adjust actual bounding box to the nearest multiple of our hex pattern
+	// and align it so that it matches across layers
+	bbPolygon := NewBoundingBox(pgx.Contour.MP.Points...).Polygon()
+	bbPolygon.MP.RotateWithCenter(angle, hexCenter)
+	bb := NewBoundingBox(bbPolygon.MP.Points...)
+	bb.Min.X = alignToGrid(bb.Min.X, hexWidth, 0)
+	bb.Min.Y = alignToGrid(bb.Min.Y, patternHeight, 0)
+
+	polygons := NewPolygons()
+	for x := bb.Min.X; x <= bb.Max.X; {
+		p := NewPolygon()
+		ax := [2]float64{x + xOffset, x + distance - xOffset}
+		for i := 0; i < 2; i++ {
+			p.MP.Reverse() // turn first half upside down
+			for y := bb.Min.Y; y <= bb.Max.Y; y += yShort + hexSide + yShort + hexSide {
+				p.MP.Points.Push(
+					NewPoint(math.Round(ax[1]), math.Round(y+yOffset)),
+					NewPoint(math.Round(ax[0]), math.Round(y+yShort-yOffset)),
+					NewPoint(math.Round(ax[0]), math.Round(y+yShort+hexSide+yOffset)),
+					NewPoint(math.Round(ax[1]), math.Round(y+yShort+hexSide+yShort-yOffset)),
+					NewPoint(math.Round(ax[1]), math.Round(y+yShort+hexSide+yShort+hexSide+yOffset)),
+				)
+			}
+			ax[0], ax[1] = ax[1]+distance, ax[0]+distance
+			x += distance
+		}
+		p.MP.RotateWithCenter(-angle, hexCenter)
+		polygons.Push(p)
+	}
+
+	if params.Complete {
+		// we were requested to complete each loop, in this case we don't try to
+		// make more continuous paths
+		trimmed, err := Intersection(pgx.Polygons(), polygons)
+		if err != nil {
+			return nil, err
+		}
+		plines := NewPolylines()
+		for _, pg := range trimmed {
+			plines.Push(pg.SplitAtFirstPoint())
+		}
+		return plines, nil
+	}
+
+	// consider polygons as polylines without re-appending the initial point: this
+	// cuts the last segment on purpose, so that the jump to the next path is more straight
+	columns := NewPolylines()
+	for _, pg := range polygons {
+		columns.Push(polylineOf(pg.MP.Points...))
+	}
+	paths, err := IntersectionPolylines(columns, pgx.Polygons())
+	if err != nil || paths.Empty() || params.DontConnect {
+		return paths, err
+	}
+
+	// connect paths
+	connected := NewPolylines()
+	for _, path := range paths.ChainedPathFrom(paths.LeftmostPoint(), false) {
+		// distance between first point of this path and last point of last path
+		if !connected.Empty() && path.MP.Points.First().DistanceTo(connected.Last().MP.Points.Last()) <= hexWidth {
+			connected.Last().MP.Points.Push(path.MP.Points...)
+			continue
+		}
+		connected.Push(path)
+	}
+
+	// clip paths again to prevent connection segments from crossing the region boundaries
+	grown, err := Offset(pgx.Polygons(), ScaledEpsilon)
+	if err != nil {
+		return nil, err
+	}
+	return IntersectionPolylines(connected, grown)
+}
@@ -0,0 +1,180 @@
+package slice
+
+import "math"
+
+// Plane path fills follow Slic3r's FillPlanePath.cpp. A single curve covering the
+// whole object is generated on a grid of the line spacing and clipped to the region,
+// so the pattern lines up on every other layer, turning a quarter turn in between
+
+// planePath generates the curve in grid units covering the grid bounds
+type planePath interface {
+	generate(minX, minY, maxX, maxY float64) Points
+	centered() bool
+}
+
+// fillPlanePath will fill the region with the part of the curve inside it
+func fillPlanePath(path planePath, pgx *PolygonEx, params *FillParams) (Polylines, error) {
+	if params.Density <= 0 || params.Spacing <= 0 {
+		return NewPolylines(), nil
+	}
+
+	angle, _ := infillDirection(pgx, params, alternatingLayerAngle)
+	region := pgx.GetCopy()
+	region.Rotate(-angle)
+	distance := math.Trunc(params.Spacing / params.Density)
+
+	// align infill across layers using the object's bounding box
+	objectBox := params.BoundingBox
+	if objectBox == nil || !objectBox.Defined() {
+		objectBox = NewBoundingBox(pgx.Contour.MP.Points...)
+	}
+	bb := objectBox.Rotated(-angle)
+	shift := NewPoint(bb.Min.X, bb.Min.Y)
+	if path.centered() {
+		shift = bb.Center()
+	}
+	region.Translate(-shift.X, -shift.Y)
+	bb.Translate(-shift.X, -shift.Y)
+
+	points := path.generate(
+		math.Ceil(bb.Min.X/distance), math.Ceil(bb.Min.Y/distance),
+		math.Ceil(bb.Max.X/distance), math.Ceil(bb.Max.Y/distance))
+	if len(points) < 2 {
+		return NewPolylines(), nil
+	}
+
+	// convert points to a polyline, upscale
+	pline := NewPolyline()
+	for _, point := range points {
+		pline.MP.Points.Push(NewPoint(math.Floor(point.X*distance+0.5), math.Floor(point.Y*distance+0.5)))
+	}
+	plines, err := IntersectionPolylines(Polylines{pline}, region.Polygons())
+	if err != nil {
+		return nil, err
+	}
+
+	// paths must be repositioned and rotated back
+	for _, pline := range plines {
+		pline.MP.Translate(shift)
+		pline.MP.Rotate(angle)
+	}
+	return plines, nil
+}
+
+// FillHilbertCurve fills with a Hilbert curve
+type FillHilbertCurve struct{}
+
+// FillSurface will fill the region with a Hilbert curve
+func (fill *FillHilbertCurve) FillSurface(pgx *PolygonEx, params *FillParams) (Polylines, error) {
+	return fillPlanePath(fill, pgx, params)
+}
+
+func (fill *FillHilbertCurve) centered() bool {
+	return false
+}
+
+// hilbertToXY will find the nth point of the Hilbert curve. Adapted from
+// Math::PlanePath::HilbertCurve, the state is one of plain, transposed, rotated
+// by 180 degrees or both, in steps of 4
+func hilbertToXY(n int) (int, int) {
+	nextState := [16]int{4, 0, 0, 12, 0, 4, 4, 8, 12, 8, 8, 4, 8, 12, 12, 0}
+	digitToX := [16]int{0, 1, 1, 0, 0, 0, 1, 1, 1, 0, 0, 1, 1, 1, 0, 0}
+	digitToY := [16]int{0, 0, 1, 1, 0, 1, 1, 0, 1, 1, 0, 0, 1, 0, 0, 1}
+
+	// number of 2 bit digits
+	ndigits := 0
+	for nc := n; nc > 0; nc >>= 2 {
+		ndigits++
+	}
+	state := 0
+	if ndigits&1 == 1 {
+		state = 4
+	}
+	x, y := 0, 0
+	for i := ndigits - 1; i >= 0; i-- {
+		digit := (n >> uint(i*2)) & 3
+		state += digit
+		x |= digitToX[state] << uint(i)
+		y |= digitToY[state] << uint(i)
+		state = nextState[state]
+	}
+	return x, y
+}
+
+func (fill *FillHilbertCurve) generate(minX, minY, maxX, maxY float64) Points {
+	// minimum power of two square to fit the domain
+	size := 2
+	for size0 := int(math.Max(maxX+1-minX, maxY+1-minY)); size < size0; {
+		size <<= 1
+	}
+
+	points := make(Points, 0, size*size)
+	for i := 0; i < size*size; i++ {
+		x, y := hilbertToXY(i)
+		points.Push(NewPoint(float64(x)+minX, float64(y)+minY))
+	}
+	return points
+}
+
+// FillArchimedeanChords fills with an Archimedean spiral centered on the object
+type FillArchimedeanChords struct{}
+
+// FillSurface will fill the region with a spiral
+func (fill *FillArchimedeanChords) FillSurface(pgx *PolygonEx, params *FillParams) (Polylines, error) {
+	return fillPlanePath(fill, pgx, params)
+}
+
+func (fill *FillArchimedeanChords) centered() bool {
+	return true
+}
+
+// generate will follow an Archimedean spiral, in polar coordinates: r=a+b*theta
+func (fill *FillArchimedeanChords) generate(minX, minY, maxX, maxY float64) Points {
+	// radius to achieve
+	rmax := math.Sqrt(maxX*maxX+maxY*maxY)*math.Sqrt(2) + 1.5
+	// now unwind the spiral
+	a := 1.0
+	b := 1 / (2 * math.Pi)
+	theta := 0.0
+	r := 1.0
+	points := Points{NewPoint(0, 0), NewPoint(1, 0)}
+	for r < rmax {
+		theta += 1 / r
+		r = a + b*theta
+		points.Push(NewPoint(r*math.Cos(theta), r*math.Sin(theta)))
+	}
+	return points
+}
+
+// FillOctagramSpiral fills with an eight pointed star spiral centered on the object
+type FillOctagramSpiral struct{}
+
+// FillSurface will fill the region with an octagram spiral
+func (fill *FillOctagramSpiral) FillSurface(pgx *PolygonEx, params *FillParams) (Polylines, error) {
+	return fillPlanePath(fill, pgx, params)
+}
+
+func (fill *FillOctagramSpiral) centered() bool {
+	return true
+}
+
+func (fill *FillOctagramSpiral) generate(minX, minY, maxX, maxY float64) Points {
+	// radius to achieve
+	rmax := math.Sqrt(maxX*maxX+maxY*maxY)*math.Sqrt(2) + 1.5
+	// now unwind the spiral
+	r := 0.0
+	rInc := math.Sqrt(2)
+	points := Points{NewPoint(0, 0)}
+	for r < rmax {
+		r += rInc
+		rx := r / math.Sqrt(2)
+		r2 := r + rx
+		points.Push(
+			NewPoint(r, 0), NewPoint(r2, rx), NewPoint(rx, rx), NewPoint(rx, r2),
+			NewPoint(0, r), NewPoint(-rx, r2), NewPoint(-rx, rx), NewPoint(-r2, rx),
+			NewPoint(-r, 0), NewPoint(-r2, -rx), NewPoint(-rx, -rx), NewPoint(-rx, -r2),
+			NewPoint(0, -r), NewPoint(rx, -r2), NewPoint(rx, -rx), NewPoint(r2+rInc, -rx),
+		)
+	}
+	return points
+}
@@ -0,0 +1,199 @@
+package slice
+
+import (
+	"errors"
+	"math"
+)
+
+// Flow follows Slic3r's Flow.cpp. Widths, heights and nozzle diameters are
+// unscaled millimeters. Extrusions are modelled as a rectangle with semicircles
+// at the ends, except bridges which are round
+
+// BridgeExtraSpacing is added between bridge extrusions
+const BridgeExtraSpacing float64 = 0.05
+
+// OverlapFactor is how much adjacent extrusions overlap, 1 meaning the semicircles fill the gaps
+const OverlapFactor float64 = 1.0
+
+// ErrInvalidFlowHeight is returned when a flow is asked for without a layer height
+var ErrInvalidFlowHeight = errors.New("Invalid flow height")
+
+// FlowRole tells what a flow will be used for, which bounds the automatic width
+type FlowRole int
+
+// Flow roles
+const (
+	FrExternalPerimeter FlowRole = iota
+	FrPerimeter
+	FrInfill
+	FrSolidInfill
+	FrTopSolidInfill
+	FrSupportMaterial
+	FrSupportMaterialInterface
+)
+
+// FloatOrPercent is a value that is either absolute or a percentage of something else
+type FloatOrPercent struct {
+	Value   float64
+	Percent bool
+}
+
+// GetAbsValue will resolve the value, taking percentages of ratioOver
+func (fp FloatOrPercent) GetAbsValue(ratioOver float64) float64 {
+	if fp.Percent {
+		return ratioOver * fp.Value / 100
+	}
+	return fp.Value
+}
+
+// Flow is the shape of a single extrusion
+type Flow struct {
+	Width          float64
+	Height         float64
+	NozzleDiameter float64
+	Bridge         bool
+}
+
+// NewFlow will construct a Flow
+func NewFlow(width float64, height float64, nozzleDiameter float64, bridge bool) *Flow {
+	flow := new(Flow)
+	flow.Width = width
+	flow.Height = height
+	flow.NozzleDiameter = nozzleDiameter
+	flow.Bridge = bridge
+	return flow
+}
+
+// NewFlowFromConfigWidth will construct a Flow from a configured width. A width of
+// 0 picks a sane default for the role, and a bridge flow ratio above 0 makes a round
+// bridge flow whose height is its width
+func NewFlowFromConfigWidth(role FlowRole, width FloatOrPercent, nozzleDiameter float64, height float64, bridgeFlowRatio float64) (*Flow, error) {
+	// we need layer height unless it's a bridge
+	if height <= 0 && bridgeFlowRatio == 0 {
+		return nil, ErrInvalidFlowHeight
+	}
+
+	var w float64
+	if bridgeFlowRatio > 0 {
+		// if bridge flow was requested, calculate bridge width
+		w = bridgeWidth(nozzleDiameter, bridgeFlowRatio)
+		height = w
+	} else if !width.Percent && width.Value == 0 {
+		// if user left option to 0, calculate a sane default width
+		w = autoWidth(role, nozzleDiameter, height)
+	} else {
+		// if user set a manual value, use it
+		w = width.GetAbsValue(height)
+	}
+	return NewFlow(w, height, nozzleDiameter, bridgeFlowRatio > 0), nil
+}
+
+// NewFlowFromSpacing will construct a Flow whose lines are spacing apart
+func NewFlowFromSpacing(spacing float64, nozzleDiameter float64, height float64, bridge bool) (*Flow, error) {
+	// we need layer height unless it's a bridge
+	if height <= 0 && !bridge {
+		return nil, ErrInvalidFlowHeight
+	}
+	w := widthFromSpacing(spacing, height, bridge)
+	if bridge {
+		height = w
+	}
+	return NewFlow(w, height, nozzleDiameter, bridge), nil
+}
+
+// Spacing will return the distance between the centers of adjacent lines
+func (flow *Flow) Spacing() float64 {
+	if flow.Bridge {
+		return flow.Width + BridgeExtraSpacing
+	}
+	// rectangle with semicircles at the ends
+	minFlowSpacing := flow.Width - flow.Height*(1-math.Pi/4)
+	return flow.Width - OverlapFactor*(flow.Width-minFlowSpacing)
+}
+
+// SpacingTo will return the distance between the center of a line of this flow and
+// an adjacent line of another flow of the same height
+func (flow *Flow) SpacingTo(other *Flow) float64 {
+	if flow.Bridge {
+		return flow.Width/2 + other.Width/2 + BridgeExtraSpacing
+	}
+	return flow.Spacing()/2 + other.Spacing()/2
+}
+
+// SetSpacing will change the width so lines end up spacing apart
+func (flow *Flow) SetSpacing(spacing float64) {
+	flow.Width = widthFromSpacing(spacing, flow.Height, flow.Bridge)
+}
+
+// SetSolidSpacing will adjust the spacing so a whole number of lines fills width
+func (flow *Flow) SetSolidSpacing(width float64) {
+	lines := math.Floor(width / flow.Spacing())
+	if lines == 0 {
+		return
+	}
+	flow.SetSpacing(width / lines)
+}
+
+// MM3PerMM will return the cross sectional area of the extrusion
+func (flow *Flow) MM3PerMM() float64 {
+	if flow.Bridge {
+		return flow.Width * flow.Width * math.Pi / 4
+	}
+	// rectangle with semicircles at the ends
+	return flow.Width*flow.Height + flow.Height*flow.Height/4*(math.Pi-4)
+}
+
+// ScaledWidth will return the width in scaled coordinates
+func (flow *Flow) ScaledWidth() float64 {
+	return math.Round(Scale(flow.Width))
+}
+
+// ScaledSpacing will return the spacing in scaled coordinates
+func (flow *Flow) ScaledSpacing() float64 {
+	return math.Round(Scale(flow.Spacing()))
+}
+
+// ScaledSpacingTo will return the spacing to another flow in scaled coordinates
+func (flow *Flow) ScaledSpacingTo(other *Flow) float64 {
+	return math.Round(Scale(flow.SpacingTo(other)))
+}
+
+// bridgeWidth will return the diameter of a round extrusion of the bridge flow ratio
+func bridgeWidth(nozzleDiameter float64, bridgeFlowRatio float64) float64 {
+	if bridgeFlowRatio == 1 {
+		return nozzleDiameter // optimization to avoid sqrt()
+	}
+	return math.Sqrt(bridgeFlowRatio * nozzleDiameter * nozzleDiameter)
+}
+
+// autoWidth will match the flow speed at the nozzle with the feed rate
+func autoWidth(role FlowRole, nozzleDiameter float64, height float64) float64 {
+	// shape: rectangle with semicircles at the ends
+	width := (nozzleDiameter*nozzleDiameter*math.Pi + height*height*(4-math.Pi)) / (4 * height)
+
+	min := nozzleDiameter * 1.05
+	max := -1.0
+	if role == FrExternalPerimeter || role == FrSupportMaterial || role == FrSupportMaterialInterface {
+		min = nozzleDiameter * 1.1
+		max = min
+	} else if role != FrInfill {
+		// do not limit width for sparse infill so that we use full native flow for it
+		max = nozzleDiameter * 1.7
+	}
+	if max != -1 && width > max {
+		width = max
+	}
+	if width < min {
+		width = min
+	}
+	return width
+}
+
+// widthFromSpacing will return the width of lines that are spacing apart
+func widthFromSpacing(spacing float64, height float64, bridge bool) float64 {
+	if bridge {
+		return spacing - BridgeExtraSpacing
+	}
+	// rectangle with semicircles at the ends
+	return spacing + OverlapFactor*height*(1-math.Pi/4)
+}
@@ -0,0 +1,325 @@
+package slice
+
+// GCode turns extrusions into moves of a GCodeWriter, deciding when travels retract,
+// wipe and lift. This follows Slic3r's GCode.cpp. Points are scaled
+type GCode struct {
+	Writer *GCodeWriter
+
+	// OnlyRetractWhenCrossingPerimeters skips the retraction of travels staying
+	// inside the internal surfaces of the layer, where strings are hidden by infill
+	OnlyRetractWhenCrossingPerimeters bool
+	FillDensity                       float64 // percent, nothing hides strings at 0
+
+	// AvoidCrossingPerimeters plans travels around the external perimeters of the
+	// layer instead of moving in a straight line
+	AvoidCrossingPerimeters bool
+
+	// Seam picks where loops start, they start where they were split without it
+	Seam *SeamPlacer
+
+	// Skirt is extruded on the first SkirtLayers layers and Brim on the first layer,
+	// right after moving to the layer and before anything of the objects
+	Skirt       *ExtrusionEntityCollection
+	SkirtLayers int
+	Brim        *ExtrusionEntityCollection
+	SkirtSpeed  float64 // mm/s of the skirt and brim
+
+	Layer *Layer // the layer being printed
+
+	planner      *MotionPlanner // for the slices of Layer, built on the first travel
+	skirtDone    map[int]bool   // layers whose skirt was extruded, once for all objects
+	brimDone     bool
+	lastPosition *Point
+	wipePath     *Polyline // last extruded path reversed, to wipe along
+}
+
+// NewGCode will construct a GCode driving the writer
+func NewGCode(writer *GCodeWriter) *GCode {
+	gcode := new(GCode)
+	gcode.Writer = writer
+	gcode.SkirtLayers = 1
+	gcode.SkirtSpeed = 30
+	gcode.FillDensity = 20
+	return gcode
+}
+
+// LastPosition will return where the last move ended, nil before the first move
+func (gcode *GCode) LastPosition() *Point {
+	return gcode.lastPosition
+}
+
+// ChangeLayer will move up to the layer, retracting first if the extruder asks for it,
+// and extrude the skirt and brim of the layer
+func (gcode *GCode) ChangeLayer(layer *Layer) error {
+	gcode.Layer = layer
+	extruder := gcode.Writer.Extruder
+	if extruder != nil && extruder.RetractLayerChange && gcode.Writer.WillMoveZ(layer.PrintZ) {
+		if err := gcode.Retract(false); err != nil {
+			return err
+		}
+	}
+	if err := gcode.Writer.TravelToZ(layer.PrintZ, "move to next layer"); err != nil {
+		return err
+	}
+	// forget the wipe path and travel planner of the previous layer
+	gcode.wipePath = nil
+	gcode.planner = nil
+
+	if gcode.Skirt != nil && layer.ID < gcode.SkirtLayers && !gcode.skirtDone[layer.ID] {
+		if gcode.skirtDone == nil {
+			gcode.skirtDone = make(map[int]bool)
+		}
+		gcode.skirtDone[layer.ID] = true
+		if err := gcode.ExtrudeEntity(gcode.Skirt, gcode.SkirtSpeed, "skirt"); err != nil {
+			return err
+		}
+	}
+	if gcode.Brim != nil && layer.ID == 0 && !gcode.brimDone {
+		gcode.brimDone = true
+		if err := gcode.ExtrudeEntity(gcode.Brim, gcode.SkirtSpeed, "brim"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NeedsRetraction will tell if the travel should retract. Short travels, travels
+// inside support islands and, when asked, travels that stay inside the internal
+// surfaces of a layer with infill don't. Without surfaces every travel may cross a
+// perimeter, so it retracts
+func (gcode *GCode) NeedsRetraction(travel *Polyline, role ExtrusionRole) bool {
+	extruder := gcode.Writer.Extruder
+	if extruder == nil || travel.MP.Length() < Scale(extruder.RetractBeforeTravel) {
+		// skip retraction if the move is shorter than the configured threshold
+		return false
+	}
+	if gcode.Layer == nil {
+		return true
+	}
+
+	if role == ErSupportMaterial && anyContains(gcode.Layer.SupportIslands, travel) {
+		// skip retraction if this is a travel move inside a support material island
+		return false
+	}
+
+	if gcode.OnlyRetractWhenCrossingPerimeters && gcode.FillDensity > 0 {
+		internal := NewPolygonExs()
+		for _, surface := range gcode.Layer.Surfaces {
+			if surface.IsInternal() {
+				internal.Push(surface.PolygonEx)
+			}
+		}
+		if anyContains(internal, travel) {
+			return false
+		}
+	}
+	return true
+}
+
+// anyContains will tell if the polyline is inside one of the regions
+func anyContains(pgxs PolygonExs, pline *Polyline) bool {
+	for _, pgx := range pgxs {
+		if pgx.ContainsPline(pline) {
+			return true
+		}
+	}
+	return false
+}
+
+// TravelTo will move to point, retracting when the travel needs it
+func (gcode *GCode) TravelTo(point *Point, role ExtrusionRole, comment string) error {
+	travel := polylineOf(point)
+	if gcode.lastPosition != nil {
+		var err error
+		if travel, err = gcode.planTravel(gcode.lastPosition, point); err != nil {
+			return err
+		}
+		if gcode.NeedsRetraction(travel, role) {
+			if err := gcode.Retract(false); err != nil {
+				return err
+			}
+		} else {
+			// don't wipe along an old path after travelling
+			gcode.wipePath = nil
+		}
+		travel.MP.Points.PopFront()
+	}
+
+	gcode.lastPosition = NewPoint(point.X, point.Y)
+	// use G1 because we rely on paths being straight (G0 may make round paths)
+	for _, p := range travel.MP.Points {
+		if err := gcode.Writer.TravelToXY(NewPoint(UnScale(p.X), UnScale(p.Y)), comment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// planTravel will return the travel from one point to another, around the external
+// perimeters when avoiding them
+func (gcode *GCode) planTravel(from *Point, to *Point) (*Polyline, error) {
+	if !gcode.AvoidCrossingPerimeters || gcode.Layer == nil {
+		return polylineOf(from, to), nil
+	}
+	if gcode.planner == nil {
+		gcode.planner = NewMotionPlanner(gcode.Layer.Slices)
+	}
+	return gcode.planner.ShortestPath(from, to)
+}
+
+// Retract will wipe when there is a path to wipe along, retract what is left and
+// lift. toolchange uses the toolchange retraction
+func (gcode *GCode) Retract(toolchange bool) error {
+	writer := gcode.Writer
+	extruder := writer.Extruder
+	if extruder == nil {
+		return nil
+	}
+
+	retract := writer.Retract
+	if toolchange {
+		retract = writer.RetractForToolchange
+	}
+	if extruder.Wipe && gcode.wipePath != nil {
+		if err := retract(true); err != nil {
+			return err
+		}
+		if err := gcode.wipe(toolchange); err != nil {
+			return err
+		}
+	}
+
+	// retract in full even after a wipe, in case the wipe path was too short
+	if err := retract(false); err != nil {
+		return err
+	}
+	if err := writer.ResetE(false); err != nil {
+		return err
+	}
+	if extruder.RetractLength > 0 || writer.UseFirmwareRetraction {
+		return writer.Lift()
+	}
+	return nil
+}
+
+// wipe will move back along the last path while retracting the part of the
+// retraction not done before wiping. With firmware retraction it only moves
+func (gcode *GCode) wipe(toolchange bool) error {
+	writer := gcode.Writer
+	extruder := writer.Extruder
+
+	// travel speed is often too high to move on existing material. Too fast rips it
+	// off, too slow gives a short wipe path and more blobs
+	wipeSpeed := writer.TravelSpeed * 0.8
+	length := extruder.RetractLength
+	if toolchange {
+		length = extruder.RetractLengthToolchange
+	}
+	length *= 1 - extruder.RetractBeforeWipe
+	if length <= 0 || extruder.RetractSpeed <= 0 {
+		return nil
+	}
+
+	// how far to move at the wipe speed in the time retracting takes
+	wipeDistance := Scale(length / extruder.RetractSpeed * wipeSpeed)
+
+	// start from where the nozzle is, which may differ from the path after clipping
+	path := NewPolyline()
+	path.MP.Points.Push(gcode.lastPosition)
+	path.MP.Points.Push(gcode.wipePath.MP.Points[1:]...)
+	path.ClipEnd(path.MP.Length() - wipeDistance)
+	gcode.wipePath = nil
+	if len(path.MP.Points) < 2 {
+		return nil
+	}
+
+	for _, line := range path.Lines() {
+		if err := writer.SetSpeed(wipeSpeed*60, ""); err != nil {
+			return err
+		}
+		to := NewPoint(UnScale(line.B.X), UnScale(line.B.Y))
+		if writer.UseFirmwareRetraction {
+			// the firmware retracted in full and only restores its own amount, so
+			// pulling more filament here would be lost
+			if err := writer.MoveToXY(to, "wipe"); err != nil {
+				return err
+			}
+			continue
+		}
+		// a bit less than the exact share, so rounding never retracts faster than
+		// the retract speed
+		dE := length * line.Length() / wipeDistance * 0.95
+		if err := writer.ExtrudeToXY(to, -dE, "wipe and retract"); err != nil {
+			return err
+		}
+	}
+	gcode.lastPosition = path.MP.Points.Last()
+	return nil
+}
+
+// Unretract will lower Z and push the filament back in
+func (gcode *GCode) Unretract() error {
+	if err := gcode.Writer.Unlift(); err != nil {
+		return err
+	}
+	return gcode.Writer.Unretract()
+}
+
+// ExtrudePath will travel to the path, unretract and extrude it at speed
+func (gcode *GCode) ExtrudePath(path *ExtrusionPath, speed float64, comment string) error {
+	if !path.IsValid() {
+		return nil
+	}
+	first := path.FirstPoint()
+	if gcode.lastPosition == nil || !gcode.lastPosition.CoincidesWith(first) {
+		if err := gcode.TravelTo(first, path.Role, "move to first "+path.Role.String()+" point"); err != nil {
+			return err
+		}
+	}
+	if err := gcode.Unretract(); err != nil {
+		return err
+	}
+	if err := gcode.Writer.ExtrudePath(path, speed, comment); err != nil {
+		return err
+	}
+
+	last := path.LastPoint()
+	gcode.lastPosition = NewPoint(last.X, last.Y)
+	if extruder := gcode.Writer.Extruder; extruder != nil && extruder.Wipe {
+		gcode.wipePath = copyPolyline(path.Polyline)
+		gcode.wipePath.MP.Reverse()
+	}
+	return nil
+}
+
+// ExtrudeEntity will extrude the paths of the entity in order
+func (gcode *GCode) ExtrudeEntity(entity ExtrusionEntity, speed float64, comment string) error {
+	switch e := entity.(type) {
+	case *ExtrusionPath:
+		return gcode.ExtrudePath(e, speed, comment)
+	case *ExtrusionMultiPath:
+		return gcode.extrudePaths(e.Paths, speed, comment)
+	case *ExtrusionLoop:
+		if gcode.Seam != nil {
+			e = e.Clone().(*ExtrusionLoop)
+			gcode.Seam.SplitExtrusionLoop(e, gcode.lastPosition)
+		}
+		return gcode.extrudePaths(e.Paths, speed, comment)
+	case *ExtrusionEntityCollection:
+		for _, child := range e.Entities {
+			if err := gcode.ExtrudeEntity(child, speed, comment); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (gcode *GCode) extrudePaths(paths ExtrusionPaths, speed float64, comment string) error {
+	for _, path := range paths {
+		if err := gcode.ExtrudePath(path, speed, comment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,602 @@
+package slice
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// ErrUnknownGCodeFlavor is returned when parsing a flavor name that is not supported
+var ErrUnknownGCodeFlavor = errors.New("Unknown G-code flavor")
+
+// ErrUnknownExtruder is returned when selecting an extruder that was never set up
+var ErrUnknownExtruder = errors.New("Unknown extruder")
+
+// GCodeFlavor is the dialect of the firmware the G-code is written for
+type GCodeFlavor int
+
+// G-code flavors
+const (
+	GfRepRap GCodeFlavor = iota // RepRap and Sprinter
+	GfMarlin
+	GfKlipper
+	GfSmoothie
+	GfTeacup
+	GfMach3 // Mach3 and LinuxCNC
+	GfNoExtrusion
+	GfRepetier
+	GfMakerWare // MakerWare and Sailfish need the G-code converted with GPX
+	GfSailfish
+	GfMachinekit
+)
+
+var gcodeFlavorNames = map[GCodeFlavor]string{
+	GfRepRap:      "reprap",
+	GfMarlin:      "marlin",
+	GfKlipper:     "klipper",
+	GfSmoothie:    "smoothie",
+	GfTeacup:      "teacup",
+	GfMach3:       "mach3",
+	GfNoExtrusion: "no-extrusion",
+	GfRepetier:    "repetier",
+	GfMakerWare:   "makerware",
+	GfSailfish:    "sailfish",
+	GfMachinekit:  "machinekit",
+}
+
+// String will return the name of the flavor as used in Slic3r configs
+func (flavor GCodeFlavor) String() string {
+	if name, ok := gcodeFlavorNames[flavor]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// isMach3 will tell if the flavor speaks the CNC dialect of Mach3 and Machinekit
+func (flavor GCodeFlavor) isMach3() bool {
+	return flavor == GfMach3 || flavor == GfMachinekit
+}
+
+// isMakerBot will tell if the flavor is for MakerBot firmwares
+func (flavor GCodeFlavor) isMakerBot() bool {
+	return flavor == GfMakerWare || flavor == GfSailfish
+}
+
+// ParseGCodeFlavor will find the flavor by its name
+func ParseGCodeFlavor(name string) (GCodeFlavor, error) {
+	for flavor, flavorName := range gcodeFlavorNames {
+		if flavorName == name {
+			return flavor, nil
+		}
+	}
+	return GfRepRap, ErrUnknownGCodeFlavor
+}
+
+// GCodeWriter writes G-code commands for a flavor and keeps track of the position,
+// the extruders and the Z lift. This follows Slic3r's GCodeWriter.cpp. Coordinates
+// are unscaled millimeters and speeds are millimeters per second
+type GCodeWriter struct {
+	Flavor                GCodeFlavor
+	UseRelativeEDistances bool
+	UseVolumetricE        bool
+	UseFirmwareRetraction bool // retract with G10 and G11, as set up in the firmware
+	GCodeComments         bool
+	TravelSpeed           float64
+
+	Extruders map[int]*Extruder
+	Extruder  *Extruder // the one in use, nil until SetExtruder
+
+	w                io.Writer
+	position         *Point3
+	lifted           float64
+	lastFanSpeed     int
+	lastAcceleration int
+}
+
+// NewGCodeWriter will construct a GCodeWriter writing to w
+func NewGCodeWriter(w io.Writer, flavor GCodeFlavor) *GCodeWriter {
+	gw := new(GCodeWriter)
+	gw.Flavor = flavor
+	gw.TravelSpeed = 130
+	gw.Extruders = make(map[int]*Extruder)
+	gw.w = w
+	gw.position = NewP3(0, 0, 0)
+	return gw
+}
+
+// xyzNumber will format a coordinate
+func xyzNumber(val float64) string {
+	return strconv.FormatFloat(val, 'f', 3, 64)
+}
+
+// eNumber will format a position of the E axis
+func eNumber(val float64) string {
+	return strconv.FormatFloat(val, 'f', 5, 64)
+}
+
+// fNumber will format a feedrate or a fan speed
+func fNumber(val float64) string {
+	return strconv.FormatFloat(math.Round(val*1000)/1000, 'f', -1, 64)
+}
+
+// write will write a line of G-code, with the comment when comments are enabled
+func (gw *GCodeWriter) write(code string, comment string) error {
+	if gw.GCodeComments && comment != "" {
+		code += " ; " + comment
+	}
+	_, err := io.WriteString(gw.w, code+"\n")
+	return err
+}
+
+// ExtrusionAxis will return the letter of the axis driving the extruder, empty
+// when the flavor doesn't extrude
+func (gw *GCodeWriter) ExtrusionAxis() string {
+	switch {
+	case gw.Flavor.isMach3():
+		return "A"
+	case gw.Flavor == GfNoExtrusion:
+		return ""
+	}
+	return "E"
+}
+
+// Position will return the current position of the nozzle
+func (gw *GCodeWriter) Position() *Point3 {
+	return NewP3(gw.position.Point.X, gw.position.Point.Y, gw.position.Z)
+}
+
+// SetExtruders will set up the extruders, the first one is used until a toolchange
+func (gw *GCodeWriter) SetExtruders(extruders ...*Extruder) {
+	for _, extruder := range extruders {
+		extruder.relativeE = gw.UseRelativeEDistances
+		extruder.volumetric = gw.UseVolumetricE
+		gw.Extruders[extruder.ID] = extruder
+	}
+	if gw.Extruder == nil && len(extruders) > 0 {
+		gw.Extruder = extruders[0]
+	}
+}
+
+// multipleExtruders will tell if more than one extruder is set up
+func (gw *GCodeWriter) multipleExtruders() bool {
+	return len(gw.Extruders) > 1
+}
+
+// Preamble will set the units and the coordinate modes
+func (gw *GCodeWriter) Preamble() error {
+	if err := gw.write("G21 ; set units to millimeters", ""); err != nil {
+		return err
+	}
+	if err := gw.write("G90 ; use absolute coordinates", ""); err != nil {
+		return err
+	}
+	if gw.Flavor.isMach3() || gw.Flavor.isMakerBot() || gw.Flavor == GfNoExtrusion {
+		return nil
+	}
+
+	code := "M82 ; use absolute distances for extrusion"
+	if gw.UseRelativeEDistances {
+		code = "M83 ; use relative distances for extrusion"
+	}
+	if err := gw.write(code, ""); err != nil {
+		return err
+	}
+	return gw.ResetE(true)
+}
+
+// Postamble will end the program where the flavor needs it
+func (gw *GCodeWriter) Postamble() error {
+	if gw.Flavor.isMach3() {
+		return gw.write("M2 ; end of program", "")
+	}
+	return nil
+}
+
+// temperatureParameter will return the parameter letter of temperatures and fan speeds
+func (gw *GCodeWriter) temperatureParameter() string {
+	if gw.Flavor.isMach3() {
+		return "P"
+	}
+	return "S"
+}
+
+// SetTemperature will set the temperature of the hotend, waiting for it when asked.
+// tool is the extruder to heat, -1 for the one in use
+func (gw *GCodeWriter) SetTemperature(temperature int, wait bool, tool int) error {
+	if wait && gw.Flavor.isMakerBot() {
+		// GPX waits for the temperatures itself
+		return nil
+	}
+	code, comment := "M104", "set temperature"
+	if wait && gw.Flavor != GfTeacup {
+		code, comment = "M109", "set temperature and wait for it to be reached"
+	}
+	code += fmt.Sprintf(" %s%d", gw.temperatureParameter(), temperature)
+	if tool != -1 && gw.multipleExtruders() {
+		code += fmt.Sprintf(" T%d", tool)
+	}
+	if err := gw.write(code+" ; "+comment, ""); err != nil {
+		return err
+	}
+	if gw.Flavor == GfTeacup && wait {
+		return gw.write("M116 ; wait for temperature to be reached", "")
+	}
+	return nil
+}
+
+// SetBedTemperature will set the temperature of the bed, waiting for it when asked
+func (gw *GCodeWriter) SetBedTemperature(temperature int, wait bool) error {
+	code, comment := "M140", "set bed temperature"
+	if wait && gw.Flavor != GfTeacup {
+		code, comment = "M190", "set bed temperature and wait for it to be reached"
+	}
+	code += fmt.Sprintf(" %s%d", gw.temperatureParameter(), temperature)
+	if err := gw.write(code+" ; "+comment, ""); err != nil {
+		return err
+	}
+	if gw.Flavor == GfTeacup && wait {
+		return gw.write("M116 ; wait for bed temperature to be reached", "")
+	}
+	return nil
+}
+
+// SetFan will set the fan speed in percent, unless it already runs at it. With
+// dontSave the speed is not remembered, for temporary changes
+func (gw *GCodeWriter) SetFan(speed int, dontSave bool) error {
+	if gw.lastFanSpeed == speed && !dontSave {
+		return nil
+	}
+	if !dontSave {
+		gw.lastFanSpeed = speed
+	}
+
+	if speed == 0 {
+		code := "M107"
+		if gw.Flavor == GfTeacup {
+			code = "M106 S0"
+		} else if gw.Flavor.isMakerBot() {
+			code = "M127"
+		}
+		return gw.write(code, "disable fan")
+	}
+	if gw.Flavor.isMakerBot() {
+		return gw.write("M126", "enable fan")
+	}
+	return gw.write("M106 "+gw.temperatureParameter()+fNumber(255*float64(speed)/100), "enable fan")
+}
+
+// SetAcceleration will set the acceleration in mm/s², 0 leaves it unchanged
+func (gw *GCodeWriter) SetAcceleration(acceleration int) error {
+	if acceleration == 0 || acceleration == gw.lastAcceleration {
+		return nil
+	}
+	gw.lastAcceleration = acceleration
+	if gw.Flavor == GfKlipper {
+		return gw.write(fmt.Sprintf("SET_VELOCITY_LIMIT ACCEL=%d", acceleration), "adjust acceleration")
+	}
+	if gw.Flavor == GfRepetier {
+		// the maximum printing and travel accelerations
+		if err := gw.write(fmt.Sprintf("M201 X%d Y%d", acceleration, acceleration), "adjust acceleration"); err != nil {
+			return err
+		}
+		return gw.write(fmt.Sprintf("M202 X%d Y%d", acceleration, acceleration), "adjust acceleration")
+	}
+	return gw.write(fmt.Sprintf("M204 S%d", acceleration), "adjust acceleration")
+}
+
+// ResetE will set the E axis back to 0 with absolute E distances. Unless forced
+// nothing is written when it already is 0
+func (gw *GCodeWriter) ResetE(force bool) error {
+	if gw.Flavor.isMach3() || gw.Flavor.isMakerBot() {
+		return nil
+	}
+	if gw.Extruder != nil {
+		if gw.Extruder.E == 0 && !force {
+			return nil
+		}
+		gw.Extruder.ResetE()
+	}
+	if gw.ExtrusionAxis() == "" || gw.UseRelativeEDistances {
+		return nil
+	}
+	return gw.write("G92 "+gw.ExtrusionAxis()+"0", "reset extrusion distance")
+}
+
+// NeedToolchange will tell if switching to the extruder needs a toolchange
+func (gw *GCodeWriter) NeedToolchange(id int) bool {
+	return gw.Extruder == nil || gw.Extruder.ID != id
+}
+
+// SetExtruder will switch to the extruder if it is not in use yet
+func (gw *GCodeWriter) SetExtruder(id int) error {
+	if !gw.NeedToolchange(id) {
+		return nil
+	}
+	return gw.Toolchange(id)
+}
+
+// Toolchange will switch to the extruder, resetting E first
+func (gw *GCodeWriter) Toolchange(id int) error {
+	extruder, ok := gw.Extruders[id]
+	if !ok {
+		return ErrUnknownExtruder
+	}
+	// if we are running a single-extruder setup, just set the extruder and return
+	if !gw.multipleExtruders() {
+		gw.Extruder = extruder
+		return nil
+	}
+
+	if err := gw.ResetE(true); err != nil {
+		return err
+	}
+	gw.Extruder = extruder
+	code := fmt.Sprintf("T%d", id)
+	switch {
+	case gw.Flavor.isMach3():
+		code = fmt.Sprintf("M6 T%d", id)
+	case gw.Flavor == GfMakerWare:
+		code = fmt.Sprintf("M135 T%d", id)
+	case gw.Flavor == GfSailfish:
+		code = fmt.Sprintf("M108 T%d", id)
+	}
+	return gw.write(code, "change extruder")
+}
+
+// SetSpeed will set the feedrate in mm/min for the following moves
+func (gw *GCodeWriter) SetSpeed(f float64, comment string) error {
+	return gw.write("G1 F"+fNumber(f), comment)
+}
+
+// travelFeedrate will return the travel speed in mm/min
+func (gw *GCodeWriter) travelFeedrate() string {
+	return fNumber(gw.TravelSpeed * 60)
+}
+
+// TravelToXY will move to point without extruding
+func (gw *GCodeWriter) TravelToXY(point *Point, comment string) error {
+	gw.position.Point = NewPoint(point.X, point.Y)
+	return gw.write("G1 X"+xyzNumber(point.X)+" Y"+xyzNumber(point.Y)+" F"+gw.travelFeedrate(), comment)
+}
+
+// TravelToXYZ will move to point without extruding. Moving down into the current
+// lift only lowers the Z the lift will be undone to
+func (gw *GCodeWriter) TravelToXYZ(point *Point3, comment string) error {
+	// if the target Z is within the lift, only move in XY and adjust the lift
+	if !gw.WillMoveZ(point.Z) {
+		nominalZ := gw.position.Z - gw.lifted
+		gw.lifted -= point.Z - nominalZ
+		if math.Abs(gw.lifted) < Epsilon {
+			gw.lifted = 0
+		}
+		return gw.TravelToXY(point.Point, comment)
+	}
+
+	// in all the other cases, we perform an actual XYZ move and cancel the lift
+	gw.lifted = 0
+	gw.position = NewP3(point.Point.X, point.Point.Y, point.Z)
+	return gw.write("G1 X"+xyzNumber(point.Point.X)+" Y"+xyzNumber(point.Point.Y)+
+		" Z"+xyzNumber(point.Z)+" F"+gw.travelFeedrate(), comment)
+}
+
+// TravelToZ will move to z. Moving down into the current lift only lowers the Z the
+// lift will be undone to
+func (gw *GCodeWriter) TravelToZ(z float64, comment string) error {
+	if !gw.WillMoveZ(z) {
+		nominalZ := gw.position.Z - gw.lifted
+		gw.lifted -= z - nominalZ
+		if math.Abs(gw.lifted) < Epsilon {
+			gw.lifted = 0
+		}
+		return nil
+	}
+
+	// in all the other cases, we perform an actual Z move and cancel the lift
+	gw.lifted = 0
+	return gw.travelToZ(z, comment)
+}
+
+func (gw *GCodeWriter) travelToZ(z float64, comment string) error {
+	gw.position.Z = z
+	return gw.write("G1 Z"+xyzNumber(z)+" F"+gw.travelFeedrate(), comment)
+}
+
+// WillMoveZ will tell if moving to z needs a Z move. A target lower than the current
+// Z but higher than the nominal Z under the lift doesn't
+func (gw *GCodeWriter) WillMoveZ(z float64) bool {
+	if gw.lifted > 0 {
+		nominalZ := gw.position.Z - gw.lifted
+		if z >= nominalZ && z <= gw.position.Z {
+			return false
+		}
+	}
+	return true
+}
+
+// MoveToXY will move to point at the current speed, leaving the extruder alone
+func (gw *GCodeWriter) MoveToXY(point *Point, comment string) error {
+	gw.position.Point = NewPoint(point.X, point.Y)
+	return gw.write("G1 X"+xyzNumber(point.X)+" Y"+xyzNumber(point.Y), comment)
+}
+
+// ExtrudeToXY will move to point while pushing dE of filament
+func (gw *GCodeWriter) ExtrudeToXY(point *Point, dE float64, comment string) error {
+	gw.position.Point = NewPoint(point.X, point.Y)
+	return gw.write("G1 X"+xyzNumber(point.X)+" Y"+xyzNumber(point.Y)+gw.extrude(dE), comment)
+}
+
+// ExtrudeToXYZ will move to point while pushing dE of filament
+func (gw *GCodeWriter) ExtrudeToXYZ(point *Point3, dE float64, comment string) error {
+	gw.lifted = 0
+	gw.position = NewP3(point.Point.X, point.Point.Y, point.Z)
+	return gw.write("G1 X"+xyzNumber(point.Point.X)+" Y"+xyzNumber(point.Point.Y)+
+		" Z"+xyzNumber(point.Z)+gw.extrude(dE), comment)
+}
+
+// extrude will move the E axis of the extruder and return its parameter
+func (gw *GCodeWriter) extrude(dE float64) string {
+	if gw.Extruder == nil {
+		return ""
+	}
+	gw.Extruder.Extrude(dE)
+	if gw.ExtrusionAxis() == "" {
+		return ""
+	}
+	return " " + gw.ExtrusionAxis() + eNumber(gw.Extruder.E)
+}
+
+// Retract will pull the filament back by the retract length of the extruder. Before
+// a wipe only the part retracted before wiping is
+func (gw *GCodeWriter) Retract(beforeWipe bool) error {
+	if gw.Extruder == nil {
+		return nil
+	}
+	factor := gw.retractFactor(beforeWipe)
+	return gw.retract(factor*gw.Extruder.RetractLength, factor*gw.Extruder.RetractRestartExtra, "retract")
+}
+
+// RetractForToolchange will pull the filament back before switching extruders
+func (gw *GCodeWriter) RetractForToolchange(beforeWipe bool) error {
+	if gw.Extruder == nil {
+		return nil
+	}
+	factor := gw.retractFactor(beforeWipe)
+	return gw.retract(factor*gw.Extruder.RetractLengthToolchange, factor*gw.Extruder.RetractRestartExtraToolchange,
+		"retract for toolchange")
+}
+
+// retractFactor will return the share of the retraction done now
+func (gw *GCodeWriter) retractFactor(beforeWipe bool) float64 {
+	if beforeWipe {
+		return gw.Extruder.RetractBeforeWipe
+	}
+	return 1
+}
+
+func (gw *GCodeWriter) retract(length float64, restartExtra float64, comment string) error {
+	// with firmware retraction we use a fake value of 1 since we ignore the actual
+	// configured retract length which might be 0, in which case the retraction
+	// logic gets skipped
+	if gw.UseFirmwareRetraction {
+		length = 1
+	}
+
+	// if we use volumetric E values we turn lengths into volumes
+	if gw.UseVolumetricE {
+		area := gw.Extruder.FilamentCrossSection()
+		length *= area
+		restartExtra *= area
+	}
+
+	dE := gw.Extruder.Retract(length, restartExtra)
+	if dE == 0 || gw.ExtrusionAxis() == "" {
+		return nil
+	}
+	if gw.UseFirmwareRetraction {
+		return gw.write("G10 ; retract", "")
+	}
+	return gw.write("G1 "+gw.ExtrusionAxis()+eNumber(gw.Extruder.E)+" F"+fNumber(gw.Extruder.RetractSpeed*60), comment)
+}
+
+// Unretract will push the retracted filament back in
+func (gw *GCodeWriter) Unretract() error {
+	if gw.Extruder == nil {
+		return nil
+	}
+	dE := gw.Extruder.Unretract()
+	if dE == 0 || gw.ExtrusionAxis() == "" {
+		return nil
+	}
+	if gw.UseFirmwareRetraction {
+		if err := gw.write("G11 ; unretract", ""); err != nil {
+			return err
+		}
+		return gw.ResetE(false)
+	}
+	// use G1 instead of G0 because G0 will blend the restart with the previous travel move
+	return gw.write("G1 "+gw.ExtrusionAxis()+eNumber(gw.Extruder.E)+" F"+fNumber(gw.Extruder.deretractSpeed()*60), "unretract")
+}
+
+// Lift will raise Z by the retract lift of the extruder, unless already lifted or
+// outside the Z range lifting is enabled in
+func (gw *GCodeWriter) Lift() error {
+	if gw.Extruder == nil || gw.lifted != 0 || gw.Extruder.RetractLift <= 0 {
+		return nil
+	}
+	z := gw.position.Z
+	if z < gw.Extruder.RetractLiftAbove || (gw.Extruder.RetractLiftBelow > 0 && z > gw.Extruder.RetractLiftBelow) {
+		return nil
+	}
+	gw.lifted = gw.Extruder.RetractLift
+	return gw.travelToZ(z+gw.lifted, "lift Z")
+}
+
+// Unlift will lower Z back to the layer after a lift
+func (gw *GCodeWriter) Unlift() error {
+	if gw.lifted <= 0 {
+		return nil
+	}
+	err := gw.travelToZ(gw.position.Z-gw.lifted, "restore layer Z")
+	gw.lifted = 0
+	return err
+}
+
+// ExtrudePath will travel to the start of the scaled path and extrude along it at
+// speed, with the E of its flow
+func (gw *GCodeWriter) ExtrudePath(path *ExtrusionPath, speed float64, comment string) error {
+	points := path.Polyline.MP.Points
+	if len(points) < 2 {
+		return nil
+	}
+	first := NewPoint(UnScale(points.First().X), UnScale(points.First().Y))
+	if first.DistanceTo(gw.position.Point) > Epsilon {
+		if err := gw.TravelToXY(first, "move to first "+path.Role.String()+" point"); err != nil {
+			return err
+		}
+	}
+	if err := gw.SetSpeed(speed*60, ""); err != nil {
+		return err
+	}
+
+	ePerMM := 0.0
+	if gw.Extruder != nil {
+		ePerMM = gw.Extruder.EPerMM(path.MM3PerMM)
+	}
+	for i := 1; i < len(points); i++ {
+		dE := ePerMM * UnScale(points[i-1].DistanceTo(points[i]))
+		if err := gw.ExtrudeToXY(NewPoint(UnScale(points[i].X), UnScale(points[i].Y)), dE, comment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExtrudeEntity will extrude the paths of the entity in order
+func (gw *GCodeWriter) ExtrudeEntity(entity ExtrusionEntity, speed float64, comment string) error {
+	switch e := entity.(type) {
+	case *ExtrusionPath:
+		return gw.ExtrudePath(e, speed, comment)
+	case *ExtrusionMultiPath:
+		return gw.extrudePaths(e.Paths, speed, comment)
+	case *ExtrusionLoop:
+		return gw.extrudePaths(e.Paths, speed, comment)
+	case *ExtrusionEntityCollection:
+		for _, child := range e.Entities {
+			if err := gw.ExtrudeEntity(child, speed, comment); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (gw *GCodeWriter) extrudePaths(paths ExtrusionPaths, speed float64, comment string) error {
+	for _, path := range paths {
+		if err := gw.ExtrudePath(path, speed, comment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package slice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// GeoJSON follows RFC 7946. Like WKT the coordinates are kept scaled, and rings are
+// closed when encoding and opened again when decoding. Contours are counter clockwise
+// and holes clockwise, which is also the winding RFC 7946 asks for.
+
+// ErrInvalidGeoJSON is returned when GeoJSON cannot be decoded
+var ErrInvalidGeoJSON = errors.New("Invalid GeoJSON")
+
+// GeoJSONObject is the JSON layout of any GeoJSON geometry, feature or collection
+type GeoJSONObject struct {
+	Type        string                 `json:"type"`
+	Coordinates json.RawMessage        `json:"coordinates,omitempty"`
+	Geometries  []*GeoJSONObject       `json:"geometries,omitempty"`
+	Geometry    *GeoJSONObject         `json:"geometry,omitempty"`
+	Features    []*GeoJSONObject       `json:"features,omitempty"`
+	Properties  map[string]interface{} `json:"properties,omitempty"`
+}
+
+func geoJSONPosition(point *Point) []float64 {
+	return []float64{point.X, point.Y}
+}
+
+func geoJSONPositions(points Points, ring bool) [][]float64 {
+	coords := make([][]float64, 0, len(points)+1)
+	for _, point := range points {
+		coords = append(coords, geoJSONPosition(point))
+	}
+	if ring && len(points) > 0 && !points.First().CoincidesWith(points.Last()) {
+		coords = append(coords, geoJSONPosition(points.First()))
+	}
+	return coords
+}
+
+func geoJSONRings(rings Polygons) [][][]float64 {
+	coords := make([][][]float64, 0, len(rings))
+	for _, ring := range rings {
+		coords = append(coords, geoJSONPositions(ring.MP.Points, true))
+	}
+	return coords
+}
+
+func newGeoJSONObject(kind string, coords interface{}) (*GeoJSONObject, error) {
+	raw, err := json.Marshal(coords)
+	if err != nil {
+		return nil, err
+	}
+	return &GeoJSONObject{Type: kind, Coordinates: raw}, nil
+}
+
+// NewGeoJSONObject will convert any of the supported geometries into a GeoJSON
+// geometry. A []interface{} becomes a GeometryCollection
+func NewGeoJSONObject(geometry interface{}) (*GeoJSONObject, error) {
+	switch geom := geometry.(type) {
+	case *Point:
+		return newGeoJSONObject("Point", geoJSONPosition(geom))
+	case *Line:
+		return newGeoJSONObject("LineString", geoJSONPositions(Points{geom.A, geom.B}, false))
+	case *Polyline:
+		return newGeoJSONObject("LineString", geoJSONPositions(geom.MP.Points, false))
+	case *Polygon:
+		return newGeoJSONObject("Polygon", geoJSONRings(Polygons{geom}))
+	case *PolygonEx:
+		return newGeoJSONObject("Polygon", geoJSONRings(geom.Polygons()))
+	case Points:
+		return newGeoJSONObject("MultiPoint", geoJSONPositions(geom, false))
+	case Polylines:
+		coords := make([][][]float64, 0, len(geom))
+		for _, pline := range geom {
+			coords = append(coords, geoJSONPositions(pline.MP.Points, false))
+		}
+		return newGeoJSONObject("MultiLineString", coords)
+	case Polygons:
+		coords := make([][][][]float64, 0, len(geom))
+		for _, poly := range geom {
+			coords = append(coords, geoJSONRings(Polygons{poly}))
+		}
+		return newGeoJSONObject("MultiPolygon", coords)
+	case PolygonExs:
+		coords := make([][][][]float64, 0, len(geom))
+		for _, pgx := range geom {
+			coords = append(coords, geoJSONRings(pgx.Polygons()))
+		}
+		return newGeoJSONObject("MultiPolygon", coords)
+	case []interface{}:
+		collection := &GeoJSONObject{Type: "GeometryCollection", Geometries: make([]*GeoJSONObject, 0, len(geom))}
+		for _, child := range geom {
+			obj, err := NewGeoJSONObject(child)
+			if err != nil {
+				return nil, err
+			}
+			collection.Geometries = append(collection.Geometries, obj)
+		}
+		return collection, nil
+	}
+	return nil, fmt.Errorf("Cannot write %T as GeoJSON", geometry)
+}
+
+// MarshalGeoJSON will encode any of the supported geometries as a GeoJSON geometry
+func MarshalGeoJSON(geometry interface{}) ([]byte, error) {
+	obj, err := NewGeoJSONObject(geometry)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(obj)
+}
+
+func geoJSONPoint(coord []float64) (*Point, error) {
+	if len(coord) < 2 {
+		return nil, fmt.Errorf("%w: positions need X and Y", ErrInvalidGeoJSON)
+	}
+	return NewPoint(coord[0], coord[1]), nil
+}
+
+func geoJSONPoints(coords [][]float64) (Points, error) {
+	points := make(Points, 0, len(coords))
+	for _, coord := range coords {
+		point, err := geoJSONPoint(coord)
+		if err != nil {
+			return nil, err
+		}
+		points.Push(point)
+	}
+	return points, nil
+}
+
+func geoJSONPolygonEx(coords [][][]float64) (*PolygonEx, error) {
+	pgx := NewPolygonEx()
+	for index, ringCoords := range coords {
+		points, err := geoJSONPoints(ringCoords)
+		if err != nil {
+			return nil, err
+		}
+		if len(points) > 1 && points.First().CoincidesWith(points.Last()) {
+			points.PopBack()
+		}
+		ring := NewPolygon()
+		ring.MP.Points = points
+		if index == 0 {
+			pgx.Contour = ring
+		} else {
+			pgx.Holes.Push(ring)
+		}
+	}
+	return pgx, nil
+}
+
+// Decode will convert the GeoJSON object into the slicer's types. Geometries map the
+// same way ParseWKT does. A Feature gives its geometry and a FeatureCollection or
+// GeometryCollection gives a []interface{}
+func (obj *GeoJSONObject) Decode() (interface{}, error) {
+	decode := func(coords interface{}) error {
+		if err := json.Unmarshal(obj.Coordinates, coords); err != nil {
+			return fmt.Errorf("%w: %s coordinates: %v", ErrInvalidGeoJSON, obj.Type, err)
+		}
+		return nil
+	}
+
+	switch obj.Type {
+	case "Point":
+		var coords []float64
+		if err := decode(&coords); err != nil {
+			return nil, err
+		}
+		return geoJSONPoint(coords)
+
+	case "LineString":
+		var coords [][]float64
+		if err := decode(&coords); err != nil {
+			return nil, err
+		}
+		points, err := geoJSONPoints(coords)
+		if err != nil {
+			return nil, err
+		}
+		pline := NewPolyline()
+		pline.MP.Points = points
+		return pline, nil
+
+	case "Polygon":
+		var coords [][][]float64
+		if err := decode(&coords); err != nil {
+			return nil, err
+		}
+		return geoJSONPolygonEx(coords)
+
+	case "MultiPoint":
+		var coords [][]float64
+		if err := decode(&coords); err != nil {
+			return nil, err
+		}
+		return geoJSONPoints(coords)
+
+	case "MultiLineString":
+		var coords [][][]float64
+		if err := decode(&coords); err != nil {
+			return nil, err
+		}
+		plines := NewPolylines()
+		for _, lineCoords := range coords {
+			points, err := geoJSONPoints(lineCoords)
+			if err != nil {
+				return nil, err
+			}
+			pline := NewPolyline()
+			pline.MP.Points = points
+			plines.Push(pline)
+		}
+		return plines, nil
+
+	case "MultiPolygon":
+		var coords [][][][]float64
+		if err := decode(&coords); err != nil {
+			return nil, err
+		}
+		pgxs := NewPolygonExs()
+		for _, polyCoords := range coords {
+			pgx, err := geoJSONPolygonEx(polyCoords)
+			if err != nil {
+				return nil, err
+			}
+			pgxs.Push(pgx)
+		}
+		return pgxs, nil
+
+	case "GeometryCollection", "FeatureCollection":
+		children := obj.Geometries
+		if obj.Type == "FeatureCollection" {
+			children = obj.Features
+		}
+		geometries := make([]interface{}, 0, len(children))
+		for _, child := range children {
+			geometry, err := child.Decode()
+			if err != nil {
+				return nil, err
+			}
+			geometries = append(geometries, geometry)
+		}
+		return geometries, nil
+
+	case "Feature":
+		if obj.Geometry == nil {
+			return nil, nil
+		}
+		return obj.Geometry.Decode()
+	}
+	return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidGeoJSON, obj.Type)
+}
+
+// UnmarshalGeoJSON will decode a GeoJSON geometry, feature or collection
+func UnmarshalGeoJSON(data []byte) (interface{}, error) {
+	obj := new(GeoJSONObject)
+	if err := json.Unmarshal(data, obj); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidGeoJSON, err)
+	}
+	return obj.Decode()
+}
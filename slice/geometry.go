@@ -1,6 +1,9 @@
 package slice
 
-import "math"
+import (
+	"math"
+	"sort"
+)
 
 // DirectionsParallel will figure out something
 func DirectionsParallel(angle1 float64, angle2 float64, maxDiff float64) bool {
@@ -13,3 +16,40 @@ func DirectionsParallel(angle1 float64, angle2 float64, maxDiff float64) bool {
 func DirectionsParallelDefault(angle1 float64, angle2 float64) bool {
 	return DirectionsParallel(angle1, angle2, 0.00)
 }
+
+// ConvexHull will return the counter clockwise convex hull of the points, using
+// Andrew's monotone chain
+func ConvexHull(points Points) *Polygon {
+	sorted := points.GetCopy()
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].X == sorted[j].X {
+			return sorted[i].Y < sorted[j].Y
+		}
+		return sorted[i].X < sorted[j].X
+	})
+
+	hull := NewPolygon()
+	if len(sorted) < 3 {
+		hull.MP.Points.Push(sorted...)
+		return hull
+	}
+
+	// lower hull left to right, then upper hull right to left
+	chain := make(Points, 0, 2*len(sorted))
+	for pass := 0; pass < 2; pass++ {
+		start := len(chain)
+		for _, point := range sorted {
+			for len(chain) >= start+2 && point.CCW(chain[len(chain)-2], chain[len(chain)-1]) <= 0 {
+				chain = chain[:len(chain)-1]
+			}
+			chain = append(chain, point)
+		}
+		// the last point starts the other half
+		chain = chain[:len(chain)-1]
+		for i, j := 0, len(sorted)-1; i < j; i, j = i+1, j-1 {
+			sorted[i], sorted[j] = sorted[j], sorted[i]
+		}
+	}
+	hull.MP.Points.Push(chain...)
+	return hull
+}
@@ -0,0 +1,123 @@
+package slice
+
+import (
+	"math"
+	"sort"
+)
+
+// SegmentIntersection is an intersection found between two lines of a set
+type SegmentIntersection struct {
+	LineA   int    // index of the first line
+	LineB   int    // index of the second line, always greater than LineA
+	Point   *Point // the intersection, or the start of the overlap for collinear lines
+	Overlap *Line  // set when the lines are collinear and share more than a point
+}
+
+// SegmentIntersections is a collection of SegmentIntersection
+type SegmentIntersections []*SegmentIntersection
+
+// IntersectSegments will find where two lines meet. nil is returned if they do not touch
+func IntersectSegments(a *Line, b *Line) *SegmentIntersection {
+	d1 := a.Vector()
+	d2 := b.Vector()
+	lenSq := d1.X*d1.X + d1.Y*d1.Y
+	if lenSq < Epsilon || d2.X*d2.X+d2.Y*d2.Y < Epsilon {
+		return nil // zero length lines cannot intersect anything
+	}
+
+	cross := d1.X*d2.Y - d1.Y*d2.X
+	if math.Abs(cross) >= Epsilon {
+		point, err := a.Intersection(b)
+		if err != nil {
+			return nil
+		}
+		return &SegmentIntersection{Point: point}
+	}
+
+	// parallel, the lines can only touch if they are collinear
+	if math.Abs(b.A.CCW(a.A, a.B))/math.Sqrt(lenSq) >= Epsilon {
+		return nil
+	}
+
+	// project b onto a and find the shared part
+	t0 := ((b.A.X-a.A.X)*d1.X + (b.A.Y-a.A.Y)*d1.Y) / lenSq
+	t1 := ((b.B.X-a.A.X)*d1.X + (b.B.Y-a.A.Y)*d1.Y) / lenSq
+	lo := math.Max(0, math.Min(t0, t1))
+	hi := math.Min(1, math.Max(t0, t1))
+	length := math.Sqrt(lenSq)
+	if (hi-lo)*length < -Epsilon {
+		return nil
+	}
+
+	start := NewPoint(a.A.X+d1.X*lo, a.A.Y+d1.Y*lo)
+	if (hi-lo)*length < Epsilon {
+		return &SegmentIntersection{Point: start}
+	}
+	end := NewPoint(a.A.X+d1.X*hi, a.A.Y+d1.Y*hi)
+	return &SegmentIntersection{
+		Point:   start,
+		Overlap: NewLine(NewPoint(start.X, start.Y), end),
+	}
+}
+
+// FindIntersections will report every intersection between the supplied lines. The lines are
+// indexed by their X extents and swept from left to right so only lines that share an X range
+// are ever compared, then only those that also share a Y range are intersected. This is not
+// Bentley-Ottmann: it costs O(n log n + a) where a is the number of pairs of lines whose X
+// ranges overlap. The edges of sliced polygons are short so a stays near n, but long lines
+// all spanning the same X range make it O(n²)
+func FindIntersections(lines []*Line) SegmentIntersections {
+	minX := func(l *Line) float64 { return math.Min(l.A.X, l.B.X) }
+	maxX := func(l *Line) float64 { return math.Max(l.A.X, l.B.X) }
+
+	order := make([]int, len(lines))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return minX(lines[order[i]]) < minX(lines[order[j]])
+	})
+
+	result := make(SegmentIntersections, 0)
+	active := make([]int, 0)
+	for _, idx := range order {
+		line := lines[idx]
+		left := minX(line)
+		lineMinY := math.Min(line.A.Y, line.B.Y)
+		lineMaxY := math.Max(line.A.Y, line.B.Y)
+
+		// retire lines that end before this one starts
+		kept := active[:0]
+		for _, other := range active {
+			if maxX(lines[other]) >= left-Epsilon {
+				kept = append(kept, other)
+			}
+		}
+		active = kept
+
+		for _, other := range active {
+			o := lines[other]
+			if math.Max(o.A.Y, o.B.Y) < lineMinY-Epsilon || math.Min(o.A.Y, o.B.Y) > lineMaxY+Epsilon {
+				continue
+			}
+			inter := IntersectSegments(o, line)
+			if inter == nil {
+				continue
+			}
+			inter.LineA, inter.LineB = other, idx
+			if other > idx {
+				inter.LineA, inter.LineB = idx, other
+			}
+			result = append(result, inter)
+		}
+		active = append(active, idx)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].LineA != result[j].LineA {
+			return result[i].LineA < result[j].LineA
+		}
+		return result[i].LineB < result[j].LineB
+	})
+	return result
+}
@@ -0,0 +1,364 @@
+package slice
+
+import "math"
+
+// Layer holds what was sliced out of an object at one height. Z values are unscaled
+type Layer struct {
+	ID     int
+	SliceZ float64 // Z the mesh was cut at
+	PrintZ float64 // Z of the top of the layer
+	Height float64
+	Slices PolygonExs
+
+	// Surfaces are the slices classified by DetectSurfacesType, of every region
+	Surfaces Surfaces
+
+	// Regions split the slices by the config they are printed with, empty until
+	// PrintObject.SplitRegions
+	Regions LayerRegions
+
+	// support generated under the layers above by SupportMaterial
+	SupportIslands        PolygonExs
+	SupportFills          *ExtrusionEntityCollection
+	SupportInterfaceFills *ExtrusionEntityCollection
+}
+
+// NewLayer will construct an empty layer
+func NewLayer(id int, sliceZ float64, printZ float64, height float64) *Layer {
+	layer := new(Layer)
+	layer.ID = id
+	layer.SliceZ = sliceZ
+	layer.PrintZ = printZ
+	layer.Height = height
+	layer.Slices = NewPolygonExs()
+	layer.Surfaces = NewSurfaces()
+	layer.Regions = NewLayerRegions()
+	layer.SupportIslands = NewPolygonExs()
+	layer.SupportFills = NewExtrusionEntityCollection()
+	layer.SupportInterfaceFills = NewExtrusionEntityCollection()
+	return layer
+}
+
+// LayerRegion is the part of a layer printed with one region config
+type LayerRegion struct {
+	Config   *PrintRegionConfig // nil for the single region of a layer that was not split
+	Slices   PolygonExs
+	Surfaces Surfaces
+}
+
+// NewLayerRegion will construct an empty region printed with config
+func NewLayerRegion(config *PrintRegionConfig) *LayerRegion {
+	region := new(LayerRegion)
+	region.Config = config
+	region.Slices = NewPolygonExs()
+	region.Surfaces = NewSurfaces()
+	return region
+}
+
+// config will return the config the region is printed with, the defaults for the
+// region of a layer that was not split
+func (region *LayerRegion) config() *PrintRegionConfig {
+	if region.Config == nil {
+		return NewPrintRegionConfig()
+	}
+	return region.Config
+}
+
+// Flow will return the flow of role on layer with the width the region config sets
+// for it. Bridges are round with the bridge flow ratio of the region
+func (region *LayerRegion) Flow(role FlowRole, layer *Layer, nozzleDiameter float64, bridge bool) (*Flow, error) {
+	cfg := region.config()
+	var width FloatOrPercent
+	switch role {
+	case FrExternalPerimeter:
+		width = cfg.ExternalPerimeterExtrusionWidth
+	case FrPerimeter:
+		width = cfg.PerimeterExtrusionWidth
+	case FrInfill:
+		width = cfg.InfillExtrusionWidth
+	case FrSolidInfill:
+		width = cfg.SolidInfillExtrusionWidth
+	case FrTopSolidInfill:
+		width = cfg.TopInfillExtrusionWidth
+	}
+	bridgeFlowRatio := 0.0
+	if bridge {
+		bridgeFlowRatio = cfg.BridgeFlowRatio
+	}
+	return NewFlowFromConfigWidth(role, width, nozzleDiameter, layer.Height, bridgeFlowRatio)
+}
+
+// PerimeterGenerator will construct a PerimeterGenerator for the slices of the region
+// with the perimeters and the extrusion widths of its config
+func (region *LayerRegion) PerimeterGenerator(layer *Layer, nozzleDiameter float64) (*PerimeterGenerator, error) {
+	perimeterFlow, err := region.Flow(FrPerimeter, layer, nozzleDiameter, false)
+	if err != nil {
+		return nil, err
+	}
+	extPerimeterFlow, err := region.Flow(FrExternalPerimeter, layer, nozzleDiameter, false)
+	if err != nil {
+		return nil, err
+	}
+	solidInfillFlow, err := region.Flow(FrSolidInfill, layer, nozzleDiameter, false)
+	if err != nil {
+		return nil, err
+	}
+	return NewPerimeterGeneratorFromFlows(region.Slices, layer.ID, region.config().Perimeters, perimeterFlow, extPerimeterFlow, solidInfillFlow), nil
+}
+
+// FillPattern will return the pattern of the surface: rectilinear for bridges, which
+// need parallel lines along their angle, the external pattern of the region for top
+// and bottom surfaces, rectilinear for the other solid ones and the fill pattern of
+// the region for sparse infill
+func (region *LayerRegion) FillPattern(surface *Surface) FillPattern {
+	switch {
+	case surface.IsBridge():
+		return FpRectilinear
+	case surface.IsExternal():
+		return region.config().ExternalFillPattern
+	case surface.IsSolid():
+		return FpRectilinear
+	}
+	return region.config().FillPattern
+}
+
+// FillFlow will return the flow the surface is filled with on layer. Bridges above
+// the first layer are printed with the bridge flow
+func (region *LayerRegion) FillFlow(surface *Surface, layer *Layer, nozzleDiameter float64) (*Flow, error) {
+	role := FrInfill
+	switch {
+	case surface.IsTop():
+		role = FrTopSolidInfill
+	case surface.IsSolid():
+		role = FrSolidInfill
+	}
+	return region.Flow(role, layer, nozzleDiameter, surface.IsBridge() && layer.ID > 0)
+}
+
+// FillParams will construct the FillParams of the surface on layer with the density
+// and the angle of the region config, and the spacing of flow. Solid surfaces are
+// filled at full density
+func (region *LayerRegion) FillParams(surface *Surface, layer *Layer, flow *Flow) *FillParams {
+	cfg := region.config()
+	density := cfg.FillDensity / 100
+	if surface.IsSolid() {
+		density = 1
+	}
+	params := NewFillParamsForSurface(surface, layer.ID, density, cfg.FillAngle*math.Pi/180, flow.ScaledSpacing())
+	params.Z = layer.PrintZ
+	return params
+}
+
+// BoundingBox will return the bounding box of the layer slices
+func (layer *Layer) BoundingBox() *BoundingBox {
+	bb := new(BoundingBox)
+	for _, pgx := range layer.Slices {
+		for _, point := range pgx.Contour.MP.Points {
+			bb.MergePoint(point)
+		}
+	}
+	return bb
+}
+
+// regions will return the regions of the layer, or a region of all its slices when
+// it was not split
+func (layer *Layer) regions() LayerRegions {
+	if !layer.Regions.Empty() {
+		return layer.Regions
+	}
+	region := NewLayerRegion(nil)
+	region.Slices = layer.Slices
+	region.Surfaces = layer.Surfaces
+	return LayerRegions{region}
+}
+
+// mergeSurfaces will gather the surfaces of the regions into the layer
+func (layer *Layer) mergeSurfaces(regions LayerRegions) {
+	layer.Surfaces = NewSurfaces()
+	for _, region := range regions {
+		layer.Surfaces.Push(region.Surfaces...)
+	}
+}
+
+// DetectSurfacesType will classify the slices of every region by comparing them with
+// the layers above and below. What has nothing above is top, what has nothing below is
+// bottom, printed over air above the first layer, and the rest is internal
+func DetectSurfacesType(layers Layers) error {
+	for i, layer := range layers {
+		regions := layer.regions()
+		for _, region := range regions {
+			if err := region.detectSurfacesType(layers, i); err != nil {
+				return err
+			}
+		}
+		layer.mergeSurfaces(regions)
+	}
+	return nil
+}
+
+// detectSurfacesType will classify the slices of the region of layers[i]. Where
+// regions of a layer meet is neither top nor bottom, as the whole layers are compared
+func (region *LayerRegion) detectSurfacesType(layers Layers, i int) error {
+	slices := region.Slices.Polygons()
+	height := layers[i].Height
+
+	var top PolygonExs
+	var err error
+	if i+1 < len(layers) {
+		top, err = DiffEx(slices, layers[i+1].Slices.Polygons())
+	} else {
+		top, err = UnionEx(slices)
+	}
+	if err != nil {
+		return err
+	}
+
+	var bottom PolygonExs
+	bottomType := StBottom
+	if i > 0 {
+		bottom, err = DiffEx(slices, layers[i-1].Slices.Polygons())
+		bottomType = StBottomBridge
+	} else {
+		bottom, err = UnionEx(slices)
+	}
+	if err != nil {
+		return err
+	}
+
+	// surfaces that are both top and bottom are printed as bottom
+	if !top.Empty() && !bottom.Empty() {
+		if top, err = DiffEx(top.Polygons(), bottom.Polygons()); err != nil {
+			return err
+		}
+	}
+
+	external := append(top.Polygons(), bottom.Polygons()...)
+	internal, err := DiffEx(slices, external)
+	if err != nil {
+		return err
+	}
+
+	region.Surfaces = NewSurfaces()
+	region.Surfaces.Append(top, StTop)
+	region.Surfaces.Append(bottom, bottomType)
+	region.Surfaces.Append(internal, StInternal)
+	for _, surface := range region.Surfaces {
+		surface.Thickness = height
+	}
+	return nil
+}
+
+// DiscoverVerticalShells will make the internal surfaces solid within topLayers of a
+// top surface and bottomLayers of a bottom surface, counting the external layer. The
+// regions of split layers use the shell counts of their config
+func DiscoverVerticalShells(layers Layers, topLayers int, bottomLayers int) error {
+	for i, layer := range layers {
+		for r, region := range layer.regions() {
+			top, bottom := topLayers, bottomLayers
+			if region.Config != nil {
+				top, bottom = region.Config.TopSolidLayers, region.Config.BottomSolidLayers
+			}
+			for _, st := range []SurfaceType{StTop, StBottom, StBottomBridge} {
+				shells, step := bottom, 1
+				if st == StTop {
+					shells, step = top, -1
+				}
+
+				solid := region.Surfaces.Filter(st).Polygons()
+				for n := 1; n < shells && !solid.Empty(); n++ {
+					j := i + n*step
+					if j < 0 || j >= len(layers) {
+						break
+					}
+					neighbor := layers[j]
+					neighborRegions := neighbor.regions()
+					if r >= len(neighborRegions) {
+						break
+					}
+
+					// the shell can't grow past the neighbour's slices
+					var err error
+					if solid, err = Intersection(solid, neighbor.Slices.Polygons()); err != nil {
+						return err
+					}
+					if err = neighborRegions[r].makeSolid(solid, StInternal, StInternalSolid); err != nil {
+						return err
+					}
+					neighbor.mergeSurfaces(neighborRegions)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// BridgeOverInfill will turn internal solid surfaces printed right over sparse infill
+// into internal bridges
+func BridgeOverInfill(layers Layers) error {
+	for i := 1; i < len(layers); i++ {
+		sparse := layers[i-1].Surfaces.Filter(StInternal).Polygons()
+		if sparse.Empty() {
+			continue
+		}
+		regions := layers[i].regions()
+		for _, region := range regions {
+			if err := region.makeSolid(sparse, StInternalSolid, StInternalBridge); err != nil {
+				return err
+			}
+		}
+		layers[i].mergeSurfaces(regions)
+	}
+	return nil
+}
+
+// SolidifySmallAreas will make the sparse surfaces smaller than minArea solid, as
+// they are not worth switching to sparse infill for. The regions of split layers use
+// the solid_infill_below_area of their config
+func SolidifySmallAreas(layers Layers, minArea float64) {
+	for _, layer := range layers {
+		for _, region := range layer.regions() {
+			threshold := minArea
+			if region.Config != nil {
+				threshold = Scale(1) * Scale(region.Config.SolidInfillBelowArea)
+			}
+			for _, surface := range region.Surfaces.Filter(StInternal) {
+				if math.Abs(surface.Area()) <= threshold {
+					surface.SurfaceType = StInternalSolid
+				}
+			}
+		}
+	}
+}
+
+// makeSolid will change the parts of the from surfaces covered by polygons to type to
+func (region *LayerRegion) makeSolid(polygons Polygons, from SurfaceType, to SurfaceType) error {
+	surfaces := region.Surfaces.Filter(from)
+	if surfaces.Empty() || polygons.Empty() {
+		return nil
+	}
+	covered, err := IntersectionEx(surfaces.Polygons(), polygons)
+	if err != nil || covered.Empty() {
+		return err
+	}
+	rest, err := DiffEx(surfaces.Polygons(), polygons)
+	if err != nil {
+		return err
+	}
+
+	kept := NewSurfaces()
+	for _, surface := range region.Surfaces {
+		if surface.SurfaceType != from {
+			kept.Push(surface)
+		}
+	}
+	for _, pgx := range rest {
+		kept.Push(NewSurfaceFrom(surfaces.First(), pgx))
+	}
+	for _, pgx := range covered {
+		surface := NewSurfaceFrom(surfaces.First(), pgx)
+		surface.SurfaceType = to
+		kept.Push(surface)
+	}
+	region.Surfaces = kept
+	return nil
+}
@@ -1,10 +1,18 @@
 package slice
 
 import (
+	"errors"
 	"fmt"
 	"math"
 )
 
+var (
+	// ErrParallelLines is returned when lines never meet at a single point
+	ErrParallelLines = errors.New("Lines are parallel")
+	// ErrNoIntersection is returned when line segments do not touch
+	ErrNoIntersection = errors.New("Lines do not intersect")
+)
+
 // Line is a line constructed from two points
 type Line struct {
 	A *Point
@@ -44,9 +52,7 @@ func (l *Line) Rotate(angle float64, center *Point) {
 
 // Reverse will swap points A and B
 func (l *Line) Reverse() {
-	tmp := NewPoint(l.A.X, l.A.Y)
-	l.A = l.B
-	l.B = tmp
+	l.A, l.B = l.B, l.A
 }
 
 // Length will return the length of a line
@@ -59,10 +65,11 @@ func (l *Line) Midpoint() *Point {
 	return NewPoint((l.A.X+l.B.X)/2.0, (l.A.Y+l.B.Y)/2.0)
 }
 
-// PointAt will push a point to the supplied point at the supplied distance
-func (l *Line) PointAt(distance float64, point *Point) {
+// PointAt will return the point at a distance from A along the line. Negative
+// distances and distances past B extend the line
+func (l *Line) PointAt(distance float64) *Point {
 	len := l.Length()
-	point = l.A
+	point := NewPoint(l.A.X, l.A.Y)
 
 	if l.A.X != l.B.X {
 		point.X = l.A.X + (l.B.X-l.A.X)*distance/len
@@ -71,30 +78,22 @@ func (l *Line) PointAt(distance float64, point *Point) {
 	if l.A.Y != l.B.Y {
 		point.Y = l.A.Y + (l.B.Y-l.A.Y)*distance/len
 	}
+	return point
 }
 
-// GetPointAt will return a point at a distance
-func (l *Line) GetPointAt(distance float64) (p *Point) {
-	l.PointAt(distance, p)
-	return
-}
-
-// IntersectionInfinite will figure out if two lines intersect
-func (l *Line) IntersectionInfinite(other *Line, point *Point) bool {
-
+// IntersectionInfinite will find where the two lines meet if they were extended forever
+func (l *Line) IntersectionInfinite(other *Line) (*Point, error) {
 	x := l.A.VectorTo(other.A)
 	d1 := l.Vector()
 	d2 := other.Vector()
 
-	var cross float64 = d1.X*d2.X - d1.Y*d2.Y
+	var cross float64 = d1.X*d2.Y - d1.Y*d2.X
 	if math.Abs(cross) < Epsilon {
-		return false
+		return nil, ErrParallelLines
 	}
 
 	var t1 float64 = (x.X*d2.Y - x.Y*d2.X) / cross
-	point.X = l.A.X + d1.X*t1
-	point.Y = l.A.Y + d1.Y*t1
-	return true
+	return NewPoint(l.A.X+d1.X*t1, l.A.Y+d1.Y*t1), nil
 }
 
 // CoincidesWith will determine if it coincides with another line
@@ -142,7 +141,7 @@ func (l *Line) ParallelToLine(line *Line) bool {
 	return l.ParallelTo(line.Direction())
 }
 
-//Vector will return a vector of this line
+// Vector will return a vector of this line
 func (l *Line) Vector() *Point {
 	return NewPoint(l.B.X-l.A.X, l.B.Y-l.A.Y)
 }
@@ -154,29 +153,24 @@ func (l *Line) Normal() *Point {
 
 // ExtendEnd will extend the end of the line
 func (l *Line) ExtendEnd(distance float64) {
-	var line *Line = l
-	line.Reverse()
-	l.B = line.GetPointAt(-distance)
+	line := NewLine(l.B, l.A)
+	l.B = line.PointAt(-distance)
 }
 
 // ExtendStart will extend the start of the line
 func (l *Line) ExtendStart(distance float64) {
-	l.A = l.GetPointAt(-distance)
+	l.A = l.PointAt(-distance)
 }
 
-// TODO Goify these types of functions (Return an Error and point instead of bool)
-
-// Intersection will detect an intersection with a supplied line
-func (l *Line) Intersection(line *Line, intersection *Point) bool {
+// Intersection will find where this line segment crosses a supplied segment
+func (l *Line) Intersection(line *Line) (*Point, error) {
 	denom := ((line.B.Y-line.A.Y)*(l.B.X-l.A.X) - (line.B.X-line.A.X)*(l.B.Y-l.A.Y))
 	numeA := ((line.B.X-line.A.X)*(l.A.Y-line.A.Y) - (line.B.Y-line.A.Y)*(l.A.X-line.A.X))
 	numeB := ((l.B.X-l.A.X)*(l.A.Y-line.A.Y) - (l.B.Y-l.A.Y)*(l.A.X-line.A.X))
 
 	if math.Abs(denom) < Epsilon {
-		if math.Abs(numeA) < Epsilon && math.Abs(numeB) < Epsilon {
-			return false // coincident
-		}
-		return false // parallel
+		// coincident or parallel
+		return nil, ErrParallelLines
 	}
 
 	ua := numeA / denom
@@ -184,11 +178,9 @@ func (l *Line) Intersection(line *Line, intersection *Point) bool {
 
 	if ua >= 0 && ua <= 1.0 && ub >= 0 && ub <= 1.0 {
 		// get the intersection point
-		intersection.X = l.A.X + ua*(l.B.X-l.A.X)
-		intersection.Y = l.A.Y + ua*(l.B.Y-l.A.Y)
-		return true
+		return NewPoint(l.A.X+ua*(l.B.X-l.A.X), l.A.Y+ua*(l.B.Y-l.A.Y)), nil
 	}
-	return false // not intersecting
+	return nil, ErrNoIntersection
 }
 
 // CCW will rotate the line CCW
@@ -18,12 +18,14 @@ func (lm LocalMininumSort) Swap(i, j int) {
 	lm[i], lm[j] = lm[j], lm[i]
 }
 
+// Less will order the minima from the bottom up. Y grows downward inside the
+// clipper so the bottom most minima has the largest Y
 func (lm LocalMininumSort) Less(i, j int) bool {
-	return lm[i].Y < lm[j].Y
+	return lm[i].Y > lm[j].Y
 }
 
 func SortLocalMinimum(lms LocalMinimums) {
-	sort.Sort(LocalMininumSort(lms))
+	sort.Stable(LocalMininumSort(lms))
 }
 
 // LocalMinimums is a collection of points
@@ -48,8 +50,8 @@ func (lm LocalMinimums) Empty() bool {
 }
 
 // Clear will clear all points
-func (lm LocalMinimums) Clear() {
-	lm = NewLocalMinimums()
+func (lm *LocalMinimums) Clear() {
+	*lm = NewLocalMinimums()
 }
 
 // First will get the first entry
@@ -0,0 +1,404 @@
+package slice
+
+import (
+	"math"
+	"sort"
+)
+
+// MotionPlanner finds travels that stay inside the islands of a layer so they don't
+// cross external perimeters. This follows Slic3r's MotionPlanner.cpp: each island,
+// and the space around all of them, gets a visibility graph between the vertices of
+// its shrunk and simplified boundary, searched with Dijkstra's algorithm. Environments
+// and their graphs are only built once a travel needs them. Points are scaled
+type MotionPlanner struct {
+	Islands     PolygonExs
+	InnerMargin float64 // how far inside the islands travels keep
+	OuterMargin float64 // how far from the islands travels between them keep
+
+	envs  []*motionPlannerEnv // one per island, nil until a travel stays inside it
+	outer *motionPlannerEnv   // around the islands, nil until a travel leaves one
+}
+
+// motionPlannerEnv is the area travels may use and its visibility graph
+type motionPlannerEnv struct {
+	env      motionRegion
+	nodes    Points
+	adjacent [][]int // nil until the first search through the environment
+}
+
+// motionRegion is an area prepared for visibility tests, every ring and edge keeps its
+// bounding box so the ones away from a segment are skipped
+type motionRegion []*motionArea
+
+// motionArea is one polygon of a motionRegion
+type motionArea struct {
+	bb    *BoundingBox // of the contour
+	rings []*motionRing
+	pgx   *PolygonEx
+}
+
+// motionRing is one ring of a motionArea
+type motionRing struct {
+	bb     *BoundingBox
+	points Points
+	edges  []*Line
+	boxes  []*BoundingBox // of the edges
+}
+
+// NewMotionPlanner will construct a MotionPlanner for the islands with Slic3r's
+// margins of 1mm inside the islands and 2mm around them
+func NewMotionPlanner(islands PolygonExs) *MotionPlanner {
+	planner := new(MotionPlanner)
+	planner.Islands = islands
+	planner.InnerMargin = Scale(1)
+	planner.OuterMargin = Scale(2)
+	return planner
+}
+
+// newMotionRegion will prepare the polygons for visibility tests
+func newMotionRegion(pgxs PolygonExs) motionRegion {
+	region := make(motionRegion, 0, len(pgxs))
+	for _, pgx := range pgxs {
+		if len(pgx.Contour.MP.Points) == 0 {
+			continue
+		}
+		area := &motionArea{pgx: pgx, bb: NewBoundingBox(pgx.Contour.MP.Points...)}
+		for _, pg := range pgx.Polygons() {
+			if len(pg.MP.Points) == 0 {
+				continue
+			}
+			ring := &motionRing{bb: NewBoundingBox(pg.MP.Points...), points: pg.MP.Points, edges: pg.Lines()}
+			for _, edge := range ring.edges {
+				ring.boxes = append(ring.boxes, NewBoundingBox(edge.A, edge.B))
+			}
+			area.rings = append(area.rings, ring)
+		}
+		region = append(region, area)
+	}
+	return region
+}
+
+// islandEnv will return the environment inside island i, building it the first time
+func (planner *MotionPlanner) islandEnv(i int) (*motionPlannerEnv, error) {
+	if planner.envs == nil {
+		planner.envs = make([]*motionPlannerEnv, len(planner.Islands))
+	}
+	if planner.envs[i] != nil {
+		return planner.envs[i], nil
+	}
+
+	// the island shrunk by the margin, or the island itself when too thin for it
+	island := planner.Islands[i]
+	env, err := OffsetEx(island.Polygons(), -planner.InnerMargin)
+	if err != nil {
+		return nil, err
+	}
+	if env.Empty() {
+		env = PolygonExs{island}
+	}
+	if planner.envs[i], err = newMotionPlannerEnv(env, planner.InnerMargin/4); err != nil {
+		return nil, err
+	}
+	return planner.envs[i], nil
+}
+
+// outerEnv will return the environment around the islands, building it the first time
+func (planner *MotionPlanner) outerEnv() (*motionPlannerEnv, error) {
+	if planner.outer != nil {
+		return planner.outer, nil
+	}
+
+	// the bounding box of all islands minus the islands, both grown by the margin
+	bb := new(BoundingBox)
+	for _, island := range planner.Islands {
+		for _, point := range island.Contour.MP.Points {
+			bb.MergePoint(point)
+		}
+	}
+	bb.Offset(2 * planner.OuterMargin)
+	grown, err := Offset(planner.Islands.Polygons(), planner.OuterMargin)
+	if err != nil {
+		return nil, err
+	}
+	outer, err := DiffEx(Polygons{bb.Polygon()}, grown)
+	if err != nil {
+		return nil, err
+	}
+	if planner.outer, err = newMotionPlannerEnv(outer, planner.OuterMargin/4); err != nil {
+		return nil, err
+	}
+	return planner.outer, nil
+}
+
+// newMotionPlannerEnv will simplify env within tolerance so curved boundaries don't
+// give a node for every short edge, and take the vertices left as the nodes of the
+// graph. A quarter of the margin keeps travels well away from the perimeters
+func newMotionPlannerEnv(env PolygonExs, tolerance float64) (*motionPlannerEnv, error) {
+	simplified := NewPolygonExs()
+	for _, pgx := range env {
+		pgxs, err := pgx.Simplify(tolerance)
+		if err != nil {
+			return nil, err
+		}
+		simplified.Push(pgxs...)
+	}
+	if simplified.Empty() {
+		simplified = env
+	}
+
+	mpe := new(motionPlannerEnv)
+	mpe.env = newMotionRegion(simplified)
+	mpe.nodes = NewPoints()
+	for _, pgx := range simplified {
+		mpe.nodes.Push(pgx.Points()...)
+	}
+	return mpe, nil
+}
+
+// graph will connect every pair of nodes which see each other the first time it is called
+func (mpe *motionPlannerEnv) graph() [][]int {
+	if mpe.adjacent != nil {
+		return mpe.adjacent
+	}
+	mpe.adjacent = make([][]int, len(mpe.nodes))
+	for i, a := range mpe.nodes {
+		for j := i + 1; j < len(mpe.nodes); j++ {
+			if mpe.env.visible(a, mpe.nodes[j]) {
+				mpe.adjacent[i] = append(mpe.adjacent[i], j)
+				mpe.adjacent[j] = append(mpe.adjacent[j], i)
+			}
+		}
+	}
+	return mpe.adjacent
+}
+
+// islandOf will return the index of the island containing point, -1 if there is none
+func (planner *MotionPlanner) islandOf(point *Point) int {
+	for i, island := range planner.Islands {
+		if island.ContainsPoint(point) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ShortestPath will return the shortest travel from one point to another which stays
+// inside their island, or goes around the islands when the points are not in the
+// same one. The travel is a straight line when there is no better way
+func (planner *MotionPlanner) ShortestPath(from *Point, to *Point) (*Polyline, error) {
+	straight := polylineOf(from, to)
+	if planner.Islands.Empty() || from.CoincidesWith(to) {
+		return straight, nil
+	}
+
+	var region motionRegion
+	var envOf func() (*motionPlannerEnv, error)
+	fromIsland, toIsland := planner.islandOf(from), planner.islandOf(to)
+	if fromIsland >= 0 && fromIsland == toIsland {
+		region = newMotionRegion(PolygonExs{planner.Islands[fromIsland]})
+		envOf = func() (*motionPlannerEnv, error) { return planner.islandEnv(fromIsland) }
+	} else {
+		// leave and enter the islands of the end points, grown to reach the outer
+		// environment, on the way around the others
+		outer, err := planner.outerEnv()
+		if err != nil {
+			return nil, err
+		}
+		polygons := NewPolygons()
+		for _, area := range outer.env {
+			polygons.Push(area.pgx.Polygons()...)
+		}
+		for _, i := range []int{fromIsland, toIsland} {
+			if i < 0 {
+				continue
+			}
+			grown, err := Offset(planner.Islands[i].Polygons(), planner.OuterMargin)
+			if err != nil {
+				return nil, err
+			}
+			polygons.Push(grown...)
+		}
+		union, err := UnionEx(polygons)
+		if err != nil {
+			return nil, err
+		}
+		region = newMotionRegion(union)
+		envOf = func() (*motionPlannerEnv, error) { return outer, nil }
+	}
+
+	if region.visible(from, to) {
+		return straight, nil
+	}
+	env, err := envOf()
+	if err != nil {
+		return nil, err
+	}
+	points := env.shortestPath(from, to, region)
+	if points == nil {
+		return straight, nil
+	}
+	path := NewPolyline()
+	path.MP.Points.Push(points...)
+	return path, nil
+}
+
+// shortestPath will run Dijkstra's algorithm from one point to another, connecting
+// them to the graph where they see it inside region. It returns nil without a path
+func (mpe *motionPlannerEnv) shortestPath(from *Point, to *Point, region motionRegion) Points {
+	n := len(mpe.nodes)
+	source, target := n, n+1
+
+	fromEdges := make([]int, 0)
+	toVisible := make([]bool, n)
+	for i, node := range mpe.nodes {
+		if region.visible(from, node) {
+			fromEdges = append(fromEdges, i)
+		}
+		toVisible[i] = region.visible(node, to)
+	}
+	if len(fromEdges) == 0 {
+		return nil
+	}
+	adjacent := mpe.graph()
+
+	point := func(i int) *Point {
+		switch i {
+		case source:
+			return from
+		case target:
+			return to
+		}
+		return mpe.nodes[i]
+	}
+
+	dist := make([]float64, n+2)
+	prev := make([]int, n+2)
+	done := make([]bool, n+2)
+	for i := range dist {
+		dist[i] = math.Inf(1)
+		prev[i] = -1
+	}
+	dist[source] = 0
+
+	for {
+		u := -1
+		for i := range dist {
+			if !done[i] && !math.IsInf(dist[i], 1) && (u == -1 || dist[i] < dist[u]) {
+				u = i
+			}
+		}
+		if u == -1 {
+			return nil
+		}
+		if u == target {
+			break
+		}
+		done[u] = true
+
+		neighbours := fromEdges
+		if u != source {
+			neighbours = adjacent[u]
+			if toVisible[u] {
+				neighbours = append(neighbours[:len(neighbours):len(neighbours)], target)
+			}
+		}
+		for _, v := range neighbours {
+			if d := dist[u] + point(u).DistanceTo(point(v)); d < dist[v] {
+				dist[v] = d
+				prev[v] = u
+			}
+		}
+	}
+
+	points := NewPoints()
+	for i := target; i != -1; i = prev[i] {
+		points.PushFront(point(i))
+	}
+	return points
+}
+
+// visible will tell if the segment from a to b stays inside one of the areas,
+// touching their boundaries counts as inside
+func (region motionRegion) visible(a *Point, b *Point) bool {
+	bb := NewBoundingBox(a, b)
+	bb.Offset(ScaledEpsilon)
+	for _, area := range region {
+		if area.bb.Overlaps(bb) && area.segmentInside(a, b, bb) {
+			return true
+		}
+	}
+	return false
+}
+
+// segmentInside will tell if the segment from a to b, within bb, never leaves the
+// area. The segment must not cross a ring, and between the vertices it touches it
+// must be inside
+func (area *motionArea) segmentInside(a *Point, b *Point, bb *BoundingBox) bool {
+	length := a.DistanceTo(b)
+	if length < ScaledEpsilon {
+		return area.covers(a)
+	}
+	tolerance := ScaledEpsilon * length
+	side := func(p *Point, p1 *Point, p2 *Point) int {
+		ccw := p.CCW(p1, p2)
+		if math.Abs(ccw) <= tolerance {
+			return 0
+		}
+		if ccw > 0 {
+			return 1
+		}
+		return -1
+	}
+
+	cuts := []float64{0, 1}
+	for _, ring := range area.rings {
+		if !ring.bb.Overlaps(bb) {
+			continue
+		}
+		for i, edge := range ring.edges {
+			if ring.boxes[i].Overlaps(bb) &&
+				side(edge.A, a, b)*side(edge.B, a, b) < 0 && side(a, edge.A, edge.B)*side(b, edge.A, edge.B) < 0 {
+				return false
+			}
+		}
+		// vertices on the segment are where it may slip out without crossing an edge
+		for _, p := range ring.points {
+			if !bb.ContainsPoint(p) || side(p, a, b) != 0 {
+				continue
+			}
+			t := ((p.X-a.X)*(b.X-a.X) + (p.Y-a.Y)*(b.Y-a.Y)) / (length * length)
+			if t > 0 && t < 1 {
+				cuts = append(cuts, t)
+			}
+		}
+	}
+
+	sort.Float64s(cuts)
+	for i := 1; i < len(cuts); i++ {
+		t := (cuts[i-1] + cuts[i]) / 2
+		if !area.covers(NewPoint(a.X+t*(b.X-a.X), a.Y+t*(b.Y-a.Y))) {
+			return false
+		}
+	}
+	return true
+}
+
+// covers will tell if point is inside the area or on its boundary
+func (area *motionArea) covers(point *Point) bool {
+	bb := NewBoundingBox(point)
+	bb.Offset(ScaledEpsilon)
+	if !area.bb.Overlaps(bb) {
+		return false
+	}
+	if area.pgx.ContainsPoint(point) {
+		return true
+	}
+	for _, ring := range area.rings {
+		for i, edge := range ring.edges {
+			if ring.boxes[i].Overlaps(bb) && point.DistanceToLine(edge) <= ScaledEpsilon {
+				return true
+			}
+		}
+	}
+	return false
+}
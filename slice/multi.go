@@ -30,8 +30,8 @@ func (pts Points) Empty() bool {
 }
 
 // Clear will clear all points
-func (pts Points) Clear() {
-	pts = NewPoints()
+func (pts *Points) Clear() {
+	*pts = NewPoints()
 }
 
 // First will get the first entry
@@ -137,8 +137,8 @@ func (pts Polygons) Empty() bool {
 }
 
 // Clear will clear all points
-func (pts Polygons) Clear() {
-	pts = NewPolygons()
+func (pts *Polygons) Clear() {
+	*pts = NewPolygons()
 }
 
 // First will get the first entry
@@ -206,3 +206,433 @@ func (pts *Polygons) PushFront(polys ...*Polygon) {
 func (pts *Polygons) EraseAt(index int) {
 	*pts = append((*pts)[:index], (*pts)[index+1:]...)
 }
+
+// PolygonExs is a collection of PolygonEx
+type PolygonExs []*PolygonEx
+
+// NewPolygonExs will construct PolygonExs
+func NewPolygonExs() PolygonExs {
+	polys := make(PolygonExs, 0)
+	return polys
+}
+
+// GetCopy will return a copy of all PolygonExs
+func (pts PolygonExs) GetCopy() PolygonExs {
+	copied := make(PolygonExs, len(pts))
+	copy(copied, pts)
+	return copied
+}
+
+// Empty will determine if the PolygonExs are empty
+func (pts PolygonExs) Empty() bool {
+	return len(pts) == 0
+}
+
+// First will get the first entry
+func (pts PolygonExs) First() *PolygonEx {
+	return pts[0]
+}
+
+// Last will get the last entry
+func (pts PolygonExs) Last() *PolygonEx {
+	return pts[len(pts)-1]
+}
+
+// Push will append a PolygonEx
+func (pts *PolygonExs) Push(poly ...*PolygonEx) {
+	*pts = append(*pts, poly...)
+}
+
+// Polygons will return the contours and holes of every PolygonEx
+func (pts PolygonExs) Polygons() Polygons {
+	polys := NewPolygons()
+	for _, pgx := range pts {
+		polys.Push(pgx.Polygons()...)
+	}
+	return polys
+}
+
+// Area will return the area of every PolygonEx added up
+func (pts PolygonExs) Area() float64 {
+	var area float64 = 0
+	for _, pgx := range pts {
+		area += pgx.Area()
+	}
+	return area
+}
+
+// EraseAt will delete an item at index
+func (pts *PolygonExs) EraseAt(index int) {
+	*pts = append((*pts)[:index], (*pts)[index+1:]...)
+}
+
+// Polylines is a collection of Polylines
+type Polylines []*Polyline
+
+// NewPolylines will construct Polylines
+func NewPolylines() Polylines {
+	plines := make(Polylines, 0)
+	return plines
+}
+
+// GetCopy will return a copy of all Polylines
+func (pls Polylines) GetCopy() Polylines {
+	copied := make(Polylines, len(pls))
+	copy(copied, pls)
+	return copied
+}
+
+// Empty will determine if the Polylines are empty
+func (pls Polylines) Empty() bool {
+	return len(pls) == 0
+}
+
+// First will get the first entry
+func (pls Polylines) First() *Polyline {
+	return pls[0]
+}
+
+// Last will get the last entry
+func (pls Polylines) Last() *Polyline {
+	return pls[len(pls)-1]
+}
+
+// Push will append a Polyline
+func (pls *Polylines) Push(pline ...*Polyline) {
+	*pls = append(*pls, pline...)
+}
+
+// EraseAt will delete an item at index
+func (pls *Polylines) EraseAt(index int) {
+	*pls = append((*pls)[:index], (*pls)[index+1:]...)
+}
+
+// LeftmostPoint will find the leftmost point of all Polylines
+func (pls Polylines) LeftmostPoint() *Point {
+	var leftP *Point
+	for _, pline := range pls {
+		if pline.MP.Points.Empty() {
+			continue
+		}
+		if p := pline.LeftmostPoint(); leftP == nil || p.X < leftP.X {
+			leftP = p
+		}
+	}
+	return leftP
+}
+
+// ChainedPathFrom will order the Polylines so each starts near where the previous
+// one ended, starting near startNear. Polylines are reversed when their end is
+// closer, unless noReverse is set
+func (pls Polylines) ChainedPathFrom(startNear *Point, noReverse bool) Polylines {
+	return ChainPolylines(pls, startNear, noReverse)
+}
+
+// TravelLength will return the length of the travels from start through the
+// Polylines in order
+func (pls Polylines) TravelLength(start *Point) float64 {
+	length := 0.0
+	last := start
+	for _, pline := range pls {
+		length += last.DistanceTo(pline.MP.Points.First())
+		last = pline.MP.Points.Last()
+	}
+	return length
+}
+
+// Layers is a collection of Layers ordered from the bottom up
+type Layers []*Layer
+
+// NewLayers will construct Layers
+func NewLayers() Layers {
+	layers := make(Layers, 0)
+	return layers
+}
+
+// Empty will determine if the Layers are empty
+func (ls Layers) Empty() bool {
+	return len(ls) == 0
+}
+
+// First will get the first entry
+func (ls Layers) First() *Layer {
+	return ls[0]
+}
+
+// Last will get the last entry
+func (ls Layers) Last() *Layer {
+	return ls[len(ls)-1]
+}
+
+// Push will append a Layer
+func (ls *Layers) Push(layer ...*Layer) {
+	*ls = append(*ls, layer...)
+}
+
+// LayerRegions is a collection of LayerRegions
+type LayerRegions []*LayerRegion
+
+// NewLayerRegions will construct LayerRegions
+func NewLayerRegions() LayerRegions {
+	regions := make(LayerRegions, 0)
+	return regions
+}
+
+// Empty will determine if the LayerRegions are empty
+func (lrs LayerRegions) Empty() bool {
+	return len(lrs) == 0
+}
+
+// Push will append a LayerRegion
+func (lrs *LayerRegions) Push(region ...*LayerRegion) {
+	*lrs = append(*lrs, region...)
+}
+
+// LayerRanges is a collection of LayerRanges
+type LayerRanges []*LayerRange
+
+// NewLayerRanges will construct LayerRanges
+func NewLayerRanges() LayerRanges {
+	ranges := make(LayerRanges, 0)
+	return ranges
+}
+
+// Empty will determine if the LayerRanges are empty
+func (lrs LayerRanges) Empty() bool {
+	return len(lrs) == 0
+}
+
+// Push will append a LayerRange
+func (lrs *LayerRanges) Push(lr ...*LayerRange) {
+	*lrs = append(*lrs, lr...)
+}
+
+// ModifierVolumes is a collection of ModifierVolumes
+type ModifierVolumes []*ModifierVolume
+
+// NewModifierVolumes will construct ModifierVolumes
+func NewModifierVolumes() ModifierVolumes {
+	volumes := make(ModifierVolumes, 0)
+	return volumes
+}
+
+// Empty will determine if the ModifierVolumes are empty
+func (mvs ModifierVolumes) Empty() bool {
+	return len(mvs) == 0
+}
+
+// Push will append a ModifierVolume
+func (mvs *ModifierVolumes) Push(volume ...*ModifierVolume) {
+	*mvs = append(*mvs, volume...)
+}
+
+// ExtrusionPaths is a collection of ExtrusionPaths
+type ExtrusionPaths []*ExtrusionPath
+
+// NewExtrusionPaths will construct ExtrusionPaths
+func NewExtrusionPaths() ExtrusionPaths {
+	paths := make(ExtrusionPaths, 0)
+	return paths
+}
+
+// Clone will deep copy every ExtrusionPath
+func (eps ExtrusionPaths) Clone() ExtrusionPaths {
+	copied := make(ExtrusionPaths, 0, len(eps))
+	for _, path := range eps {
+		copied = append(copied, path.Clone().(*ExtrusionPath))
+	}
+	return copied
+}
+
+// Empty will determine if the ExtrusionPaths are empty
+func (eps ExtrusionPaths) Empty() bool {
+	return len(eps) == 0
+}
+
+// First will get the first entry
+func (eps ExtrusionPaths) First() *ExtrusionPath {
+	return eps[0]
+}
+
+// Last will get the last entry
+func (eps ExtrusionPaths) Last() *ExtrusionPath {
+	return eps[len(eps)-1]
+}
+
+// Push will append an ExtrusionPath
+func (eps *ExtrusionPaths) Push(path ...*ExtrusionPath) {
+	*eps = append(*eps, path...)
+}
+
+// EraseAt will delete an item at index
+func (eps *ExtrusionPaths) EraseAt(index int) {
+	*eps = append((*eps)[:index], (*eps)[index+1:]...)
+}
+
+// Reverse will reverse the order of the paths and the direction of each one
+func (eps ExtrusionPaths) Reverse() {
+	for _, path := range eps {
+		path.Reverse()
+	}
+	for i, j := 0, len(eps)-1; i < j; i, j = i+1, j-1 {
+		eps[i], eps[j] = eps[j], eps[i]
+	}
+}
+
+// Length will return the length of all paths
+func (eps ExtrusionPaths) Length() float64 {
+	var length float64 = 0
+	for _, path := range eps {
+		length += path.Length()
+	}
+	return length
+}
+
+// AsPolyline will join the paths into one polyline, skipping points shared by consecutive paths
+func (eps ExtrusionPaths) AsPolyline() *Polyline {
+	pline := NewPolyline()
+	for _, path := range eps {
+		points := path.Polyline.MP.Points
+		if !pline.MP.Points.Empty() && !points.Empty() && pline.MP.Points.Last().CoincidesWith(points.First()) {
+			points = points[1:]
+		}
+		pline.MP.Points.Push(points...)
+	}
+	return pline
+}
+
+// ExtrusionEntities is a collection of ExtrusionEntity
+type ExtrusionEntities []ExtrusionEntity
+
+// NewExtrusionEntities will construct ExtrusionEntities
+func NewExtrusionEntities() ExtrusionEntities {
+	entities := make(ExtrusionEntities, 0)
+	return entities
+}
+
+// GetCopy will return a copy of all ExtrusionEntities
+func (ees ExtrusionEntities) GetCopy() ExtrusionEntities {
+	copied := make(ExtrusionEntities, len(ees))
+	copy(copied, ees)
+	return copied
+}
+
+// Empty will determine if the ExtrusionEntities are empty
+func (ees ExtrusionEntities) Empty() bool {
+	return len(ees) == 0
+}
+
+// First will get the first entry
+func (ees ExtrusionEntities) First() ExtrusionEntity {
+	return ees[0]
+}
+
+// Last will get the last entry
+func (ees ExtrusionEntities) Last() ExtrusionEntity {
+	return ees[len(ees)-1]
+}
+
+// Push will append an ExtrusionEntity
+func (ees *ExtrusionEntities) Push(entity ...ExtrusionEntity) {
+	*ees = append(*ees, entity...)
+}
+
+// EraseAt will delete an item at index
+func (ees *ExtrusionEntities) EraseAt(index int) {
+	*ees = append((*ees)[:index], (*ees)[index+1:]...)
+}
+
+// TravelLength will return the length of the travels from start through the
+// ExtrusionEntities in order
+func (ees ExtrusionEntities) TravelLength(start *Point) float64 {
+	length := 0.0
+	last := start
+	for _, entity := range ees {
+		length += last.DistanceTo(entity.FirstPoint())
+		last = entity.LastPoint()
+	}
+	return length
+}
+
+// Surfaces is a collection of Surfaces
+type Surfaces []*Surface
+
+// NewSurfaces will construct Surfaces
+func NewSurfaces() Surfaces {
+	surfaces := make(Surfaces, 0)
+	return surfaces
+}
+
+// GetCopy will deep copy every Surface
+func (ss Surfaces) GetCopy() Surfaces {
+	copied := make(Surfaces, 0, len(ss))
+	for _, surface := range ss {
+		copied = append(copied, surface.GetCopy())
+	}
+	return copied
+}
+
+// Empty will determine if the Surfaces are empty
+func (ss Surfaces) Empty() bool {
+	return len(ss) == 0
+}
+
+// First will get the first entry
+func (ss Surfaces) First() *Surface {
+	return ss[0]
+}
+
+// Last will get the last entry
+func (ss Surfaces) Last() *Surface {
+	return ss[len(ss)-1]
+}
+
+// Push will append a Surface
+func (ss *Surfaces) Push(surface ...*Surface) {
+	*ss = append(*ss, surface...)
+}
+
+// EraseAt will delete an item at index
+func (ss *Surfaces) EraseAt(index int) {
+	*ss = append((*ss)[:index], (*ss)[index+1:]...)
+}
+
+// Append will add a Surface of type st for every PolygonEx
+func (ss *Surfaces) Append(pgxs PolygonExs, st SurfaceType) {
+	for _, pgx := range pgxs {
+		ss.Push(NewSurface(pgx, st))
+	}
+}
+
+// Filter will return the surfaces of any of the types
+func (ss Surfaces) Filter(types ...SurfaceType) Surfaces {
+	filtered := NewSurfaces()
+	for _, surface := range ss {
+		for _, st := range types {
+			if surface.SurfaceType == st {
+				filtered.Push(surface)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// PolygonExs will return the PolygonEx of every Surface
+func (ss Surfaces) PolygonExs() PolygonExs {
+	pgxs := NewPolygonExs()
+	for _, surface := range ss {
+		pgxs.Push(surface.PolygonEx)
+	}
+	return pgxs
+}
+
+// Polygons will return the contours and holes of every Surface
+func (ss Surfaces) Polygons() Polygons {
+	return ss.PolygonExs().Polygons()
+}
+
+// Area will return the area of every Surface added up
+func (ss Surfaces) Area() float64 {
+	return ss.PolygonExs().Area()
+}
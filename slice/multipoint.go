@@ -144,19 +144,47 @@ func (mp *MultiPoint) RemoveDuplicatePoints() bool {
 	}
 
 	if j+1 < len(mp.Points) {
-		mp.Points = mp.Points[:j]
+		mp.Points = mp.Points[:j+1]
 		return true
 	}
 	return false
 }
 
-// Intersection will find intersections
-func (mp *MultiPoint) Intersection(line *Line, intersection *Point) bool {
+// Intersection will find the first place a line crosses the multipoint
+func (mp *MultiPoint) Intersection(line *Line) (*Point, error) {
 	lines := mp.Lines.GetLines()
-	for _, line := range lines {
-		if line.Intersection(line, intersection) {
-			return true
+	for _, segment := range lines {
+		if point, err := segment.Intersection(line); err == nil {
+			return point, nil
 		}
 	}
-	return false
+	return nil, ErrNoIntersection
+}
+
+// DouglasPeucker will drop points that stray less than tolerance from the line
+// between the points kept around them. The first and last points are always kept
+func DouglasPeucker(points Points, tolerance float64) Points {
+	if len(points) < 3 {
+		return points.GetCopy()
+	}
+	results := NewPoints()
+	dmax := 0.0
+	index := 0
+	full := NewLine(points.First(), points.Last())
+	for i, point := range points[1:] {
+		// we use shortest distance, not perpendicular distance
+		if d := point.DistanceToLine(full); d > dmax {
+			index = i + 1
+			dmax = d
+		}
+	}
+
+	if dmax >= tolerance {
+		front := DouglasPeucker(points[:index+1], tolerance)
+		results.Push(front[:len(front)-1]...)
+		results.Push(DouglasPeucker(points[index:], tolerance)...)
+	} else {
+		results.Push(points.First(), points.Last())
+	}
+	return results
 }
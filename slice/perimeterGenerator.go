@@ -0,0 +1,409 @@
+package slice
+
+// This follows Slic3r's PerimeterGenerator.cpp. Thin walls and overhang detection
+// are not ported yet, and gaps are kept as regions instead of medial axis paths
+
+// InsetOverlapTolerance is how much adjacent perimeters may overlap before the
+// area between them is dropped
+const InsetOverlapTolerance float64 = 0.2
+
+// PerimeterGeneratorLoop is a perimeter loop and the loops nested in it
+type PerimeterGeneratorLoop struct {
+	Polygon   *Polygon
+	Depth     int // 0 is the external perimeter
+	IsContour bool
+	Children  PerimeterGeneratorLoops
+}
+
+// NewPerimeterGeneratorLoop will construct a loop. CCW polygons are contours, CW ones holes
+func NewPerimeterGeneratorLoop(polygon *Polygon, depth int) *PerimeterGeneratorLoop {
+	loop := new(PerimeterGeneratorLoop)
+	loop.Polygon = polygon
+	loop.Depth = depth
+	loop.IsContour = polygon.IsCounterClockwise()
+	loop.Children = make(PerimeterGeneratorLoops, 0)
+	return loop
+}
+
+// IsExternal will tell if the loop is on the outside of the island
+func (loop *PerimeterGeneratorLoop) IsExternal() bool {
+	return loop.Depth == 0
+}
+
+// IsInternalContour will tell if the loop is a contour with no contours inside of it
+func (loop *PerimeterGeneratorLoop) IsInternalContour() bool {
+	if !loop.IsContour {
+		return false
+	}
+	for _, child := range loop.Children {
+		if child.IsContour {
+			return false
+		}
+	}
+	return true
+}
+
+// PerimeterGeneratorLoops is a collection of PerimeterGeneratorLoop
+type PerimeterGeneratorLoops []*PerimeterGeneratorLoop
+
+// PerimeterGenerator turns the slices of a layer into perimeter loops. Widths and
+// spacings are scaled
+type PerimeterGenerator struct {
+	Slices  PolygonExs
+	LayerID int
+
+	Perimeters           int
+	ExtPerimeterWidth    float64
+	ExtPerimeterSpacing  float64
+	ExtPerimeterSpacing2 float64 // spacing between the external perimeter and the next one
+	PerimeterWidth       float64
+	PerimeterSpacing     float64
+	SolidInfillSpacing   float64
+	InfillOverlap        float64
+
+	FillGaps                bool
+	ExternalPerimetersFirst bool
+
+	// flows of the loops, used for the extrusions when set
+	PerimeterFlow    *Flow
+	ExtPerimeterFlow *Flow
+
+	Loops        []PerimeterGeneratorLoops  // ordered loops of every island
+	Extrusions   *ExtrusionEntityCollection // a collection of loops for every island
+	GapFill      PolygonExs                 // gaps too narrow for another loop
+	FillSurfaces PolygonExs                 // what is left over for infill
+}
+
+// NewPerimeterGenerator will construct a PerimeterGenerator where every perimeter
+// and the infill share the same width and spacing
+func NewPerimeterGenerator(slices PolygonExs, layerID int, perimeters int, width float64, spacing float64) *PerimeterGenerator {
+	pg := new(PerimeterGenerator)
+	pg.Slices = slices
+	pg.LayerID = layerID
+	pg.Perimeters = perimeters
+	pg.ExtPerimeterWidth = width
+	pg.ExtPerimeterSpacing = spacing
+	pg.ExtPerimeterSpacing2 = spacing
+	pg.PerimeterWidth = width
+	pg.PerimeterSpacing = spacing
+	pg.SolidInfillSpacing = spacing
+	pg.FillGaps = true
+	return pg
+}
+
+// NewPerimeterGeneratorFromFlows will construct a PerimeterGenerator with the widths
+// and spacings of the flows
+func NewPerimeterGeneratorFromFlows(slices PolygonExs, layerID int, perimeters int, perimeterFlow *Flow, extPerimeterFlow *Flow, solidInfillFlow *Flow) *PerimeterGenerator {
+	pg := NewPerimeterGenerator(slices, layerID, perimeters, perimeterFlow.ScaledWidth(), perimeterFlow.ScaledSpacing())
+	pg.ExtPerimeterWidth = extPerimeterFlow.ScaledWidth()
+	pg.ExtPerimeterSpacing = extPerimeterFlow.ScaledSpacing()
+	pg.ExtPerimeterSpacing2 = extPerimeterFlow.ScaledSpacingTo(perimeterFlow)
+	pg.SolidInfillSpacing = solidInfillFlow.ScaledSpacing()
+	pg.PerimeterFlow = perimeterFlow
+	pg.ExtPerimeterFlow = extPerimeterFlow
+	return pg
+}
+
+// Process will generate the loops, gaps and fill surfaces of every island
+func (pg *PerimeterGenerator) Process() error {
+	pg.Loops = make([]PerimeterGeneratorLoops, 0)
+	pg.Extrusions = NewExtrusionEntityCollection()
+	pg.GapFill = NewPolygonExs()
+	pg.FillSurfaces = NewPolygonExs()
+
+	// we need to process each island separately because we might have different
+	// extra perimeters for each one
+	for _, island := range pg.Slices {
+		if err := pg.processIsland(island); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (pg *PerimeterGenerator) processIsland(island *PolygonEx) error {
+	loopNumber := pg.Perimeters - 1 // 0-indexed loops
+	simplified, err := island.SimplifyP(ScaledResolution)
+	if err != nil {
+		return err
+	}
+	last, err := Union(simplified)
+	if err != nil {
+		return err
+	}
+	gaps := NewPolygons()
+
+	if loopNumber >= 0 {
+		contours := make([]PerimeterGeneratorLoops, loopNumber+1) // depth => loops
+		holes := make([]PerimeterGeneratorLoops, loopNumber+1)    // depth => loops
+
+		// we loop one time more than needed in order to find gaps after the last perimeter was applied
+		for i := 0; i <= loopNumber+1; i++ { // outer loop is 0
+			var offsets Polygons
+			if i == 0 {
+				offsets, err = Offset(last, -pg.ExtPerimeterWidth/2)
+				if err != nil {
+					return err
+				}
+			} else {
+				distance := pg.PerimeterSpacing
+				if i == 1 {
+					distance = pg.ExtPerimeterSpacing2
+				}
+				if offsets, err = Offset(last, -distance); err != nil {
+					return err
+				}
+
+				// look for gaps
+				if pg.FillGaps {
+					found, err := pg.findGaps(last, offsets, distance)
+					if err != nil {
+						return err
+					}
+					gaps.Push(found...)
+				}
+			}
+
+			if len(offsets) == 0 {
+				break
+			}
+			if i > loopNumber {
+				break // we were only looking for gaps this time
+			}
+
+			last = offsets
+			for _, polygon := range offsets {
+				loop := NewPerimeterGeneratorLoop(polygon, i)
+				if loop.IsContour {
+					contours[i] = append(contours[i], loop)
+				} else {
+					holes[i] = append(holes[i], loop)
+				}
+			}
+		}
+
+		nestPerimeterLoops(contours, holes)
+
+		// at this point, all loops should be in contours[0]
+		entities := traversePerimeterLoops(contours[0])
+		if pg.ExternalPerimetersFirst {
+			for i, j := 0, len(entities)-1; i < j; i, j = i+1, j-1 {
+				entities[i], entities[j] = entities[j], entities[i]
+			}
+		}
+		if len(entities) > 0 {
+			pg.Loops = append(pg.Loops, entities)
+			pg.Extrusions.Append(pg.extrusionLoops(entities))
+		}
+	}
+
+	// keep the gaps that are wide enough to fill but too narrow for another loop
+	if len(gaps) > 0 {
+		min := 0.2 * pg.PerimeterWidth * (1 - InsetOverlapTolerance)
+		max := 2 * pg.PerimeterSpacing
+		wide, err := Offset2(gaps, -min/2, min/2)
+		if err != nil {
+			return err
+		}
+		tooWide, err := Offset2(gaps, -max/2, max/2)
+		if err != nil {
+			return err
+		}
+		gapsEx, err := DiffEx(wide, tooWide)
+		if err != nil {
+			return err
+		}
+		pg.GapFill.Push(gapsEx...)
+
+		// remove areas that are filled by gap fill from the infill area
+		if last, err = Diff(last, gapsEx.Polygons()); err != nil {
+			return err
+		}
+	}
+
+	return pg.addFillSurfaces(last, loopNumber)
+}
+
+// extrusionLoops will turn the ordered loops of an island into extrusion loops
+func (pg *PerimeterGenerator) extrusionLoops(loops PerimeterGeneratorLoops) *ExtrusionEntityCollection {
+	collection := NewExtrusionEntityCollection()
+	collection.NoSort = true // the loops are already in print order
+	for _, loop := range loops {
+		role, flow, width := ErPerimeter, pg.PerimeterFlow, pg.PerimeterWidth
+		if loop.IsExternal() {
+			role, flow, width = ErExternalPerimeter, pg.ExtPerimeterFlow, pg.ExtPerimeterWidth
+		}
+		loopRole := ElrDefault
+		if loop.IsInternalContour() {
+			loopRole = ElrContourInternalPerimeter
+		}
+
+		var mm3PerMM, height float64
+		if flow != nil {
+			mm3PerMM, height = flow.MM3PerMM(), Scale(flow.Height)
+		}
+		collection.Append(NewExtrusionLoopFromPolygon(loop.Polygon, role, loopRole, mm3PerMM, width, height))
+	}
+	return collection
+}
+
+// findGaps will find what lies between the last loop and the next one. Not using a
+// safety offset here would detect very narrow gaps that gap fill won't be able to
+// fill but we'd still remove from the infill area
+func (pg *PerimeterGenerator) findGaps(last Polygons, offsets Polygons, distance float64) (Polygons, error) {
+	outer, err := Offset(last, -0.5*distance)
+	if err != nil {
+		return nil, err
+	}
+	inner, err := Offset(offsets, 0.5*distance+10) // safety offset
+	if err != nil {
+		return nil, err
+	}
+	return Diff(outer, inner)
+}
+
+// addFillSurfaces will offset the innermost loops by half the perimeter spacing to
+// reach the actual infill boundary, then back and forth by half the infill spacing
+// to only keep the non-collapsing regions
+func (pg *PerimeterGenerator) addFillSurfaces(last Polygons, loopNumber int) error {
+	var inset float64 = 0
+	if loopNumber == 0 {
+		// one loop
+		inset += pg.ExtPerimeterSpacing / 2
+	} else if loopNumber > 0 {
+		// two or more loops
+		inset += pg.PerimeterSpacing / 2
+	}
+	// only apply infill overlap if we actually have one perimeter
+	if inset > 0 {
+		inset -= pg.InfillOverlap
+	}
+
+	expp, err := UnionEx(last)
+	if err != nil {
+		return err
+	}
+	// simplify infill contours according to resolution
+	pp := NewPolygons()
+	for _, ex := range expp {
+		simplified, err := ex.SimplifyP(ScaledResolution)
+		if err != nil {
+			return err
+		}
+		pp.Push(simplified...)
+	}
+
+	// collapse too narrow infill areas
+	minPerimeterInfillSpacing := pg.SolidInfillSpacing * (1 - InsetOverlapTolerance)
+	expp, err = Offset2Ex(pp, -inset-minPerimeterInfillSpacing/2, minPerimeterInfillSpacing/2)
+	if err != nil {
+		return err
+	}
+	pg.FillSurfaces.Push(expp...)
+	return nil
+}
+
+// nestPerimeterLoops will move every loop into the loop that contains it so that all
+// of them end up under contours[0]
+func nestPerimeterLoops(contours []PerimeterGeneratorLoops, holes []PerimeterGeneratorLoops) {
+	loopNumber := len(contours) - 1
+
+	// nest loops: holes first
+	for d := 0; d <= loopNumber; d++ {
+		// loop through all holes having depth == d
+		for i := 0; i < len(holes[d]); i++ {
+			loop := holes[d][i]
+			if parent := findParentLoop(loop, holes[d+1:]); parent != nil {
+				// a hole loop is nested in the next hole loop around it
+				parent.Children = append(parent.Children, loop)
+			} else if parent := findParentLoopReversed(loop, contours); parent != nil {
+				// if no hole contains this hole, find the contour loop that contains it
+				parent.Children = append(parent.Children, loop)
+			} else {
+				continue
+			}
+			holes[d] = append(holes[d][:i], holes[d][i+1:]...)
+			i--
+		}
+	}
+
+	// nest contour loops
+	for d := loopNumber; d >= 1; d-- {
+		// loop through all contours having depth == d
+		for i := 0; i < len(contours[d]); i++ {
+			loop := contours[d][i]
+			if parent := findParentLoopReversed(loop, contours[:d]); parent != nil {
+				parent.Children = append(parent.Children, loop)
+				contours[d] = append(contours[d][:i], contours[d][i+1:]...)
+				i--
+			}
+		}
+	}
+}
+
+// findParentLoop will find the first loop containing loop, searching depths in order
+func findParentLoop(loop *PerimeterGeneratorLoop, depths []PerimeterGeneratorLoops) *PerimeterGeneratorLoop {
+	for _, candidates := range depths {
+		for _, candidate := range candidates {
+			if candidate.Polygon.ContainsPoint(loop.Polygon.MP.Points.First()) {
+				return candidate
+			}
+		}
+	}
+	return nil
+}
+
+// findParentLoopReversed will find the first loop containing loop, searching the deepest first
+func findParentLoopReversed(loop *PerimeterGeneratorLoop, depths []PerimeterGeneratorLoops) *PerimeterGeneratorLoop {
+	for t := len(depths) - 1; t >= 0; t-- {
+		if parent := findParentLoop(loop, depths[t:t+1]); parent != nil {
+			return parent
+		}
+	}
+	return nil
+}
+
+// chainPerimeterLoops will order loops so each starts near where the previous one ended
+func chainPerimeterLoops(loops PerimeterGeneratorLoops) PerimeterGeneratorLoops {
+	if len(loops) == 0 {
+		return loops
+	}
+	remaining := make(PerimeterGeneratorLoops, len(loops))
+	copy(remaining, loops)
+	starts := make(Points, 0, len(loops))
+	for _, loop := range remaining {
+		starts.Push(loop.Polygon.MP.Points.First())
+	}
+
+	ordered := make(PerimeterGeneratorLoops, 0, len(loops))
+	last := starts.First()
+	for len(remaining) > 0 {
+		idx := last.NearestPointIndex(starts)
+		ordered = append(ordered, remaining[idx])
+		// loops end where they start
+		last = starts[idx]
+		remaining = append(remaining[:idx], remaining[idx+1:]...)
+		starts.EraseAt(idx)
+	}
+	return ordered
+}
+
+// traversePerimeterLoops will flatten the loop tree into print order. Contours are
+// printed after the loops inside them and holes before theirs, so the external
+// perimeters come last
+func traversePerimeterLoops(loops PerimeterGeneratorLoops) PerimeterGeneratorLoops {
+	entities := make(PerimeterGeneratorLoops, 0)
+	for _, loop := range chainPerimeterLoops(loops) {
+		children := traversePerimeterLoops(loop.Children)
+		if loop.IsContour {
+			loop.Polygon.MakeCounterClockwise()
+			entities = append(entities, children...)
+			entities = append(entities, loop)
+		} else {
+			loop.Polygon.MakeClockwise()
+			entities = append(entities, loop)
+			entities = append(entities, children...)
+		}
+	}
+	return entities
+}
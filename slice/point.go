@@ -1,6 +1,7 @@
 package slice
 
 import (
+	"errors"
 	"fmt"
 	"math"
 )
@@ -22,7 +23,7 @@ func NewPoint(X float64, Y float64) *Point {
 
 // Describe will return a string with the point
 func (p *Point) Describe() string {
-	return fmt.Sprintf("POINT(X: %f, Y: %f", p.X, p.Y)
+	return fmt.Sprintf("POINT(X: %f, Y: %f)", p.X, p.Y)
 }
 
 // Scale will scale the point by some factor
@@ -44,12 +45,12 @@ func (p *Point) TranslatePoint(point *Point) {
 
 // Rotate will rotate this point
 func (p *Point) Rotate(angle float64) {
-	p = p.Rotated(angle)
+	*p = *p.Rotated(angle)
 }
 
 // RotateWithCenter will rotate this point
 func (p *Point) RotateWithCenter(angle float64, center *Point) {
-	p = p.RotatedWithCenter(angle, center)
+	*p = *p.RotatedWithCenter(angle, center)
 }
 
 // Rotated will return a rotated copy of the current point
@@ -61,7 +62,7 @@ func (p *Point) Rotated(angle float64) *Point {
 	cos := math.Cos(angle)
 
 	x := math.Round(cos*curX - sine*curY)
-	y := math.Round(cos*curX - sine*curY)
+	y := math.Round(cos*curY + sine*curX)
 	return NewPoint(x, y)
 }
 
@@ -136,39 +137,13 @@ func (p *Point) NearestPointIndex(points Points) int {
 	return idx
 }
 
-// NearestWaypointIndex finds the point that is closest to both this point and the supplied one
-func (p *Point) NearestWaypointIndex(points Points, dest *Point) int {
-	var idx int = -1
-	var distance float64 = -1.00
-
-	for index, point := range points {
-		// distance from this to candidate
-		d := math.Pow(p.X-point.X, 2) + math.Pow(p.Y-point.Y, 2)
-
-		//distance from candidate to dest
-		d += math.Pow(p.X-dest.X, 2) + math.Pow(p.Y-dest.Y, 2)
-
-		// if the total distance is greater that current min distance, ignore it
-		if distance != -1 && d > distance {
-			continue
-		}
-		idx = index
-		distance = d
-		if distance < Epsilon {
-			break
-		}
-	}
-	return idx
-}
-
-// NearestPoint will decide if a supplied point is nearest to this point
-func (p *Point) NearestPoint(points Points, dest *Point, point *Point) bool {
-	idx := p.NearestWaypointIndex(points, dest)
+// NearestPoint will return the supplied point nearest to this point
+func (p *Point) NearestPoint(points Points) (*Point, error) {
+	idx := p.NearestPointIndex(points)
 	if idx == -1 {
-		return false
+		return nil, errors.New("No Points Supplied")
 	}
-	point = points[idx]
-	return true
+	return points[idx], nil
 }
 
 // DistanceTo will figure the distance to a supplied point
@@ -201,13 +176,13 @@ func (p *Point) DistanceToLine(line *Line) float64 {
 	return p.DistanceTo(projection)
 }
 
-// DistanceToPerp will figure out the perpindicular distance to line (I think)
+// DistanceToPerp will figure out the perpendicular distance to the line extended forever
 func (p *Point) DistanceToPerp(line *Line) float64 {
 	if line.A.CoincidesWith(line.B) {
 		return p.DistanceTo(line.A)
 	}
 
-	n := (line.B.X-line.A.X)*(line.A.Y-p.Y) - (line.A.X-p.X)*(line.B.X-line.A.Y)
+	n := (line.B.X-line.A.X)*(line.A.Y-p.Y) - (line.A.X-p.X)*(line.B.Y-line.A.Y)
 	return math.Abs(n) / line.Length()
 }
 
@@ -233,7 +208,8 @@ func (p *Point) ProjectionOntoLine(line *Line) *Point {
 		return line.A
 	}
 
-	theta := (line.B.X-p.X)*(line.B.X-line.A.X) + (line.B.Y-p.Y)*(line.B.Y-line.A.Y)/(math.Pow(line.B.X-line.A.X, 2)+math.Pow(line.B.Y-line.A.Y, 2))
+	theta := ((line.B.X-p.X)*(line.B.X-line.A.X) + (line.B.Y-p.Y)*(line.B.Y-line.A.Y)) /
+		(math.Pow(line.B.X-line.A.X, 2) + math.Pow(line.B.Y-line.A.Y, 2))
 	if 0.00 <= theta && theta <= 1.00 {
 		//theta * line.A + (1.0-theta) * line.B
 		return AddPoints(MultPoints(theta, line.A), MultPoints((1.0-theta), line.B))
@@ -0,0 +1,146 @@
+package slice
+
+import "math"
+
+// PointIndex is a grid of points to find the nearest one without scanning all of
+// them. Points are identified by their index in the slice the grid was built from
+type PointIndex struct {
+	points   Points
+	cellSize float64
+	cells    map[[2]int64][]int
+	removed  []bool
+	count    int
+	min, max [2]int64 // cell range holding points
+}
+
+// NewPointIndex will construct a PointIndex of the points, with cells sized to hold
+// about one point each
+func NewPointIndex(points Points) *PointIndex {
+	index := new(PointIndex)
+	index.points = points
+	index.cells = make(map[[2]int64][]int)
+	index.removed = make([]bool, len(points))
+	index.count = len(points)
+	if len(points) == 0 {
+		return index
+	}
+
+	bb := NewBoundingBox(points...)
+	size := bb.Size()
+	index.cellSize = math.Sqrt(size.X * size.Y / float64(len(points)+1))
+	if index.cellSize < math.Max(size.X, size.Y)/float64(len(points)+1) {
+		// the points are on a line
+		index.cellSize = math.Max(size.X, size.Y) / float64(len(points)+1)
+	}
+	if index.cellSize < ScaledEpsilon {
+		index.cellSize = ScaledEpsilon
+	}
+
+	for id, point := range points {
+		cell := index.cell(point)
+		if id == 0 {
+			index.min, index.max = cell, cell
+		}
+		for i := range cell {
+			index.min[i] = minInt64(index.min[i], cell[i])
+			index.max[i] = maxInt64(index.max[i], cell[i])
+		}
+		index.cells[cell] = append(index.cells[cell], id)
+	}
+	return index
+}
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// cell will return the grid cell of point
+func (index *PointIndex) cell(point *Point) [2]int64 {
+	return [2]int64{int64(math.Floor(point.X / index.cellSize)), int64(math.Floor(point.Y / index.cellSize))}
+}
+
+// Len will return how many points are left
+func (index *PointIndex) Len() int {
+	return index.count
+}
+
+// Remove will take the point with id out of the index
+func (index *PointIndex) Remove(id int) {
+	if index.removed[id] {
+		return
+	}
+	index.removed[id] = true
+	index.count--
+
+	cell := index.cell(index.points[id])
+	ids := index.cells[cell]
+	for i, other := range ids {
+		if other == id {
+			index.cells[cell] = append(ids[:i], ids[i+1:]...)
+			break
+		}
+	}
+}
+
+// Nearest will return the id of the point nearest to point, -1 when the index is empty
+func (index *PointIndex) Nearest(point *Point) int {
+	if index.count == 0 {
+		return -1
+	}
+
+	center := index.cell(point)
+	// rings of cells closer than the cells holding points are empty, and the last one
+	// needed reaches every cell holding points
+	first, rings := int64(0), int64(0)
+	for i := range center {
+		first = maxInt64(first, maxInt64(index.min[i]-center[i], center[i]-index.max[i]))
+		rings = maxInt64(rings, maxInt64(center[i]-index.min[i], index.max[i]-center[i]))
+	}
+
+	best, bestDist := -1, math.Inf(1)
+	visit := func(x, y int64) {
+		for _, id := range index.cells[[2]int64{x, y}] {
+			if d := point.DistanceTo(index.points[id]); d < bestDist {
+				best, bestDist = id, d
+			}
+		}
+	}
+	for r := first; r <= rings; r++ {
+		// visit the cells at Chebyshev distance r from the center which may hold points,
+		// the rows at the top and bottom then the columns at the sides between them
+		lowX, highX := maxInt64(center[0]-r, index.min[0]), minInt64(center[0]+r, index.max[0])
+		lowY, highY := maxInt64(center[1]-r+1, index.min[1]), minInt64(center[1]+r-1, index.max[1])
+		for _, y := range []int64{center[1] - r, center[1] + r} {
+			if y >= index.min[1] && y <= index.max[1] {
+				for x := lowX; x <= highX; x++ {
+					visit(x, y)
+				}
+			}
+			if r == 0 {
+				break
+			}
+		}
+		for _, x := range []int64{center[0] - r, center[0] + r} {
+			if x >= index.min[0] && x <= index.max[0] {
+				for y := lowY; y <= highY; y++ {
+					visit(x, y)
+				}
+			}
+		}
+		// points further out are at least r cells away
+		if best >= 0 && bestDist <= float64(r)*index.cellSize {
+			break
+		}
+	}
+	return best
+}
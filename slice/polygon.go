@@ -13,10 +13,19 @@ type Polygon struct {
 // NewPolygon will construct a polygon
 func NewPolygon() *Polygon {
 	pg := new(Polygon)
-	pg.MP = NewMultiPointNoInterface()
+	pg.MP = NewMultiPointFromInterface(pg)
 	return pg
 }
 
+// GetCopy will return a copy of the polygon and its points
+func (pg *Polygon) GetCopy() *Polygon {
+	copied := NewPolygon()
+	for _, point := range pg.MP.Points {
+		copied.MP.Points.Push(NewPoint(point.X, point.Y))
+	}
+	return copied
+}
+
 // Push will push a point into the polygon. Take this out at some point
 func (pg *Polygon) Push(point *Point) {
 	pg.MP.Points.Push(point)
@@ -39,14 +48,22 @@ func (pg *Polygon) GetLastPoint() *Point {
 
 // Lines will retrieve the polygon lines
 func (pg *Polygon) Lines() []*Line {
-	lines := make([]*Line, 0)
-	for i := 0; i < len(pg.MP.Points); i += 2 {
-		lines = append(lines, NewLine(pg.MP.Points.EntryAtIndex(i), pg.MP.Points.EntryAtIndex(i+1)))
+	lines := make([]*Line, 0, len(pg.MP.Points))
+	if len(pg.MP.Points) < 2 {
+		return lines
+	}
+	for i := 1; i < len(pg.MP.Points); i++ {
+		lines = append(lines, NewLine(pg.MP.Points[i-1], pg.MP.Points[i]))
 	}
-	lines = append(lines, NewLine(pg.MP.Points.EntryAtIndex(-1), pg.MP.Points[0]))
+	lines = append(lines, NewLine(pg.MP.Points.Last(), pg.MP.Points.First()))
 	return lines
 }
 
+// GetLines is the same as Lines()
+func (pg *Polygon) GetLines() []*Line {
+	return pg.Lines()
+}
+
 // SplitAtVertex splits the polygon at a vertex and returns a polyline
 func (pg *Polygon) SplitAtVertex(point *Point) *Polyline {
 	for index, p := range pg.MP.Points {
@@ -123,7 +140,7 @@ func (pg *Polygon) MakeCounterClockwise() bool {
 
 // MakeClockwise will make the polygon Clockwise
 func (pg *Polygon) MakeClockwise() bool {
-	if pg.IsClockwise() {
+	if pg.IsCounterClockwise() {
 		pg.MP.Reverse()
 		return true
 	}
@@ -135,6 +152,91 @@ func (pg *Polygon) IsValid() bool {
 	return len(pg.MP.Points) >= 3
 }
 
+// edges will return the edges of the polygon, skipping repeated points
+func (pg *Polygon) edges() []*Line {
+	lines := make([]*Line, 0, len(pg.MP.Points))
+	points := make(Points, 0, len(pg.MP.Points))
+	for _, point := range pg.MP.Points {
+		if len(points) == 0 || !point.CoincidesWith(points.Last()) {
+			points.Push(point)
+		}
+	}
+	for len(points) > 1 && points.Last().CoincidesWith(points.First()) {
+		points.PopBack()
+	}
+	if len(points) < 2 {
+		return lines
+	}
+	for index, point := range points {
+		lines = append(lines, NewLine(point, points.NextEntry(index)))
+	}
+	return lines
+}
+
+// adjacentTouch will determine if an intersection between two edges of a ring is
+// just the vertex they share
+func adjacentTouch(edges []*Line, i, j int, inter *SegmentIntersection) bool {
+	if inter.Overlap != nil {
+		return false
+	}
+	if i > j {
+		i, j = j, i
+	}
+	var shared *Point
+	if j == i+1 {
+		shared = edges[i].B
+	} else if i == 0 && j == len(edges)-1 {
+		shared = edges[0].A
+	} else {
+		return false
+	}
+	return inter.Point.DistanceTo(shared) < Epsilon
+}
+
+// SelfIntersections will find every place the polygon crosses or touches itself.
+// Neighbouring edges meeting at their shared vertex are not reported
+func (pg *Polygon) SelfIntersections() SegmentIntersections {
+	edges := pg.edges()
+	result := make(SegmentIntersections, 0)
+	for _, inter := range FindIntersections(edges) {
+		if !adjacentTouch(edges, inter.LineA, inter.LineB, inter) {
+			result = append(result, inter)
+		}
+	}
+	return result
+}
+
+// IsSimple will determine if the polygon never crosses or touches itself
+func (pg *Polygon) IsSimple() bool {
+	return len(pg.SelfIntersections()) == 0
+}
+
+// MakeSimple will split a self intersecting polygon into simple polygons. Areas
+// are kept using the nonzero fill rule
+func (pg *Polygon) MakeSimple() (Polygons, error) {
+	return SimplifyPolygon(pg, pftNonZero)
+}
+
+// simplifyRing will run Douglas Peucker over the whole closed ring
+func simplifyRing(ring *Polygon, tolerance float64) *Polygon {
+	points := ring.MP.GetPoints()
+	if len(points) == 0 {
+		return NewPolygon()
+	}
+	// repeat first point at the end in order to apply Douglas-Peucker on the whole polygon
+	points.Push(points.First())
+	simplified := NewPolygon()
+	simplified.MP.Points = DouglasPeucker(points, tolerance)
+	simplified.MP.Points.PopBack()
+	return simplified
+}
+
+// Simplify will remove points closer than tolerance to the polygon outline and clean
+// up any self intersections this causes
+func (pg *Polygon) Simplify(tolerance float64) (Polygons, error) {
+	return SimplifyPolygons(Polygons{simplifyRing(pg, tolerance)}, pftNonZero)
+}
+
 // ContainsPoint will check if the polygon contains a point
 func (pg *Polygon) ContainsPoint(point *Point) (result bool) {
 	result = false
@@ -151,8 +253,7 @@ func (pg *Polygon) ContainsPoint(point *Point) (result bool) {
 	return
 }
 
-// RemoveCollinearPoints will remove collinear points
-// TODO Test this since you changed how it works from the original
+// RemoveCollinearPoints will remove points lying on the line between their neighbours
 func (pg *Polygon) RemoveCollinearPoints() {
 	if len(pg.MP.Points) <= 2 {
 		return
@@ -161,16 +262,15 @@ func (pg *Polygon) RemoveCollinearPoints() {
 	points := pg.MP.GetPoints()
 	pg.MP.Points.Clear()
 
-	for i := 0; i < len(points); i++ {
-		p1 := points[i]
-		for j, p2 := range points[i+1:] {
-			p3 := points[j+1]
-			l := NewLine(p1, p3)
-
-			if l.DistanceTo(p2) > ScaledEpsilon {
-				pg.MP.Points.Push(p1)
-				i = j
-			}
+	for index, point := range points {
+		var prev *Point
+		if pg.MP.Points.Empty() {
+			prev = points.PreviousEntry(index)
+		} else {
+			prev = pg.MP.Points.Last()
+		}
+		if NewLine(prev, points.NextEntry(index)).DistanceTo(point) > ScaledEpsilon {
+			pg.MP.Points.Push(point)
 		}
 	}
 }
@@ -193,18 +293,18 @@ func (pg *Polygon) RemoveVerticalCollinearPoints(tolerance float64) {
 	}
 }
 
-// TriangulateConvex will only work on convex polygons
-func (pg *Polygon) TriangulateConvex(polygons []*Polygon) {
-	for i, point := range pg.MP.Points[2:] {
+// TriangulateConvex will fan the polygon into triangles. It will only work on convex polygons
+func (pg *Polygon) TriangulateConvex() Polygons {
+	polygons := NewPolygons()
+	for i := 2; i < len(pg.MP.Points); i++ {
 		poly := NewPolygon()
-		poly.MP.Points.Push(pg.MP.Points.First())
-		poly.MP.Points.Push(pg.MP.Points[(i+2)-1])
-		poly.MP.Points.Push(point)
+		poly.MP.Points.Push(pg.MP.Points.First(), pg.MP.Points[i-1], pg.MP.Points[i])
 
 		if poly.Area() > 0 {
-			polygons = append(polygons, poly)
+			polygons.Push(poly)
 		}
 	}
+	return polygons
 }
 
 // Centroid will calculate the center of mass
@@ -214,8 +314,8 @@ func (pg *Polygon) Centroid() *Point {
 	tmpY := 0.00
 
 	pline := pg.SplitAtFirstPoint()
-	for i, point := range pline.MP.Points {
-		nextP := pg.MP.Points.EntryAtIndex(i + 1)
+	for i, point := range pline.MP.Points[:len(pline.MP.Points)-1] {
+		nextP := pline.MP.Points[i+1]
 		tmpX += (point.X + nextP.X) * (point.X*nextP.Y - nextP.X*point.Y)
 		tmpY += (point.Y + nextP.Y) * (point.X*nextP.Y - nextP.X*point.Y)
 	}
@@ -236,55 +336,37 @@ func (pg *Polygon) Describe() string {
 	return describe
 }
 
-// ConcavePoints will find all concave point in the polygon
+// ConcavePoints will find all points with an internal angle of at least angle, for a
+// counter clockwise polygon
 func (pg *Polygon) ConcavePoints(angle float64) Points {
 	angle = 2.00*math.Pi - angle + Epsilon
 	concavePoints := make(Points, 0)
-
-	// check whether first point forms a concave angle
-	if pg.MP.Points.First().CCWAngle(
-		pg.MP.Points.Last(),
-		pg.MP.Points.NextEntry(0)) <= angle {
-		concavePoints = append(concavePoints, pg.MP.Points.First())
+	if len(pg.MP.Points) < 3 {
+		return concavePoints
 	}
 
-	// Check whether points [1:] form concave angles
-	for index, point := range pg.MP.Points[1:] {
+	for index, point := range pg.MP.Points {
 		if point.CCWAngle(pg.MP.Points.PreviousEntry(index), pg.MP.Points.NextEntry(index)) <= angle {
 			concavePoints = append(concavePoints, point)
 		}
 	}
-
-	// Check whether last point forms a concave angle
-	if pg.MP.Points.Last().CCWAngle(pg.MP.Points.EntryAtIndex(-2), pg.MP.Points.First()) <= angle {
-		concavePoints = append(concavePoints, pg.MP.Points.Last())
-	}
-
 	return concavePoints
 }
 
-// ConvexPoints will return all convex points
+// ConvexPoints will find all points with an internal angle of at most angle, for a
+// counter clockwise polygon
 func (pg *Polygon) ConvexPoints(angle float64) Points {
 	angle = 2.00*math.Pi - angle - Epsilon
 	convexPoints := make(Points, 0)
-
-	// check whether first point forms a convex angle
-	if pg.MP.Points.First().CCWAngle(pg.MP.Points.Last(), pg.MP.Points.NextEntry(0)) >= angle {
-		convexPoints = append(convexPoints, pg.MP.Points.First())
+	if len(pg.MP.Points) < 3 {
+		return convexPoints
 	}
 
-	// Check whether points [1:] form convex angles
-	for index, point := range pg.MP.Points[1:] {
+	for index, point := range pg.MP.Points {
 		if point.CCWAngle(pg.MP.Points.PreviousEntry(index), pg.MP.Points.NextEntry(index)) >= angle {
 			convexPoints = append(convexPoints, point)
 		}
 	}
-
-	// Check whether last point forms a convex angle
-	if pg.MP.Points.Last().CCWAngle(pg.MP.Points.EntryAtIndex(-2), pg.MP.Points.First()) >= angle {
-		convexPoints = append(convexPoints, pg.MP.Points.Last())
-	}
-
 	return convexPoints
 }
 
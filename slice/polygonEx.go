@@ -1,5 +1,7 @@
 package slice
 
+import "fmt"
+
 // PolygonEx is a representation of an external polygon
 type PolygonEx struct {
 	Contour *Polygon
@@ -14,9 +16,19 @@ func NewPolygonEx() *PolygonEx {
 	return pgx
 }
 
+// GetCopy will return a copy of the PolygonEx and its points
+func (pgx *PolygonEx) GetCopy() *PolygonEx {
+	copied := NewPolygonEx()
+	copied.Contour = pgx.Contour.GetCopy()
+	for _, hole := range pgx.Holes {
+		copied.Holes.Push(hole.GetCopy())
+	}
+	return copied
+}
+
 // Points will return all points
 func (pgx *PolygonEx) Points() Points {
-	points := make(Points, 0)
+	points := pgx.Contour.MP.GetPoints()
 
 	for _, poly := range pgx.Holes {
 		for _, point := range poly.MP.Points {
@@ -77,14 +89,135 @@ func (pgx *PolygonEx) Area() float64 {
 	return area
 }
 
+// ValidationReport lists the problems found while validating a PolygonEx
+type ValidationReport struct {
+	Problems      []string
+	Intersections Points
+}
+
+// Valid will tell if no problems were found
+func (vr *ValidationReport) Valid() bool {
+	return len(vr.Problems) == 0
+}
+
+func (vr *ValidationReport) add(format string, args ...interface{}) {
+	vr.Problems = append(vr.Problems, fmt.Sprintf(format, args...))
+}
+
+// ringName will name a ring of the PolygonEx for reports. Ring 0 is the contour
+func ringName(ring int) string {
+	if ring == 0 {
+		return "contour"
+	}
+	return fmt.Sprintf("hole %d", ring-1)
+}
+
+// Validate will check the vertex counts and orientation of every ring and sweep all of
+// the edges at once to find self intersections and rings crossing each other
+func (pgx *PolygonEx) Validate() *ValidationReport {
+	report := new(ValidationReport)
+	rings := pgx.Polygons()
+
+	lines := make([]*Line, 0)
+	owner := make([]int, 0)
+	local := make([]int, 0)
+	ringEdges := make([][]*Line, len(rings))
+	for r, ring := range rings {
+		if !ring.IsValid() {
+			report.add("%s has fewer than 3 points", ringName(r))
+		} else if r == 0 && !ring.IsCounterClockwise() {
+			report.add("contour is not counter clockwise")
+		} else if r > 0 && ring.IsCounterClockwise() {
+			report.add("%s is not clockwise", ringName(r))
+		}
+
+		ringEdges[r] = ring.edges()
+		for index, edge := range ringEdges[r] {
+			lines = append(lines, edge)
+			owner = append(owner, r)
+			local = append(local, index)
+		}
+	}
+
+	crossed := make(map[int]bool)
+	for _, inter := range FindIntersections(lines) {
+		ra, rb := owner[inter.LineA], owner[inter.LineB]
+		if ra == rb {
+			if adjacentTouch(ringEdges[ra], local[inter.LineA], local[inter.LineB], inter) {
+				continue
+			}
+			report.add("%s intersects itself at (%f, %f)", ringName(ra), inter.Point.X, inter.Point.Y)
+		} else {
+			report.add("%s touches %s at (%f, %f)", ringName(ra), ringName(rb), inter.Point.X, inter.Point.Y)
+			crossed[ra], crossed[rb] = true, true
+		}
+		report.Intersections.Push(inter.Point)
+	}
+
+	for r, hole := range pgx.Holes {
+		if crossed[r+1] || len(hole.MP.Points) == 0 {
+			continue
+		}
+		if !pgx.Contour.ContainsPoint(hole.MP.Points.First()) {
+			report.add("%s is outside of the contour", ringName(r+1))
+		}
+	}
+	return report
+}
+
 // IsValid will find if this PolygonEx is valid
 func (pgx *PolygonEx) IsValid() bool {
-	if !pgx.Contour.IsValid() || !pgx.Contour.IsCounterClockwise() {
-		return false
+	return pgx.Validate().Valid()
+}
+
+// IsSimple will determine if none of the rings cross or touch
+func (pgx *PolygonEx) IsSimple() bool {
+	return len(pgx.Validate().Intersections) == 0
+}
+
+// MakeSimple will resolve self intersections and crossing rings. Every ring is
+// made counter clockwise and the holes are cut out of the contour using the
+// nonzero fill rule, so overlapping holes or holes leaving the contour are handled
+func (pgx *PolygonEx) MakeSimple() (PolygonExs, error) {
+	contour := NewPolygon()
+	contour.MP.Points = pgx.Contour.MP.GetPoints()
+	contour.MakeCounterClockwise()
+
+	holes := NewPolygons()
+	for _, ring := range pgx.Holes {
+		hole := NewPolygon()
+		hole.MP.Points = ring.MP.GetPoints()
+		hole.MakeCounterClockwise()
+		holes.Push(hole)
 	}
+	return clipPolygonsEx(ctDifference, Polygons{contour}, holes, pftNonZero)
+}
 
-	for _, poly := range pgx.Holes {
-		if !poly.IsValid() || poly.IsCounterClockwise() {
+// SimplifyP will simplify every ring and return them as simple polygons
+func (pgx *PolygonEx) SimplifyP(tolerance float64) (Polygons, error) {
+	rings := NewPolygons()
+	for _, ring := range pgx.Polygons() {
+		rings.Push(simplifyRing(ring, tolerance))
+	}
+	return SimplifyPolygons(rings, pftNonZero)
+}
+
+// Simplify will simplify every ring and match the holes back up with their contours
+func (pgx *PolygonEx) Simplify(tolerance float64) (PolygonExs, error) {
+	pp, err := pgx.SimplifyP(tolerance)
+	if err != nil {
+		return nil, err
+	}
+	return UnionEx(pp)
+}
+
+// ContainsPoint will check if the point is inside the contour and outside the holes
+func (pgx *PolygonEx) ContainsPoint(point *Point) bool {
+	if !pgx.Contour.ContainsPoint(point) {
+		return false
+	}
+	for _, hole := range pgx.Holes {
+		if hole.ContainsPoint(point) {
 			return false
 		}
 	}
@@ -100,6 +233,6 @@ func (pgx *PolygonEx) Contains(line *Line) bool {
 
 // ContainsPline detirmine if this contains a pline
 func (pgx *PolygonEx) ContainsPline(pline *Polyline) bool {
-	//pline.
-	return false
+	outside, err := DiffPolylines(Polylines{pline}, pgx.Polygons())
+	return err == nil && outside.Empty()
 }
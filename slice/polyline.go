@@ -37,8 +37,8 @@ func (pl *Polyline) LeftmostPoint() *Point {
 // Lines will return all lines
 func (pl *Polyline) Lines() []*Line {
 	lines := make([]*Line, 0)
-	for i := 0; i < len(pl.MP.Points); i += 2 {
-		lines = append(lines, NewLine(pl.MP.Points.EntryAtIndex(i), pl.MP.Points.EntryAtIndex(i+1)))
+	for i := 1; i < len(pl.MP.Points); i++ {
+		lines = append(lines, NewLine(pl.MP.Points[i-1], pl.MP.Points[i]))
 	}
 	return lines
 }
@@ -63,7 +63,7 @@ func (pl *Polyline) ClipEnd(distance float64) {
 		}
 
 		segment := NewLine(lastPoint, pl.MP.Points.Last())
-		pl.MP.Points.Push(segment.GetPointAt(distance))
+		pl.MP.Points.Push(segment.PointAt(distance))
 		distance = 0
 	}
 }
@@ -82,7 +82,7 @@ func (pl *Polyline) ExtendEnd(distance float64) {
 	backPoint := pl.MP.Points.Last()
 	backPoint2 := pl.MP.Points.EntryAtIndex(len(pl.MP.Points) - 2)
 	backline := NewLine(backPoint, backPoint2)
-	pl.MP.Points[len(pl.MP.Points)-1] = backline.GetPointAt(-distance)
+	pl.MP.Points[len(pl.MP.Points)-1] = backline.PointAt(-distance)
 }
 
 // ExtendStart will extend the front of a polyline
@@ -90,7 +90,7 @@ func (pl *Polyline) ExtendStart(distance float64) {
 	frontPoint := pl.MP.Points.First()
 	frontPoint2 := pl.MP.Points.EntryAtIndex(1)
 	frontLine := NewLine(frontPoint, frontPoint2)
-	pl.MP.Points[0] = frontLine.GetPointAt(-distance)
+	pl.MP.Points[0] = frontLine.PointAt(-distance)
 }
 
 // EquallySpacedPoints will return a collection of points picked
@@ -98,37 +98,45 @@ func (pl *Polyline) ExtendStart(distance float64) {
 func (pl *Polyline) EquallySpacedPoints(distance float64) Points {
 	mp := NewMultiPointNoInterface()
 	mp.Points.Push(pl.MP.Points.First())
-	var len float64 = 0
+	var length float64 = 0
 
-	for i := 1; !EqualPoints(pl.MP.Points.EntryAtIndex(i), pl.MP.Points.Last()); i++ {
+	for i := 1; i < len(pl.MP.Points); i++ {
 		currentPoint := pl.MP.Points.EntryAtIndex(i)
 		previousPoint := pl.MP.Points.EntryAtIndex(i - 1)
 		segmentLength := currentPoint.DistanceTo(previousPoint)
 
-		len += segmentLength
-		if len < distance {
+		length += segmentLength
+		if length < distance {
 			continue
 		}
 
-		if len == distance {
+		if length == distance {
 			mp.Points.Push(currentPoint)
-			len = 0
+			length = 0
 			continue
 		}
 
-		var take float64 = segmentLength - (len - distance)
+		var take float64 = segmentLength - (length - distance)
 		segment := NewLine(previousPoint, currentPoint)
-		mp.Points.Push(segment.GetPointAt(take))
+		mp.Points.Push(segment.PointAt(take))
 		i--
-		len = -take
+		length = -take
 	}
 	return mp.Points
 }
 
-// SplitAt will split the polyline at a point
-func (pl *Polyline) SplitAt(point *Point, pline1 *Polyline, pline2 *Polyline) {
+// Simplify will drop points that stray less than tolerance from the polyline
+func (pl *Polyline) Simplify(tolerance float64) {
+	pl.MP.Points = DouglasPeucker(pl.MP.Points, tolerance)
+}
+
+// SplitAt will split the polyline at the point on it nearest to the supplied point.
+// Both halves contain the supplied point
+func (pl *Polyline) SplitAt(point *Point) (*Polyline, *Polyline) {
+	pline1 := NewPolyline()
+	pline2 := NewPolyline()
 	if pl.MP.Points.Empty() {
-		return
+		return pline1, pline2
 	}
 
 	var lineIdx int = 0
@@ -146,7 +154,6 @@ func (pl *Polyline) SplitAt(point *Point, pline1 *Polyline, pline2 *Polyline) {
 	}
 
 	// Create First Half
-	pline1.MP.Points.Clear()
 	for _, line := range lines[:lineIdx+1] {
 		if !line.A.CoincidesWith(p) {
 			pline1.MP.Points.Push(line.A)
@@ -156,11 +163,11 @@ func (pl *Polyline) SplitAt(point *Point, pline1 *Polyline, pline2 *Polyline) {
 	pline1.MP.Points.Push(point)
 
 	// Create Second Half
-	pline2.MP.Points.Clear()
 	pline2.MP.Points.Push(point)
 	for _, line := range lines[lineIdx:] {
 		pline2.MP.Points.Push(line.B)
 	}
+	return pline1, pline2
 }
 
 // IsStraight will Check that each segment's direction is equal to the line connecting
@@ -1,5 +1,117 @@
 package slice
 
+// PolyNode is a single contour in the result of a clip operation. Childs are
+// the contours directly contained by this one
+type PolyNode struct {
+	Contour *Polygon
+	Childs  []*PolyNode
+	Parent  *PolyNode
+	Index   int
+	isOpen  bool
+}
+
+// NewPolyNode will construct an empty PolyNode
+func NewPolyNode() *PolyNode {
+	pn := new(PolyNode)
+	pn.Contour = NewPolygon()
+	pn.Childs = make([]*PolyNode, 0)
+	return pn
+}
+
+// ChildCount will return the number of direct children of the node
+func (pn *PolyNode) ChildCount() int {
+	return len(pn.Childs)
+}
+
+// AddChild will add child as the last child of the node
+func (pn *PolyNode) AddChild(child *PolyNode) {
+	child.Parent = pn
+	child.Index = len(pn.Childs)
+	pn.Childs = append(pn.Childs, child)
+}
+
+// GetNext will return the next node in a depth first walk of the tree
+func (pn *PolyNode) GetNext() *PolyNode {
+	if len(pn.Childs) > 0 {
+		return pn.Childs[0]
+	}
+	return pn.GetNextSiblingUp()
+}
+
+// GetNextSiblingUp will return the next sibling of this node or the closest parent
+func (pn *PolyNode) GetNextSiblingUp() *PolyNode {
+	if pn.Parent == nil {
+		return nil
+	}
+	if pn.Index == len(pn.Parent.Childs)-1 {
+		return pn.Parent.GetNextSiblingUp()
+	}
+	return pn.Parent.Childs[pn.Index+1]
+}
+
+// IsHole will determine if the node is a hole. Nodes alternate between outer
+// contours and holes at each level of the tree
+func (pn *PolyNode) IsHole() bool {
+	result := true
+	node := pn.Parent
+	for node != nil {
+		result = !result
+		node = node.Parent
+	}
+	return result
+}
+
+// IsOpen will determine if the node is an open path
+func (pn *PolyNode) IsOpen() bool {
+	return pn.isOpen
+}
+
+// PolyTree is the root of a clip result. The root itself has no contour
 type PolyTree struct {
-	PolyNodes Polygons
+	PolyNode
+	AllNodes []*PolyNode
+}
+
+// NewPolyTree will construct an empty PolyTree
+func NewPolyTree() *PolyTree {
+	pt := new(PolyTree)
+	pt.Contour = NewPolygon()
+	pt.Childs = make([]*PolyNode, 0)
+	pt.AllNodes = make([]*PolyNode, 0)
+	return pt
+}
+
+// GetFirst will return the first node in the tree or nil if it is empty
+func (pt *PolyTree) GetFirst() *PolyNode {
+	if len(pt.Childs) == 0 {
+		return nil
+	}
+	return pt.Childs[0]
+}
+
+// Total will return the number of nodes in the tree
+func (pt *PolyTree) Total() int {
+	result := len(pt.AllNodes)
+	// with negative offsets, ignore the hidden outer polygon ...
+	if result > 0 && pt.Childs[0] != pt.AllNodes[0] {
+		result--
+	}
+	return result
+}
+
+// Clear will remove every node from the tree
+func (pt *PolyTree) Clear() {
+	pt.AllNodes = make([]*PolyNode, 0)
+	pt.Childs = make([]*PolyNode, 0)
+}
+
+// ClosedPaths will return every closed contour in the tree
+func (pt *PolyTree) ClosedPaths() Polygons {
+	polys := NewPolygons()
+	for _, node := range pt.AllNodes {
+		if !node.isOpen {
+			polys.Push(node.Contour)
+		}
+	}
+	return polys
 }
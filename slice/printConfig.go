@@ -0,0 +1,409 @@
+package slice
+
+import (
+	"fmt"
+	"math"
+)
+
+// PrintConfigDef defines every option of the print configs, with Slic3r's keys and
+// defaults
+var PrintConfigDef = newConfigDef(
+	// object
+	&ConfigOptionDef{Key: "layer_height", Type: CoFloat, Label: "Layer height", Default: "0.3", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "first_layer_height", Type: CoFloatOrPercent, Label: "First layer height", Default: "0.35", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "extrusion_width", Type: CoFloatOrPercent, Label: "Default extrusion width", Default: "0", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "seam_position", Type: CoEnum, Label: "Seam position", Default: "aligned", EnumValues: []string{"aligned", "nearest", "random", "rear", "sharpest"}},
+	&ConfigOptionDef{Key: "raft_layers", Type: CoInt, Label: "Raft layers", Default: "0", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "raft_expansion", Type: CoFloat, Label: "Raft expansion", Default: "1.5", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "raft_contact_distance", Type: CoFloat, Label: "Raft contact Z distance", Default: "0.2", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "support_material", Type: CoBool, Label: "Generate support material", Default: "0"},
+	&ConfigOptionDef{Key: "support_material_threshold", Type: CoInt, Label: "Overhang threshold", Default: "0", Min: 0, Max: 90},
+	&ConfigOptionDef{Key: "support_material_pattern", Type: CoEnum, Label: "Support pattern", Default: "rectilinear", EnumValues: []string{"rectilinear", "rectilinear-grid", "pillars"}},
+	&ConfigOptionDef{Key: "support_material_spacing", Type: CoFloat, Label: "Support pattern spacing", Default: "2.5", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "support_material_angle", Type: CoFloat, Label: "Support pattern angle", Default: "0", Min: 0, Max: 359},
+	&ConfigOptionDef{Key: "support_material_interface_layers", Type: CoInt, Label: "Support interface layers", Default: "3", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "support_material_interface_spacing", Type: CoFloat, Label: "Support interface pattern spacing", Default: "0", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "support_material_contact_distance", Type: CoFloat, Label: "Support contact Z distance", Default: "0.2", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "support_material_extrusion_width", Type: CoFloatOrPercent, Label: "Support material extrusion width", Default: "0", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "support_material_speed", Type: CoFloat, Label: "Support material speed", Default: "60", Min: 0, Max: noMax},
+
+	// region
+	&ConfigOptionDef{Key: "perimeters", Type: CoInt, Label: "Perimeters", Default: "3", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "top_solid_layers", Type: CoInt, Label: "Top solid layers", Default: "3", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "bottom_solid_layers", Type: CoInt, Label: "Bottom solid layers", Default: "3", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "fill_density", Type: CoPercent, Label: "Fill density", Default: "20%", Min: 0, Max: 100},
+	&ConfigOptionDef{Key: "fill_pattern", Type: CoEnum, Label: "Fill pattern", Default: "honeycomb", EnumValues: []string{"rectilinear", "alignedrectilinear", "grid", "line", "cubic", "triangles", "stars", "honeycomb", "3dhoneycomb", "gyroid", "hilbertcurve", "archimedeanchords", "octagramspiral", "concentric"}},
+	&ConfigOptionDef{Key: "external_fill_pattern", Type: CoEnum, Label: "Top/bottom fill pattern", Default: "rectilinear", EnumValues: []string{"rectilinear", "alignedrectilinear", "concentric", "hilbertcurve", "archimedeanchords", "octagramspiral"}},
+	&ConfigOptionDef{Key: "fill_angle", Type: CoFloat, Label: "Fill angle", Default: "45", Min: 0, Max: 359},
+	&ConfigOptionDef{Key: "solid_infill_below_area", Type: CoFloat, Label: "Solid infill threshold area", Default: "70", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "bridge_flow_ratio", Type: CoFloat, Label: "Bridge flow ratio", Default: "1", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "perimeter_extrusion_width", Type: CoFloatOrPercent, Label: "Perimeters extrusion width", Default: "0", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "external_perimeter_extrusion_width", Type: CoFloatOrPercent, Label: "External perimeters extrusion width", Default: "0", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "infill_extrusion_width", Type: CoFloatOrPercent, Label: "Infill extrusion width", Default: "0", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "solid_infill_extrusion_width", Type: CoFloatOrPercent, Label: "Solid infill extrusion width", Default: "0", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "top_infill_extrusion_width", Type: CoFloatOrPercent, Label: "Top solid infill extrusion width", Default: "0", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "perimeter_speed", Type: CoFloat, Label: "Perimeters speed", Default: "60", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "external_perimeter_speed", Type: CoFloatOrPercent, Label: "External perimeters speed", Default: "50%", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "infill_speed", Type: CoFloat, Label: "Infill speed", Default: "80", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "solid_infill_speed", Type: CoFloatOrPercent, Label: "Solid infill speed", Default: "20", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "top_solid_infill_speed", Type: CoFloatOrPercent, Label: "Top solid infill speed", Default: "15", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "bridge_speed", Type: CoFloat, Label: "Bridges speed", Default: "60", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "gap_fill_speed", Type: CoFloat, Label: "Gap fill speed", Default: "20", Min: 0, Max: noMax},
+
+	// printer
+	&ConfigOptionDef{Key: "bed_shape", Type: CoPoints, Label: "Bed shape", Default: "0x0,200x0,200x200,0x200"},
+	&ConfigOptionDef{Key: "z_offset", Type: CoFloat, Label: "Z offset", Default: "0"},
+	&ConfigOptionDef{Key: "gcode_flavor", Type: CoEnum, Label: "G-code flavor", Default: "reprap", EnumValues: []string{"reprap", "repetier", "marlin", "klipper", "smoothie", "teacup", "makerware", "sailfish", "mach3", "machinekit", "no-extrusion"}},
+	&ConfigOptionDef{Key: "gcode_comments", Type: CoBool, Label: "Verbose G-code", Default: "0"},
+	&ConfigOptionDef{Key: "use_relative_e_distances", Type: CoBool, Label: "Use relative E distances", Default: "0"},
+	&ConfigOptionDef{Key: "use_volumetric_e", Type: CoBool, Label: "Use volumetric E", Default: "0"},
+	&ConfigOptionDef{Key: "use_firmware_retraction", Type: CoBool, Label: "Use firmware retraction", Default: "0"},
+	&ConfigOptionDef{Key: "start_gcode", Type: CoString, Label: "Start G-code", Default: "G28 ; home all axes\\nG1 Z5 F5000 ; lift nozzle\\n"},
+	&ConfigOptionDef{Key: "end_gcode", Type: CoString, Label: "End G-code", Default: "M104 S0 ; turn off temperature\\nG28 X0  ; home X axis\\nM84     ; disable motors\\n"},
+	&ConfigOptionDef{Key: "post_process", Type: CoStrings, Label: "Post-processing scripts", Default: ""},
+	&ConfigOptionDef{Key: "travel_speed", Type: CoFloat, Label: "Travel speed", Default: "130", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "first_layer_speed", Type: CoFloatOrPercent, Label: "First layer speed", Default: "30", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "first_layer_extrusion_width", Type: CoFloatOrPercent, Label: "First layer extrusion width", Default: "200%", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "avoid_crossing_perimeters", Type: CoBool, Label: "Avoid crossing perimeters", Default: "0"},
+	&ConfigOptionDef{Key: "only_retract_when_crossing_perimeters", Type: CoBool, Label: "Only retract when crossing perimeters", Default: "1"},
+	&ConfigOptionDef{Key: "skirts", Type: CoInt, Label: "Loops (minimum)", Default: "1", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "skirt_distance", Type: CoFloat, Label: "Distance from object", Default: "6", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "skirt_height", Type: CoInt, Label: "Skirt height", Default: "1", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "min_skirt_length", Type: CoFloat, Label: "Minimum extrusion length", Default: "0", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "brim_width", Type: CoFloat, Label: "Brim width", Default: "0", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "brim_type", Type: CoEnum, Label: "Brim type", Default: "outer_only", EnumValues: []string{"outer_only", "inner_only", "outer_and_inner"}},
+	&ConfigOptionDef{Key: "bed_temperature", Type: CoInt, Label: "Bed temperature", Default: "0", Min: 0, Max: 300},
+	&ConfigOptionDef{Key: "first_layer_bed_temperature", Type: CoInt, Label: "First layer bed temperature", Default: "0", Min: 0, Max: 300},
+
+	// extruders
+	&ConfigOptionDef{Key: "nozzle_diameter", Type: CoFloats, Label: "Nozzle diameter", Default: "0.5", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "filament_diameter", Type: CoFloats, Label: "Filament diameter", Default: "3", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "extrusion_multiplier", Type: CoFloats, Label: "Extrusion multiplier", Default: "1", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "temperature", Type: CoInts, Label: "Temperature", Default: "200", Min: 0, Max: 500},
+	&ConfigOptionDef{Key: "first_layer_temperature", Type: CoInts, Label: "First layer temperature", Default: "200", Min: 0, Max: 500},
+	&ConfigOptionDef{Key: "retract_length", Type: CoFloats, Label: "Retraction length", Default: "2", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "retract_restart_extra", Type: CoFloats, Label: "Extra length on restart", Default: "0"},
+	&ConfigOptionDef{Key: "retract_speed", Type: CoFloats, Label: "Retraction speed", Default: "40", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "deretract_speed", Type: CoFloats, Label: "Deretraction speed", Default: "0", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "retract_before_travel", Type: CoFloats, Label: "Minimum travel after retraction", Default: "2", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "retract_layer_change", Type: CoBools, Label: "Retract on layer change", Default: "0"},
+	&ConfigOptionDef{Key: "retract_length_toolchange", Type: CoFloats, Label: "Retraction length on tool change", Default: "10", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "retract_restart_extra_toolchange", Type: CoFloats, Label: "Extra length on restart after tool change", Default: "0"},
+	&ConfigOptionDef{Key: "retract_lift", Type: CoFloats, Label: "Lift Z", Default: "0", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "retract_lift_above", Type: CoFloats, Label: "Only lift Z above", Default: "0", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "retract_lift_below", Type: CoFloats, Label: "Only lift Z below", Default: "0", Min: 0, Max: noMax},
+	&ConfigOptionDef{Key: "wipe", Type: CoBools, Label: "Wipe while retracting", Default: "0"},
+	&ConfigOptionDef{Key: "retract_before_wipe", Type: CoPercents, Label: "Retract amount before wipe", Default: "0%", Min: 0, Max: 100},
+)
+
+// PrintObjectConfig holds the options which apply to a whole object
+type PrintObjectConfig struct {
+	LayerHeight                     float64        `config:"layer_height"`
+	FirstLayerHeight                FloatOrPercent `config:"first_layer_height"` // percent of LayerHeight
+	ExtrusionWidth                  FloatOrPercent `config:"extrusion_width"`
+	SeamPosition                    SeamPosition   `config:"seam_position"`
+	RaftLayers                      int            `config:"raft_layers"`
+	RaftExpansion                   float64        `config:"raft_expansion"`
+	RaftContactDistance             float64        `config:"raft_contact_distance"`
+	SupportMaterial                 bool           `config:"support_material"`
+	SupportMaterialThreshold        int            `config:"support_material_threshold"`
+	SupportMaterialPattern          SupportPattern `config:"support_material_pattern"`
+	SupportMaterialSpacing          float64        `config:"support_material_spacing"`
+	SupportMaterialAngle            float64        `config:"support_material_angle"`
+	SupportMaterialInterfaceLayers  int            `config:"support_material_interface_layers"`
+	SupportMaterialInterfaceSpacing float64        `config:"support_material_interface_spacing"`
+	SupportMaterialContactDistance  float64        `config:"support_material_contact_distance"`
+	SupportMaterialExtrusionWidth   FloatOrPercent `config:"support_material_extrusion_width"`
+	SupportMaterialSpeed            float64        `config:"support_material_speed"`
+}
+
+// NewPrintObjectConfig will construct a PrintObjectConfig with the default options
+func NewPrintObjectConfig() *PrintObjectConfig {
+	cfg := new(PrintObjectConfig)
+	applyConfigDefaults(cfg)
+	return cfg
+}
+
+// Set will parse value into the option key
+func (cfg *PrintObjectConfig) Set(key string, value string) error {
+	return setConfigOption(cfg, key, value)
+}
+
+// Get will return the option key as written in .ini files
+func (cfg *PrintObjectConfig) Get(key string) (string, error) {
+	return getConfigOption(cfg, key)
+}
+
+// Validate will check the options are in bounds and work together
+func (cfg *PrintObjectConfig) Validate() error {
+	return configError(append(checkConfigBounds(cfg), cfg.problems()...))
+}
+
+func (cfg *PrintObjectConfig) problems() []string {
+	problems := make([]string, 0)
+	if cfg.LayerHeight <= 0 {
+		problems = append(problems, "layer_height must be above 0")
+	}
+	if cfg.FirstLayerHeight.GetAbsValue(cfg.LayerHeight) <= 0 {
+		problems = append(problems, "first_layer_height must be above 0")
+	}
+	return problems
+}
+
+// PrintRegionConfig holds the options which may change between the regions of an
+// object
+type PrintRegionConfig struct {
+	Perimeters                      int            `config:"perimeters"`
+	TopSolidLayers                  int            `config:"top_solid_layers"`
+	BottomSolidLayers               int            `config:"bottom_solid_layers"`
+	FillDensity                     float64        `config:"fill_density"` // percent
+	FillPattern                     FillPattern    `config:"fill_pattern"`
+	ExternalFillPattern             FillPattern    `config:"external_fill_pattern"`
+	FillAngle                       float64        `config:"fill_angle"`              // degrees
+	SolidInfillBelowArea            float64        `config:"solid_infill_below_area"` // square millimeters
+	BridgeFlowRatio                 float64        `config:"bridge_flow_ratio"`
+	PerimeterExtrusionWidth         FloatOrPercent `config:"perimeter_extrusion_width"`
+	ExternalPerimeterExtrusionWidth FloatOrPercent `config:"external_perimeter_extrusion_width"`
+	InfillExtrusionWidth            FloatOrPercent `config:"infill_extrusion_width"`
+	SolidInfillExtrusionWidth       FloatOrPercent `config:"solid_infill_extrusion_width"`
+	TopInfillExtrusionWidth         FloatOrPercent `config:"top_infill_extrusion_width"`
+	PerimeterSpeed                  float64        `config:"perimeter_speed"`
+	ExternalPerimeterSpeed          FloatOrPercent `config:"external_perimeter_speed"` // percent of PerimeterSpeed
+	InfillSpeed                     float64        `config:"infill_speed"`
+	SolidInfillSpeed                FloatOrPercent `config:"solid_infill_speed"`     // percent of InfillSpeed
+	TopSolidInfillSpeed             FloatOrPercent `config:"top_solid_infill_speed"` // percent of solid infill speed
+	BridgeSpeed                     float64        `config:"bridge_speed"`
+	GapFillSpeed                    float64        `config:"gap_fill_speed"`
+}
+
+// NewPrintRegionConfig will construct a PrintRegionConfig with the default options
+func NewPrintRegionConfig() *PrintRegionConfig {
+	cfg := new(PrintRegionConfig)
+	applyConfigDefaults(cfg)
+	return cfg
+}
+
+// Set will parse value into the option key
+func (cfg *PrintRegionConfig) Set(key string, value string) error {
+	return setConfigOption(cfg, key, value)
+}
+
+// Get will return the option key as written in .ini files
+func (cfg *PrintRegionConfig) Get(key string) (string, error) {
+	return getConfigOption(cfg, key)
+}
+
+// Validate will check the options are in bounds and work together
+func (cfg *PrintRegionConfig) Validate() error {
+	return configError(append(checkConfigBounds(cfg), cfg.problems()...))
+}
+
+func (cfg *PrintRegionConfig) problems() []string {
+	problems := make([]string, 0)
+	// as in Slic3r, only patterns that cover everything can be printed solid
+	switch cfg.FillPattern {
+	case FpRectilinear, FpAlignedRectilinear, FpConcentric, FpLine, FpHilbertCurve, FpArchimedeanChords, FpOctagramSpiral:
+	default:
+		if cfg.FillDensity >= 100 {
+			problems = append(problems, fmt.Sprintf("fill_pattern %s is not supposed to work at 100%% density", cfg.FillPattern))
+		}
+	}
+	return problems
+}
+
+// PrintConfig holds the options of the printer and its extruders, which apply to
+// the whole print. Plural options hold a value per extruder
+type PrintConfig struct {
+	BedShape                          Points         `config:"bed_shape"` // unscaled
+	ZOffset                           float64        `config:"z_offset"`
+	GCodeFlavor                       GCodeFlavor    `config:"gcode_flavor"`
+	GCodeComments                     bool           `config:"gcode_comments"`
+	UseRelativeEDistances             bool           `config:"use_relative_e_distances"`
+	UseVolumetricE                    bool           `config:"use_volumetric_e"`
+	UseFirmwareRetraction             bool           `config:"use_firmware_retraction"`
+	StartGCode                        string         `config:"start_gcode"`
+	EndGCode                          string         `config:"end_gcode"`
+	PostProcess                       []string       `config:"post_process"`
+	TravelSpeed                       float64        `config:"travel_speed"`
+	FirstLayerSpeed                   FloatOrPercent `config:"first_layer_speed"`
+	FirstLayerExtrusionWidth          FloatOrPercent `config:"first_layer_extrusion_width"`
+	AvoidCrossingPerimeters           bool           `config:"avoid_crossing_perimeters"`
+	OnlyRetractWhenCrossingPerimeters bool           `config:"only_retract_when_crossing_perimeters"`
+	Skirts                            int            `config:"skirts"`
+	SkirtDistance                     float64        `config:"skirt_distance"`
+	SkirtHeight                       int            `config:"skirt_height"`
+	MinSkirtLength                    float64        `config:"min_skirt_length"`
+	BrimWidth                         float64        `config:"brim_width"`
+	BrimType                          BrimType       `config:"brim_type"`
+	BedTemperature                    int            `config:"bed_temperature"`
+	FirstLayerBedTemperature          int            `config:"first_layer_bed_temperature"`
+
+	NozzleDiameter                []float64 `config:"nozzle_diameter"`
+	FilamentDiameter              []float64 `config:"filament_diameter"`
+	ExtrusionMultiplier           []float64 `config:"extrusion_multiplier"`
+	Temperature                   []int     `config:"temperature"`
+	FirstLayerTemperature         []int     `config:"first_layer_temperature"`
+	RetractLength                 []float64 `config:"retract_length"`
+	RetractRestartExtra           []float64 `config:"retract_restart_extra"`
+	RetractSpeed                  []float64 `config:"retract_speed"`
+	DeretractSpeed                []float64 `config:"deretract_speed"`
+	RetractBeforeTravel           []float64 `config:"retract_before_travel"`
+	RetractLayerChange            []bool    `config:"retract_layer_change"`
+	RetractLengthToolchange       []float64 `config:"retract_length_toolchange"`
+	RetractRestartExtraToolchange []float64 `config:"retract_restart_extra_toolchange"`
+	RetractLift                   []float64 `config:"retract_lift"`
+	RetractLiftAbove              []float64 `config:"retract_lift_above"`
+	RetractLiftBelow              []float64 `config:"retract_lift_below"`
+	Wipe                          []bool    `config:"wipe"`
+	RetractBeforeWipe             []float64 `config:"retract_before_wipe"` // percents
+}
+
+// NewPrintConfig will construct a PrintConfig with the default options
+func NewPrintConfig() *PrintConfig {
+	cfg := new(PrintConfig)
+	applyConfigDefaults(cfg)
+	return cfg
+}
+
+// Set will parse value into the option key
+func (cfg *PrintConfig) Set(key string, value string) error {
+	return setConfigOption(cfg, key, value)
+}
+
+// Get will return the option key as written in .ini files
+func (cfg *PrintConfig) Get(key string) (string, error) {
+	return getConfigOption(cfg, key)
+}
+
+// Validate will check the options are in bounds and work together
+func (cfg *PrintConfig) Validate() error {
+	return configError(append(checkConfigBounds(cfg), cfg.problems()...))
+}
+
+func (cfg *PrintConfig) problems() []string {
+	problems := make([]string, 0)
+	if len(cfg.NozzleDiameter) == 0 {
+		problems = append(problems, "nozzle_diameter needs a value for every extruder")
+	}
+	if len(cfg.FilamentDiameter) == 0 {
+		problems = append(problems, "filament_diameter needs a value for every extruder")
+	}
+	if cfg.UseFirmwareRetraction {
+		switch cfg.GCodeFlavor {
+		case GfRepRap, GfRepetier, GfMarlin, GfKlipper, GfSmoothie, GfMachinekit:
+		default:
+			problems = append(problems, fmt.Sprintf("use_firmware_retraction is not supported by the %s flavor", cfg.GCodeFlavor))
+		}
+		for _, wipe := range cfg.Wipe {
+			if wipe {
+				problems = append(problems, "use_firmware_retraction is not compatible with wipe")
+				break
+			}
+		}
+	}
+	if len(cfg.BedShape) < 3 {
+		problems = append(problems, "bed_shape needs at least 3 points")
+	}
+	return problems
+}
+
+// MinNozzleDiameter will return the diameter of the smallest nozzle
+func (cfg *PrintConfig) MinNozzleDiameter() float64 {
+	min := math.Inf(1)
+	for _, diameter := range cfg.NozzleDiameter {
+		min = math.Min(min, diameter)
+	}
+	return min
+}
+
+// floatAt will return the value of extruder id, or of the first extruder when it has
+// none like Slic3r does
+func floatAt(values []float64, id int) float64 {
+	if id < len(values) {
+		return values[id]
+	}
+	if len(values) == 0 {
+		return 0
+	}
+	return values[0]
+}
+
+// boolAt will return the value of extruder id like floatAt
+func boolAt(values []bool, id int) bool {
+	if id < len(values) {
+		return values[id]
+	}
+	return len(values) > 0 && values[0]
+}
+
+// NewExtruder will construct the Extruder id with its options
+func (cfg *PrintConfig) NewExtruder(id int) *Extruder {
+	extruder := NewExtruder(id, floatAt(cfg.FilamentDiameter, id))
+	extruder.ExtrusionMultiplier = floatAt(cfg.ExtrusionMultiplier, id)
+	extruder.RetractLength = floatAt(cfg.RetractLength, id)
+	extruder.RetractRestartExtra = floatAt(cfg.RetractRestartExtra, id)
+	extruder.RetractSpeed = floatAt(cfg.RetractSpeed, id)
+	extruder.DeretractSpeed = floatAt(cfg.DeretractSpeed, id)
+	extruder.RetractBeforeTravel = floatAt(cfg.RetractBeforeTravel, id)
+	extruder.RetractLayerChange = boolAt(cfg.RetractLayerChange, id)
+	extruder.RetractLengthToolchange = floatAt(cfg.RetractLengthToolchange, id)
+	extruder.RetractRestartExtraToolchange = floatAt(cfg.RetractRestartExtraToolchange, id)
+	extruder.RetractLift = floatAt(cfg.RetractLift, id)
+	extruder.RetractLiftAbove = floatAt(cfg.RetractLiftAbove, id)
+	extruder.RetractLiftBelow = floatAt(cfg.RetractLiftBelow, id)
+	extruder.Wipe = boolAt(cfg.Wipe, id)
+	extruder.RetractBeforeWipe = floatAt(cfg.RetractBeforeWipe, id) / 100
+	return extruder
+}
+
+// FullPrintConfig holds every option, as Slic3r .ini profiles do. Options it doesn't
+// know are kept in Extra so profiles are saved back unchanged
+type FullPrintConfig struct {
+	PrintObjectConfig
+	PrintRegionConfig
+	PrintConfig
+
+	Extra map[string]string
+}
+
+// NewFullPrintConfig will construct a FullPrintConfig with the default options
+func NewFullPrintConfig() *FullPrintConfig {
+	cfg := new(FullPrintConfig)
+	applyConfigDefaults(cfg)
+	cfg.Extra = make(map[string]string)
+	return cfg
+}
+
+// Set will parse value into the option key
+func (cfg *FullPrintConfig) Set(key string, value string) error {
+	return setConfigOption(cfg, key, value)
+}
+
+// Get will return the option key as written in .ini files
+func (cfg *FullPrintConfig) Get(key string) (string, error) {
+	return getConfigOption(cfg, key)
+}
+
+// Keys will return the keys of the known options, sorted
+func (cfg *FullPrintConfig) Keys() []string {
+	return configKeys(cfg)
+}
+
+// Validate will check the options are in bounds and work together
+func (cfg *FullPrintConfig) Validate() error {
+	problems := checkConfigBounds(cfg)
+	problems = append(problems, cfg.PrintObjectConfig.problems()...)
+	problems = append(problems, cfg.PrintRegionConfig.problems()...)
+	problems = append(problems, cfg.PrintConfig.problems()...)
+
+	if len(cfg.NozzleDiameter) > 0 {
+		nozzle := cfg.MinNozzleDiameter()
+		if cfg.LayerHeight > nozzle {
+			problems = append(problems, fmt.Sprintf("layer_height %s can't be greater than the nozzle diameter %s", formatConfigFloat(cfg.LayerHeight), formatConfigFloat(nozzle)))
+		}
+		if first := cfg.FirstLayerHeight.GetAbsValue(cfg.LayerHeight); first > nozzle {
+			problems = append(problems, fmt.Sprintf("first_layer_height %s can't be greater than the nozzle diameter %s", formatConfigFloat(first), formatConfigFloat(nozzle)))
+		}
+	}
+	return configError(problems)
+}
@@ -0,0 +1,366 @@
+package slice
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Objects and modifier volumes follow Slic3r's PrintObject.cpp. An object may
+// override options of the print, layer ranges override its region options and layer
+// height between two heights, and modifier volumes override region options where
+// they cover the object, so each layer is split into one region per distinct region
+// config. Meshes are not handled here, objects and volumes come already sliced.
+
+// ModifierVolume is a volume attached to an object that changes the region options
+// of the parts of the object it covers
+type ModifierVolume struct {
+	Overrides map[string]string // region options, as in .ini files
+	Layers    Layers            // slices of the volume
+}
+
+// NewModifierVolume will construct a ModifierVolume of the sliced layers, overriding
+// nothing yet
+func NewModifierVolume(layers Layers) *ModifierVolume {
+	mv := new(ModifierVolume)
+	mv.Overrides = make(map[string]string)
+	mv.Layers = layers
+	return mv
+}
+
+// RegionConfig will return a copy of base with the overrides of the volume
+func (mv *ModifierVolume) RegionConfig(base *PrintRegionConfig) (*PrintRegionConfig, error) {
+	config := *base
+	for key := range mv.Overrides {
+		if !hasConfigOption(&config, key) {
+			return nil, fmt.Errorf("%w: %s can't be set by a modifier volume", ErrUnknownOption, key)
+		}
+	}
+	if err := applyConfigOverrides(&config, mv.Overrides); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// SlicesAt will return the slices of the volume in the layer spanning z, empty when
+// the volume doesn't reach z
+func (mv *ModifierVolume) SlicesAt(z float64) PolygonExs {
+	for _, layer := range mv.Layers {
+		if z > layer.PrintZ-layer.Height && z <= layer.PrintZ {
+			return layer.Slices
+		}
+	}
+	return NewPolygonExs()
+}
+
+// LayerRange overrides options of an object between two heights
+type LayerRange struct {
+	MinZ      float64           // unscaled, where the range starts
+	MaxZ      float64           // unscaled, where the next range may start
+	Overrides map[string]string // region options and layer_height, as in .ini files
+}
+
+// NewLayerRange will construct a LayerRange from minZ up to maxZ, overriding nothing
+// yet
+func NewLayerRange(minZ float64, maxZ float64) *LayerRange {
+	lr := new(LayerRange)
+	lr.MinZ = minZ
+	lr.MaxZ = maxZ
+	lr.Overrides = make(map[string]string)
+	return lr
+}
+
+// Contains will tell if z is in the range, z within Epsilon of a bound counts as on it
+func (lr *LayerRange) Contains(z float64) bool {
+	return z >= lr.MinZ-Epsilon && z < lr.MaxZ-Epsilon
+}
+
+// objectOverridable will tell if the option can be set per object
+func objectOverridable(key string) bool {
+	return hasConfigOption(new(PrintObjectConfig), key) || hasConfigOption(new(PrintRegionConfig), key)
+}
+
+// rangeOverridable will tell if the option can be set per layer range
+func rangeOverridable(key string) bool {
+	return key == "layer_height" || hasConfigOption(new(PrintRegionConfig), key)
+}
+
+// checkOverrides will return an error when an override can't be set where, or its
+// value can't be parsed
+func checkOverrides(overrides map[string]string, overridable func(string) bool, where string) error {
+	cfg := NewFullPrintConfig()
+	for key, value := range overrides {
+		if !overridable(key) {
+			return fmt.Errorf("%w: %s can't be set per %s", ErrUnknownOption, key, where)
+		}
+		if err := cfg.Set(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintObject is an object with its layers, configs, layer ranges and modifier
+// volumes
+type PrintObject struct {
+	Config       *PrintObjectConfig
+	RegionConfig *PrintRegionConfig // of the parts no modifier or range covers
+	Overrides    map[string]string  // object and region options of the object, as in .ini files
+	Ranges       LayerRanges        // later ranges win where ranges overlap
+	Modifiers    ModifierVolumes    // later volumes win where volumes overlap
+	Layers       Layers
+
+	// Regions are the distinct region configs of the object, indexed like the regions
+	// of its split layers. The first is RegionConfig
+	Regions []*PrintRegionConfig
+}
+
+// NewPrintObject will construct a PrintObject of the sliced layers with the default
+// configs
+func NewPrintObject(layers Layers) *PrintObject {
+	object := new(PrintObject)
+	object.Config = NewPrintObjectConfig()
+	object.RegionConfig = NewPrintRegionConfig()
+	object.Overrides = make(map[string]string)
+	object.Ranges = NewLayerRanges()
+	object.Modifiers = NewModifierVolumes()
+	object.Layers = layers
+	return object
+}
+
+// ApplyConfig will set the configs of the object to those of the print with the
+// overrides of the object, and check the overrides of its ranges
+func (object *PrintObject) ApplyConfig(print *FullPrintConfig) error {
+	if err := checkOverrides(object.Overrides, objectOverridable, "object"); err != nil {
+		return err
+	}
+	for _, lr := range object.Ranges {
+		if err := checkOverrides(lr.Overrides, rangeOverridable, "layer range"); err != nil {
+			return err
+		}
+	}
+
+	objectConfig := print.PrintObjectConfig
+	regionConfig := print.PrintRegionConfig
+	if err := applyConfigOverrides(&objectConfig, object.Overrides); err != nil {
+		return err
+	}
+	if err := applyConfigOverrides(&regionConfig, object.Overrides); err != nil {
+		return err
+	}
+	object.Config = &objectConfig
+	object.RegionConfig = &regionConfig
+	return nil
+}
+
+// rangeAt will return the layer range z is in, nil when it is in none
+func (object *PrintObject) rangeAt(z float64) *LayerRange {
+	for i := len(object.Ranges) - 1; i >= 0; i-- {
+		if object.Ranges[i].Contains(z) {
+			return object.Ranges[i]
+		}
+	}
+	return nil
+}
+
+// rangeConfigs will return copies of the configs of the object with the overrides
+// of lr, which may be nil
+func (object *PrintObject) rangeConfigs(lr *LayerRange) (*PrintObjectConfig, *PrintRegionConfig, error) {
+	objectConfig := *object.Config
+	regionConfig := *object.RegionConfig
+	if lr == nil {
+		return &objectConfig, &regionConfig, nil
+	}
+	if err := checkOverrides(lr.Overrides, rangeOverridable, "layer range"); err != nil {
+		return nil, nil, err
+	}
+	if err := applyConfigOverrides(&objectConfig, lr.Overrides); err != nil {
+		return nil, nil, err
+	}
+	if err := applyConfigOverrides(&regionConfig, lr.Overrides); err != nil {
+		return nil, nil, err
+	}
+	return &objectConfig, &regionConfig, nil
+}
+
+// GenerateLayers will return the empty layers of an object height high, to be
+// sliced at their SliceZ. Layers are layer_height high, or that of the range their
+// bottom is in, and get shorter so ranges start at the bottom of a layer
+func (object *PrintObject) GenerateLayers(height float64) (Layers, error) {
+	layers := NewLayers()
+	z := 0.0
+	for id := 0; z < height-Epsilon; id++ {
+		config, _, err := object.rangeConfigs(object.rangeAt(z))
+		if err != nil {
+			return nil, err
+		}
+		layerHeight := config.LayerHeight
+		if id == 0 {
+			layerHeight = object.Config.FirstLayerHeight.GetAbsValue(object.Config.LayerHeight)
+		}
+		if layerHeight < Epsilon {
+			return nil, fmt.Errorf("%w: layer_height %s at %s mm", ErrInvalidConfig, formatConfigFloat(layerHeight), formatConfigFloat(z))
+		}
+
+		for _, lr := range object.Ranges {
+			for _, boundary := range []float64{lr.MinZ, lr.MaxZ} {
+				if boundary > z+Epsilon && boundary < z+layerHeight-Epsilon {
+					layerHeight = boundary - z
+				}
+			}
+		}
+		top := math.Min(z+layerHeight, height)
+
+		// snap the top onto the bounds of the ranges so rounding errors don't add up
+		// past them
+		for _, lr := range object.Ranges {
+			for _, boundary := range []float64{lr.MinZ, lr.MaxZ} {
+				if math.Abs(top-boundary) < Epsilon {
+					top = boundary
+				}
+			}
+		}
+		layers.Push(NewLayer(id, (z+top)/2, top, top-z))
+		z = top
+	}
+	return layers, nil
+}
+
+// regionIndex will return the index of the region printed with config, adding it
+// when no region has the same options
+func (object *PrintObject) regionIndex(config *PrintRegionConfig) int {
+	for i, region := range object.Regions {
+		if reflect.DeepEqual(region, config) {
+			return i
+		}
+	}
+	object.Regions = append(object.Regions, config)
+	return len(object.Regions) - 1
+}
+
+// rangeRegions will return the region of the object and of each modifier volume
+// within lr, which may be nil
+func (object *PrintObject) rangeRegions(lr *LayerRange) ([]int, error) {
+	_, base, err := object.rangeConfigs(lr)
+	if err != nil {
+		return nil, err
+	}
+	regionOf := []int{object.regionIndex(base)}
+	for _, mv := range object.Modifiers {
+		config, err := mv.RegionConfig(base)
+		if err != nil {
+			return nil, err
+		}
+		regionOf = append(regionOf, object.regionIndex(config))
+	}
+	return regionOf, nil
+}
+
+// SplitRegions will split the slices of every layer into a region for each region
+// config, with the overrides of the layer range the layer is in. Every layer gets
+// all the regions, some maybe empty, so regions line up across layers
+func (object *PrintObject) SplitRegions() error {
+	object.Regions = make([]*PrintRegionConfig, 0)
+	outside, err := object.rangeRegions(nil)
+	if err != nil {
+		return err
+	}
+	inside := make(map[*LayerRange][]int)
+	for _, lr := range object.Ranges {
+		if inside[lr], err = object.rangeRegions(lr); err != nil {
+			return err
+		}
+	}
+
+	for _, layer := range object.Layers {
+		regions := NewLayerRegions()
+		for _, config := range object.Regions {
+			regions.Push(NewLayerRegion(config))
+		}
+		regionOf := outside
+		if lr := object.rangeAt(layer.SliceZ); lr != nil {
+			regionOf = inside[lr]
+		}
+
+		rest := layer.Slices.Polygons()
+		for i := len(object.Modifiers) - 1; i >= 0 && !rest.Empty(); i-- {
+			volume := object.Modifiers[i].SlicesAt(layer.SliceZ).Polygons()
+			if volume.Empty() {
+				continue
+			}
+			covered, err := IntersectionEx(rest, volume)
+			if err != nil {
+				return err
+			}
+			regions[regionOf[i+1]].Slices.Push(covered...)
+			if rest, err = Diff(rest, volume); err != nil {
+				return err
+			}
+		}
+		uncovered, err := UnionEx(rest)
+		if err != nil {
+			return err
+		}
+		regions[regionOf[0]].Slices.Push(uncovered...)
+
+		// volumes sharing a config may touch
+		for _, region := range regions {
+			if len(region.Slices) > 1 {
+				if region.Slices, err = UnionEx(region.Slices.Polygons()); err != nil {
+					return err
+				}
+			}
+		}
+		layer.Regions = regions
+	}
+	return nil
+}
+
+// ReadIni will read the overrides of the object, then those of its layer ranges from
+// [layer_range <min z> <max z>] sections
+func (object *PrintObject) ReadIni(r io.Reader) error {
+	var lr *LayerRange
+	return readIni(r, func(line int, section string, key string, value string) error {
+		if key == "" {
+			fields := strings.Fields(section)
+			if len(fields) != 3 || fields[0] != "layer_range" {
+				return fmt.Errorf("%w: [%s] is not [layer_range <min z> <max z>]", ErrInvalidOptionValue, section)
+			}
+			minZ, errMin := strconv.ParseFloat(fields[1], 64)
+			maxZ, errMax := strconv.ParseFloat(fields[2], 64)
+			if errMin != nil || errMax != nil || minZ >= maxZ {
+				return fmt.Errorf("%w: [%s] is not a range of heights", ErrInvalidOptionValue, section)
+			}
+			lr = NewLayerRange(minZ, maxZ)
+			object.Ranges.Push(lr)
+			return nil
+		}
+
+		overrides, overridable, where := object.Overrides, objectOverridable, "object"
+		if lr != nil {
+			overrides, overridable, where = lr.Overrides, rangeOverridable, "layer range"
+		}
+		if err := checkOverrides(map[string]string{key: value}, overridable, where); err != nil {
+			return err
+		}
+		overrides[key] = value
+		return nil
+	})
+}
+
+// WriteTo will write the overrides of the object and its layer ranges like ReadIni
+// reads them
+func (object *PrintObject) WriteTo(w io.Writer) (int64, error) {
+	out := new(strings.Builder)
+	out.WriteString("# generated by goSlicer\n")
+	writeIniOptions(out, object.Overrides)
+	for _, lr := range object.Ranges {
+		out.WriteString("\n[layer_range " + formatConfigFloat(lr.MinZ) + " " + formatConfigFloat(lr.MaxZ) + "]\n")
+		writeIniOptions(out, lr.Overrides)
+	}
+	n, err := io.WriteString(w, out.String())
+	return int64(n), err
+}
@@ -0,0 +1,161 @@
+package slice
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+)
+
+// ErrUnknownSeamPosition is returned when parsing a seam position name that is not supported
+var ErrUnknownSeamPosition = errors.New("Unknown seam position")
+
+// SeamPosition is how the start of perimeter loops is chosen
+type SeamPosition int
+
+// Seam positions
+const (
+	SeamAligned  SeamPosition = iota // near the seam of the previous loop, forming a line across layers
+	SeamNearest                      // near where the nozzle is
+	SeamRandom                       // anywhere, spreading the seam over the surface
+	SeamRear                         // at the back of the loop
+	SeamSharpest                     // in the sharpest concave corner, or convex corner without one
+)
+
+var seamPositionNames = map[SeamPosition]string{
+	SeamAligned:  "aligned",
+	SeamNearest:  "nearest",
+	SeamRandom:   "random",
+	SeamRear:     "rear",
+	SeamSharpest: "sharpest",
+}
+
+// String will return the name of the seam position as used in Slic3r configs
+func (sp SeamPosition) String() string {
+	if name, ok := seamPositionNames[sp]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseSeamPosition will find the seam position by its name
+func ParseSeamPosition(name string) (SeamPosition, error) {
+	for sp, spName := range seamPositionNames {
+		if spName == name {
+			return sp, nil
+		}
+	}
+	return SeamAligned, ErrUnknownSeamPosition
+}
+
+// seam corner thresholds of Slic3r, as internal angles
+const (
+	seamConcaveAngle = math.Pi * 4 / 3
+	seamConvexAngle  = math.Pi * 2 / 3
+)
+
+// SeamPlacer picks the vertex where each loop starts. This follows the seam
+// placement of Slic3r's GCode.cpp. Corners hide the seam best, so concave corners are
+// preferred, then convex ones, then any vertex. Polygons are scaled and counter
+// clockwise
+type SeamPlacer struct {
+	Position SeamPosition
+
+	rand *rand.Rand
+	last *Point // the previous seam, which aligned seams follow
+}
+
+// NewSeamPlacer will construct a SeamPlacer. Random seams are seeded so the same
+// model always gets the same G-code
+func NewSeamPlacer(position SeamPosition) *SeamPlacer {
+	sp := new(SeamPlacer)
+	sp.Position = position
+	sp.rand = rand.New(rand.NewSource(1))
+	return sp
+}
+
+// Place will return the vertex of pg to start the loop at, with lastPos where the
+// nozzle is, which may be nil
+func (sp *SeamPlacer) Place(pg *Polygon, lastPos *Point) *Point {
+	points := pg.MP.Points
+	if points.Empty() {
+		return nil
+	}
+
+	reference := lastPos
+	if (sp.Position == SeamAligned || sp.Position == SeamSharpest) && sp.last != nil {
+		reference = sp.last
+	}
+	if reference == nil {
+		reference = points.First()
+	}
+
+	var seam *Point
+	switch sp.Position {
+	case SeamRandom:
+		seam = points[sp.rand.Intn(len(points))]
+	case SeamRear:
+		bb := NewBoundingBox(points...)
+		seam = points[NewPoint(bb.Center().X, bb.Max.Y).NearestPointIndex(points)]
+	case SeamSharpest:
+		seam = sharpestCorner(pg, reference)
+	default:
+		candidates := pg.ConcavePoints(seamConcaveAngle)
+		if candidates.Empty() {
+			candidates = pg.ConvexPoints(seamConvexAngle)
+		}
+		if candidates.Empty() {
+			candidates = points
+		}
+		seam = candidates[reference.NearestPointIndex(candidates)]
+	}
+
+	sp.last = seam
+	return seam
+}
+
+// sharpestCorner will return the vertex of the sharpest concave corner, or the
+// sharpest convex corner when there is no concave one. Among corners as sharp the
+// one nearest to reference is picked
+func sharpestCorner(pg *Polygon, reference *Point) *Point {
+	points := pg.MP.Points
+	for _, concave := range []bool{true, false} {
+		var seam *Point
+		sharpest := 0.0
+		for index, point := range points {
+			interior := 2*math.Pi - point.CCWAngle(points.PreviousEntry(index), points.NextEntry(index))
+			// how far the corner goes past the threshold
+			sharpness := seamConvexAngle - interior
+			if concave {
+				sharpness = interior - seamConcaveAngle
+			}
+			if sharpness < -Epsilon {
+				continue
+			}
+			if seam == nil || sharpness > sharpest+Epsilon ||
+				(sharpness > sharpest-Epsilon && reference.DistanceTo(point) < reference.DistanceTo(seam)) {
+				seam, sharpest = point, math.Max(sharpest, sharpness)
+			}
+		}
+		if seam != nil {
+			return seam
+		}
+	}
+	return points[reference.NearestPointIndex(points)]
+}
+
+// SplitLoop will open pg at its seam
+func (sp *SeamPlacer) SplitLoop(pg *Polygon, lastPos *Point) *Polyline {
+	return pg.SplitAtVertex(sp.Place(pg, lastPos))
+}
+
+// SplitExtrusionLoop will rotate loop to start at its seam
+func (sp *SeamPlacer) SplitExtrusionLoop(loop *ExtrusionLoop, lastPos *Point) {
+	pg := loop.Polygon()
+	if !pg.IsCounterClockwise() {
+		// holes are clockwise, corners are judged on the counter clockwise ring
+		pg.MP.Reverse()
+	}
+	if seam := sp.Place(pg, lastPos); seam != nil {
+		loop.SplitAtVertex(seam)
+	}
+}
@@ -0,0 +1,238 @@
+package slice
+
+import (
+	"errors"
+	"math"
+)
+
+// Skirt and brim follow Slic3r's Print.cpp. The skirt is a few loops around everything
+// printed on the first layers, priming the nozzle and showing the first layer goes
+// down right. The brim is loops stuck to the first layer of the objects to hold them
+// on the bed.
+
+// ErrUnknownBrimType is returned when parsing a brim type name that is not supported
+var ErrUnknownBrimType = errors.New("Unknown brim type")
+
+// BrimType tells which sides of the islands get a brim
+type BrimType int
+
+// Brim types
+const (
+	BtOuterOnly     BrimType = iota // around the outside of the islands
+	BtInnerOnly                     // inside their holes
+	BtOuterAndInner                 // both
+)
+
+var brimTypeNames = map[BrimType]string{
+	BtOuterOnly:     "outer_only",
+	BtInnerOnly:     "inner_only",
+	BtOuterAndInner: "outer_and_inner",
+}
+
+// String will return the name of the brim type
+func (bt BrimType) String() string {
+	if name, ok := brimTypeNames[bt]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseBrimType will find the brim type by its name
+func ParseBrimType(name string) (BrimType, error) {
+	for bt, btName := range brimTypeNames {
+		if btName == name {
+			return bt, nil
+		}
+	}
+	return BtOuterOnly, ErrUnknownBrimType
+}
+
+// Skirt generates the skirt loops around the objects
+type Skirt struct {
+	Loops    int
+	Distance float64 // unscaled, from the objects or their brim
+	Height   int     // layers the skirt goes up, and whose footprint it goes around
+
+	// MinLength of filament in unscaled millimeters the skirt must use, adding loops
+	// when Loops are not enough
+	MinLength        float64
+	FilamentDiameter float64
+
+	// ConvexHull goes around the convex hull of the footprint, else around the
+	// footprint itself
+	ConvexHull bool
+
+	BrimWidth float64 // unscaled, so the skirt keeps its distance from the brim
+	Flow      *Flow
+}
+
+// NewSkirt will construct a Skirt with Slic3r's defaults
+func NewSkirt(flow *Flow, filamentDiameter float64) *Skirt {
+	skirt := new(Skirt)
+	skirt.Loops = 1
+	skirt.Distance = 6
+	skirt.Height = 1
+	skirt.FilamentDiameter = filamentDiameter
+	skirt.ConvexHull = true
+	skirt.Flow = flow
+	return skirt
+}
+
+// footprint will return the outline of the first layers of the objects and their
+// support
+func (skirt *Skirt) footprint(objects []Layers) (Polygons, error) {
+	polygons := NewPolygons()
+	for _, layers := range objects {
+		for i := 0; i < len(layers) && i < int(math.Max(1, float64(skirt.Height))); i++ {
+			for _, pgx := range append(layers[i].Slices.GetCopy(), layers[i].SupportIslands...) {
+				polygons.Push(pgx.Contour)
+			}
+		}
+	}
+	if polygons.Empty() {
+		return polygons, nil
+	}
+
+	if skirt.ConvexHull {
+		points := NewPoints()
+		for _, pg := range polygons {
+			points.Push(pg.MP.Points...)
+		}
+		return Polygons{ConvexHull(points)}, nil
+	}
+
+	union, err := UnionEx(polygons)
+	if err != nil {
+		return nil, err
+	}
+	contours := NewPolygons()
+	for _, pgx := range union {
+		contours.Push(pgx.Contour)
+	}
+	return contours, nil
+}
+
+// Generate will return the skirt loops around the objects, outermost first
+func (skirt *Skirt) Generate(objects ...Layers) (*ExtrusionEntityCollection, error) {
+	collection := NewExtrusionEntityCollection()
+	collection.NoSort = true
+	// the minimum length only adds loops to a skirt that is enabled
+	if skirt.Loops <= 0 || skirt.Height <= 0 {
+		return collection, nil
+	}
+
+	footprint, err := skirt.footprint(objects)
+	if err != nil || footprint.Empty() {
+		return collection, err
+	}
+
+	spacing := skirt.Flow.ScaledSpacing()
+	mm3PerMM := skirt.Flow.MM3PerMM()
+	minMM3 := skirt.MinLength * skirt.FilamentDiameter * skirt.FilamentDiameter * math.Pi / 4
+
+	// loops are made inside out, each a spacing further
+	distance := Scale(math.Max(skirt.Distance, skirt.BrimWidth)) - spacing/2
+	extruded := 0.0
+	loops := make(ExtrusionEntities, 0)
+	for i := 0; i < skirt.Loops || extruded < minMM3; i++ {
+		distance += spacing
+		polygons, err := OffsetJoin(footprint, distance, jtRound, Scale(0.1))
+		if err != nil {
+			return nil, err
+		}
+		if polygons.Empty() {
+			break
+		}
+		for _, pg := range polygons {
+			loops = append(loops, NewExtrusionLoopFromPolygon(pg, ErSkirt, ElrSkirt, mm3PerMM, skirt.Flow.ScaledWidth(), Scale(skirt.Flow.Height)))
+			extruded += UnScale(pg.Polyline().MP.Length()) * mm3PerMM
+		}
+	}
+
+	for i := len(loops) - 1; i >= 0; i-- {
+		collection.Append(loops[i])
+	}
+	return collection, nil
+}
+
+// Brim generates the brim loops on the first layer of the objects
+type Brim struct {
+	Width float64 // unscaled
+	Type  BrimType
+	Flow  *Flow
+}
+
+// NewBrim will construct a Brim around the outside of the islands
+func NewBrim(flow *Flow, width float64) *Brim {
+	brim := new(Brim)
+	brim.Width = width
+	brim.Type = BtOuterOnly
+	brim.Flow = flow
+	return brim
+}
+
+// Generate will return the brim loops of the first layer of the objects, the outer
+// ones outermost first, then the inner ones
+func (brim *Brim) Generate(objects ...Layers) (*ExtrusionEntityCollection, error) {
+	collection := NewExtrusionEntityCollection()
+	collection.NoSort = true
+	count := int(math.Floor(brim.Width/brim.Flow.Width + 0.5))
+	if count <= 0 {
+		return collection, nil
+	}
+
+	islands := NewPolygons()
+	for _, layers := range objects {
+		if !layers.Empty() {
+			islands.Push(layers.First().Slices.Polygons()...)
+			islands.Push(layers.First().SupportIslands.Polygons()...)
+		}
+	}
+	union, err := UnionEx(islands)
+	if err != nil || union.Empty() {
+		return collection, err
+	}
+
+	spacing := brim.Flow.ScaledSpacing()
+	push := func(polygons Polygons) {
+		for _, pg := range polygons {
+			collection.Append(NewExtrusionLoopFromPolygon(pg, ErSkirt, ElrSkirt, brim.Flow.MM3PerMM(), brim.Flow.ScaledWidth(), Scale(brim.Flow.Height)))
+		}
+	}
+
+	if brim.Type != BtInnerOnly {
+		contours := NewPolygons()
+		for _, pgx := range union {
+			contours.Push(pgx.Contour)
+		}
+		for i := count; i >= 1; i-- {
+			// the brims of islands close together merge
+			grown, err := OffsetJoin(contours, spacing*(float64(i)-0.5), jtRound, Scale(0.1))
+			if err != nil {
+				return nil, err
+			}
+			merged, err := UnionEx(grown)
+			if err != nil {
+				return nil, err
+			}
+			push(merged.Polygons())
+		}
+	}
+
+	if brim.Type != BtOuterOnly {
+		for _, pgx := range union {
+			for _, hole := range pgx.Holes {
+				inside := hole.GetCopy()
+				inside.MakeCounterClockwise()
+				for i := 1; i <= count; i++ {
+					shrunk, err := OffsetJoin(Polygons{inside}, -spacing*(float64(i)-0.5), jtRound, Scale(0.1))
+					if err != nil {
+						return nil, err
+					}
+					push(shrunk)
+				}
+			}
+		}
+	}
+	return collection, nil
+}
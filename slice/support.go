@@ -0,0 +1,368 @@
+package slice
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrUnknownSupportPattern is returned when parsing a support pattern name that is not supported
+var ErrUnknownSupportPattern = errors.New("Unknown support pattern")
+
+// Support follows Slic3r's SupportMaterial.pm. Overhangs are found by comparing every
+// layer with the one below, the contact areas under them are projected down until
+// they land on the bed or the object, and the columns are filled with a sparse base
+// pattern topped by dense interface layers.
+
+// SupportPattern is the pattern the body of the support is filled with
+type SupportPattern int
+
+// Support patterns
+const (
+	SpRectilinear     SupportPattern = iota
+	SpRectilinearGrid                // rectilinear turned a quarter turn every other layer
+	SpPillars
+)
+
+var supportPatternNames = map[SupportPattern]string{
+	SpRectilinear:     "rectilinear",
+	SpRectilinearGrid: "rectilinear-grid",
+	SpPillars:         "pillars",
+}
+
+// String will return the name of the support pattern as used in Slic3r configs
+func (sp SupportPattern) String() string {
+	if name, ok := supportPatternNames[sp]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseSupportPattern will find the support pattern by its name
+func ParseSupportPattern(name string) (SupportPattern, error) {
+	for sp, spName := range supportPatternNames {
+		if spName == name {
+			return sp, nil
+		}
+	}
+	return SpRectilinear, ErrUnknownSupportPattern
+}
+
+// SupportMaterial generates support under the overhangs of a stack of layers
+type SupportMaterial struct {
+	// ThresholdAngle in degrees from horizontal. Overhangs flatter than this are
+	// supported, 0 supports anything overhanging by more than half a line
+	ThresholdAngle float64
+
+	Pattern          SupportPattern
+	Spacing          float64 // scaled distance between base lines
+	Angle            float64 // of the base lines, interface lines are perpendicular
+	InterfaceLayers  int
+	InterfaceSpacing float64 // scaled distance between interface lines, 0 for solid
+	ContactDistance  float64 // unscaled Z gap between the interface and the object above
+	XYDistance       float64 // scaled distance kept from the sides of the object
+	PillarSize       float64 // scaled
+	PillarSpacing    float64 // scaled
+
+	// RaftLayers are printed under the whole footprint, the top InterfaceLayers of
+	// them dense. The raft reaches RaftMargin past the footprint and leaves a Z gap of
+	// RaftContactDistance to the object
+	RaftLayers          int
+	RaftMargin          float64 // scaled
+	RaftContactDistance float64 // unscaled
+	RaftLayerHeight     float64 // unscaled, 0 uses the height of the first object layer
+
+	Flow          *Flow
+	InterfaceFlow *Flow
+}
+
+// NewSupportMaterial will construct a SupportMaterial with Slic3r's defaults
+func NewSupportMaterial(flow *Flow, interfaceFlow *Flow) *SupportMaterial {
+	sm := new(SupportMaterial)
+	sm.Pattern = SpRectilinear
+	sm.Spacing = Scale(2.5)
+	sm.InterfaceLayers = 3
+	sm.ContactDistance = 0.2
+	sm.XYDistance = flow.ScaledWidth()
+	sm.PillarSize = Scale(2.5)
+	sm.PillarSpacing = Scale(10)
+	sm.RaftMargin = Scale(1.5)
+	sm.RaftContactDistance = 0.2
+	sm.Flow = flow
+	sm.InterfaceFlow = interfaceFlow
+	return sm
+}
+
+// Generate will fill the support islands and extrusions of every layer
+func (sm *SupportMaterial) Generate(layers Layers) error {
+	contacts, err := sm.contactAreas(layers)
+	if err != nil {
+		return err
+	}
+
+	// walk down from the top, carrying the contact areas along
+	projection := NewPolygons()
+	for i := len(layers) - 1; i >= 0; i-- {
+		layer := layers[i]
+		projection = append(projection, contacts[i]...)
+		if projection, err = Union(projection); err != nil {
+			return err
+		}
+
+		// keep away from the object
+		object, err := Offset(layer.Slices.Polygons(), sm.XYDistance)
+		if err != nil {
+			return err
+		}
+		area, err := Diff(projection, object)
+		if err != nil {
+			return err
+		}
+
+		// the top layers of every column are interface
+		top := NewPolygons()
+		for j := i; j < len(layers) && j < i+sm.InterfaceLayers; j++ {
+			top = append(top, contacts[j]...)
+		}
+		interfaceArea, err := Intersection(area, top)
+		if err != nil {
+			return err
+		}
+		base, err := Diff(area, interfaceArea)
+		if err != nil {
+			return err
+		}
+
+		if layer.SupportIslands, err = UnionEx(area); err != nil {
+			return err
+		}
+		if err = sm.fillLayer(layer, base, interfaceArea); err != nil {
+			return err
+		}
+
+		// support stops where it lands on the object
+		if projection, err = Diff(projection, layer.Slices.Polygons()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// contactAreas will return for every layer the overhangs it is the top of the support
+// for, leaving the Z gap to the overhanging layer
+func (sm *SupportMaterial) contactAreas(layers Layers) ([]Polygons, error) {
+	contacts := make([]Polygons, len(layers))
+	for i := 1; i < len(layers); i++ {
+		overhangs, err := sm.overhangs(layers[i], layers[i-1])
+		if err != nil {
+			return nil, err
+		}
+		if overhangs.Empty() {
+			continue
+		}
+
+		bottomZ := layers[i].PrintZ - layers[i].Height
+		for j := i - 1; j >= 0; j-- {
+			if bottomZ-layers[j].PrintZ >= sm.ContactDistance-Epsilon {
+				contacts[j] = append(contacts[j], overhangs...)
+				break
+			}
+		}
+	}
+	return contacts, nil
+}
+
+// overhangs will return the parts of layer flatter than the threshold angle over lower
+func (sm *SupportMaterial) overhangs(layer *Layer, lower *Layer) (Polygons, error) {
+	width := sm.Flow.ScaledWidth()
+	distance := width / 2
+	if sm.ThresholdAngle > 0 {
+		distance = Scale(layer.Height) / math.Tan(sm.ThresholdAngle*math.Pi/180)
+	}
+
+	supported, err := Offset(lower.Slices.Polygons(), distance)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := DiffEx(layer.Slices.Polygons(), supported)
+	if err != nil {
+		return nil, err
+	}
+
+	// ignore crumbs, and grow what is left back over the whole overhang
+	overhangs := NewPolygons()
+	for _, pgx := range diff {
+		if pgx.Area() >= width*width {
+			overhangs.Push(pgx.Polygons()...)
+		}
+	}
+	if overhangs.Empty() {
+		return overhangs, nil
+	}
+	grown, err := Offset(overhangs, distance)
+	if err != nil {
+		return nil, err
+	}
+	return Diff(grown, lower.Slices.Polygons())
+}
+
+// fillLayer will fill the base and interface areas of the layer
+func (sm *SupportMaterial) fillLayer(layer *Layer, base Polygons, interfaceArea Polygons) error {
+	layer.SupportFills = NewExtrusionEntityCollection()
+	layer.SupportInterfaceFills = NewExtrusionEntityCollection()
+
+	pattern, layerID := FpRectilinear, 0
+	switch sm.Pattern {
+	case SpRectilinearGrid:
+		layerID = layer.ID
+	case SpPillars:
+		var err error
+		if base, err = sm.pillars(base); err != nil {
+			return err
+		}
+		pattern = FpConcentric
+	}
+
+	flow := sm.layerFlow(sm.Flow, layer)
+	density := 1.0
+	if sm.Pattern != SpPillars {
+		density = math.Min(1, flow.ScaledSpacing()/sm.Spacing)
+	}
+	params := NewFillParams(layerID, density, sm.Angle, flow.ScaledSpacing())
+	paths, err := sm.fillArea(base, pattern, params, ErSupportMaterial, flow)
+	if err != nil {
+		return err
+	}
+	layer.SupportFills.AppendPaths(paths)
+
+	interfaceFlow := sm.layerFlow(sm.InterfaceFlow, layer)
+	density = 1.0
+	if sm.InterfaceSpacing > 0 {
+		density = math.Min(1, interfaceFlow.ScaledSpacing()/sm.InterfaceSpacing)
+	}
+	params = NewFillParams(0, density, sm.Angle+math.Pi/2, interfaceFlow.ScaledSpacing())
+	if paths, err = sm.fillArea(interfaceArea, FpRectilinear, params, ErSupportMaterialInterface, interfaceFlow); err != nil {
+		return err
+	}
+	layer.SupportInterfaceFills.AppendPaths(paths)
+	return nil
+}
+
+// layerFlow will return the flow with the height of the layer
+func (sm *SupportMaterial) layerFlow(flow *Flow, layer *Layer) *Flow {
+	return NewFlow(flow.Width, layer.Height, flow.NozzleDiameter, false)
+}
+
+// fillArea will fill the area with the pattern, keeping the extrusions inside it
+func (sm *SupportMaterial) fillArea(area Polygons, pattern FillPattern, params *FillParams, role ExtrusionRole, flow *Flow) (ExtrusionPaths, error) {
+	paths := NewExtrusionPaths()
+	if area.Empty() {
+		return paths, nil
+	}
+	fill, err := NewFill(pattern)
+	if err != nil {
+		return nil, err
+	}
+	// inset by half a line, dropping the slivers thinner than a line
+	islands, err := Offset2Ex(area, -flow.ScaledWidth(), flow.ScaledWidth()/2)
+	if err != nil {
+		return nil, err
+	}
+
+	template := NewExtrusionPath(role, flow.MM3PerMM(), flow.ScaledWidth(), Scale(flow.Height))
+	for _, island := range islands {
+		plines, err := fill.FillSurface(island, params)
+		if err != nil {
+			return nil, err
+		}
+		for _, pline := range plines {
+			paths.Push(NewExtrusionPathFromPolyline(pline, template))
+		}
+	}
+	return paths, nil
+}
+
+// pillars will cut the area into square pillars on a grid, aligned across layers
+func (sm *SupportMaterial) pillars(area Polygons) (Polygons, error) {
+	if area.Empty() {
+		return area, nil
+	}
+	points := NewPoints()
+	for _, pg := range area {
+		points.Push(pg.MP.Points...)
+	}
+	bb := NewBoundingBox(points...)
+
+	grid := NewPolygons()
+	for x := alignToGrid(bb.Min.X, sm.PillarSpacing, 0); x <= bb.Max.X; x += sm.PillarSpacing {
+		for y := alignToGrid(bb.Min.Y, sm.PillarSpacing, 0); y <= bb.Max.Y; y += sm.PillarSpacing {
+			pillar := NewBoundingBox(NewPoint(x-sm.PillarSize/2, y-sm.PillarSize/2), NewPoint(x+sm.PillarSize/2, y+sm.PillarSize/2))
+			grid.Push(pillar.Polygon())
+		}
+	}
+	return Intersection(area, grid)
+}
+
+// Raft will return the raft layers under the first layer of the objects and its
+// support, so call it after Generate. The object layers are moved up on top of the
+// raft and renumbered after it
+func (sm *SupportMaterial) Raft(layers Layers) (Layers, error) {
+	raft := NewLayers()
+	if sm.RaftLayers <= 0 || layers.Empty() {
+		return raft, nil
+	}
+
+	first := layers.First()
+	footprint, err := Offset(append(first.Slices.Polygons(), first.SupportIslands.Polygons()...), sm.RaftMargin)
+	if err != nil {
+		return nil, err
+	}
+	islands, err := UnionEx(footprint)
+	if err != nil {
+		return nil, err
+	}
+
+	height := sm.RaftLayerHeight
+	if height <= 0 {
+		height = first.Height
+	}
+	for i := 0; i < sm.RaftLayers; i++ {
+		printZ := height * float64(i+1)
+		layer := NewLayer(i, printZ-height/2, printZ, height)
+		layer.SupportIslands = islands.GetCopy()
+		if err := sm.fillRaftLayer(layer, islands.Polygons(), sm.RaftLayers-i <= sm.InterfaceLayers); err != nil {
+			return nil, err
+		}
+		raft.Push(layer)
+	}
+
+	// the object starts the Z gap above the raft
+	shift := raft.Last().PrintZ + sm.RaftContactDistance - (first.PrintZ - first.Height)
+	for _, layer := range layers {
+		layer.ID += sm.RaftLayers
+		layer.PrintZ += shift
+	}
+	return raft, nil
+}
+
+// fillRaftLayer will fill the raft layer sparse, or dense when it is interface.
+// Every layer turns a quarter turn from the one below to hold it up
+func (sm *SupportMaterial) fillRaftLayer(layer *Layer, area Polygons, dense bool) error {
+	angle := sm.Angle + float64(layer.ID%2)*math.Pi/2
+	flow, role, fills := sm.layerFlow(sm.Flow, layer), ErSupportMaterial, layer.SupportFills
+	density := math.Min(1, flow.ScaledSpacing()/sm.Spacing)
+	if dense {
+		flow, role, fills = sm.layerFlow(sm.InterfaceFlow, layer), ErSupportMaterialInterface, layer.SupportInterfaceFills
+		density = 1
+		if sm.InterfaceSpacing > 0 {
+			density = math.Min(1, flow.ScaledSpacing()/sm.InterfaceSpacing)
+		}
+	}
+
+	params := NewFillParams(0, density, angle, flow.ScaledSpacing())
+	paths, err := sm.fillArea(area, FpRectilinear, params, role, flow)
+	if err != nil {
+		return err
+	}
+	fills.AppendPaths(paths)
+	return nil
+}
@@ -0,0 +1,104 @@
+package slice
+
+// This follows Slic3r's Surface.cpp. Surfaces are the classified regions of a layer
+// which decide how each part is filled.
+
+// SurfaceType tells where a surface sits in the object
+type SurfaceType int
+
+// Surface types
+const (
+	StTop            SurfaceType = iota
+	StBottom                     // printed on the bed or on support
+	StBottomBridge               // printed over air
+	StInternal                   // sparse infill
+	StInternalSolid              // solid shells below top and above bottom surfaces
+	StInternalBridge             // solid infill printed over sparse infill
+	StInternalVoid               // not filled at all
+)
+
+var surfaceTypeNames = map[SurfaceType]string{
+	StTop:            "top",
+	StBottom:         "bottom",
+	StBottomBridge:   "bottom bridge",
+	StInternal:       "internal",
+	StInternalSolid:  "internal solid",
+	StInternalBridge: "internal bridge",
+	StInternalVoid:   "internal void",
+}
+
+// String will return the name of the surface type
+func (st SurfaceType) String() string {
+	if name, ok := surfaceTypeNames[st]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// Surface is a region of a layer and how it should be filled
+type Surface struct {
+	PolygonEx       *PolygonEx
+	SurfaceType     SurfaceType
+	Thickness       float64 // unscaled, -1 when not known
+	ThicknessLayers int
+	BridgeAngle     float64 // radians, -1 when not detected
+	ExtraPerimeters int
+}
+
+// NewSurface will construct a Surface
+func NewSurface(pgx *PolygonEx, st SurfaceType) *Surface {
+	surface := new(Surface)
+	surface.PolygonEx = pgx
+	surface.SurfaceType = st
+	surface.Thickness = -1
+	surface.ThicknessLayers = 1
+	surface.BridgeAngle = -1
+	return surface
+}
+
+// NewSurfaceFrom will construct a Surface for pgx with the properties of another
+func NewSurfaceFrom(other *Surface, pgx *PolygonEx) *Surface {
+	surface := *other
+	surface.PolygonEx = pgx
+	return &surface
+}
+
+// GetCopy will return a copy of the Surface and its points
+func (surface *Surface) GetCopy() *Surface {
+	return NewSurfaceFrom(surface, surface.PolygonEx.GetCopy())
+}
+
+// Area will find the area
+func (surface *Surface) Area() float64 {
+	return surface.PolygonEx.Area()
+}
+
+// IsTop will tell if the surface is a top surface
+func (surface *Surface) IsTop() bool {
+	return surface.SurfaceType == StTop
+}
+
+// IsBottom will tell if the surface is printed on the bed, on support or over air
+func (surface *Surface) IsBottom() bool {
+	return surface.SurfaceType == StBottom || surface.SurfaceType == StBottomBridge
+}
+
+// IsBridge will tell if the surface is printed over air or sparse infill
+func (surface *Surface) IsBridge() bool {
+	return surface.SurfaceType == StBottomBridge || surface.SurfaceType == StInternalBridge
+}
+
+// IsExternal will tell if the surface is visible from outside the object
+func (surface *Surface) IsExternal() bool {
+	return surface.IsTop() || surface.IsBottom()
+}
+
+// IsInternal will tell if the surface is inside the object
+func (surface *Surface) IsInternal() bool {
+	return !surface.IsExternal()
+}
+
+// IsSolid will tell if the surface is filled solid
+func (surface *Surface) IsSolid() bool {
+	return surface.IsExternal() || surface.SurfaceType == StInternalSolid || surface.SurfaceType == StInternalBridge
+}
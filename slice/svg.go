@@ -0,0 +1,212 @@
+package slice
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SVG follows Slic3r's SVG debugging class. Everything drawn is kept until the
+// SVG is written so the viewBox can be fitted around it. Output is in unscaled
+// millimeters with Y pointing up like the slicer
+type SVG struct {
+	BoundingBox *BoundingBox
+	Margin      float64 // scaled space left around the drawing
+	Stroke      float64 // scaled stroke width used when nothing else is given
+	elements    []string
+}
+
+// NewSVG will construct an empty SVG. Supplying a bounding box fixes the viewBox,
+// otherwise it grows to fit whatever is drawn
+func NewSVG(bb *BoundingBox) *SVG {
+	svg := new(SVG)
+	svg.BoundingBox = new(BoundingBox)
+	if bb != nil && bb.Defined() {
+		svg.BoundingBox.MergeBox(bb)
+	}
+	svg.Margin = Scale(1)
+	svg.Stroke = Scale(0.05)
+	svg.elements = make([]string, 0)
+	return svg
+}
+
+func svgNumber(val float64) string {
+	return strconv.FormatFloat(UnScale(val), 'f', -1, 64)
+}
+
+func svgCoord(point *Point) string {
+	return svgNumber(point.X) + "," + svgNumber(-point.Y)
+}
+
+func (svg *SVG) merge(points Points) {
+	for _, point := range points {
+		svg.BoundingBox.MergePoint(point)
+	}
+}
+
+// pathData will write the points as an SVG path, closing it when asked
+func pathData(points Points, closed bool) string {
+	if len(points) == 0 {
+		return ""
+	}
+	coords := make([]string, 0, len(points))
+	for _, point := range points {
+		coords = append(coords, svgCoord(point))
+	}
+	data := "M " + strings.Join(coords, " L ")
+	if closed {
+		data += " Z"
+	}
+	return data
+}
+
+// DrawPolygon will draw a filled polygon
+func (svg *SVG) DrawPolygon(pg *Polygon, fill string) {
+	svg.merge(pg.MP.Points)
+	svg.elements = append(svg.elements, fmt.Sprintf(
+		`<path d="%s" style="fill: %s; stroke: black; stroke-width: %s; fill-opacity: 0.5" />`,
+		pathData(pg.MP.Points, true), fill, svgNumber(svg.Stroke)))
+}
+
+// DrawPolygons will draw every polygon
+func (svg *SVG) DrawPolygons(pgs Polygons, fill string) {
+	for _, pg := range pgs {
+		svg.DrawPolygon(pg, fill)
+	}
+}
+
+// DrawPolygonEx will draw a filled PolygonEx. The holes are left empty by the evenodd rule
+func (svg *SVG) DrawPolygonEx(pgx *PolygonEx, fill string) {
+	rings := make([]string, 0, len(pgx.Holes)+1)
+	for _, ring := range pgx.Polygons() {
+		svg.merge(ring.MP.Points)
+		rings = append(rings, pathData(ring.MP.Points, true))
+	}
+	svg.elements = append(svg.elements, fmt.Sprintf(
+		`<path d="%s" style="fill: %s; fill-rule: evenodd; stroke: black; stroke-width: %s; fill-opacity: 0.5" />`,
+		strings.Join(rings, " "), fill, svgNumber(svg.Stroke)))
+}
+
+// DrawPolygonExs will draw every PolygonEx
+func (svg *SVG) DrawPolygonExs(pgxs PolygonExs, fill string) {
+	for _, pgx := range pgxs {
+		svg.DrawPolygonEx(pgx, fill)
+	}
+}
+
+// DrawPolyline will draw an open polyline as wide as its Width. Polylines without
+// a Width use the SVG stroke
+func (svg *SVG) DrawPolyline(pl *Polyline, stroke string) {
+	width := pl.Width
+	if width <= 0 {
+		width = svg.Stroke
+	}
+	svg.merge(pl.MP.Points)
+	svg.elements = append(svg.elements, fmt.Sprintf(
+		`<path d="%s" style="fill: none; stroke: %s; stroke-width: %s; stroke-linecap: round; stroke-linejoin: round" />`,
+		pathData(pl.MP.Points, false), stroke, svgNumber(width)))
+}
+
+// DrawPolylines will draw every polyline
+func (svg *SVG) DrawPolylines(pls Polylines, stroke string) {
+	for _, pl := range pls {
+		svg.DrawPolyline(pl, stroke)
+	}
+}
+
+// DrawLine will draw a line with a scaled width
+func (svg *SVG) DrawLine(line *Line, stroke string, width float64) {
+	pl := NewPolyline()
+	pl.MP.Points.Push(line.A, line.B)
+	pl.Width = width
+	svg.DrawPolyline(pl, stroke)
+}
+
+// DrawPoint will draw a dot with a scaled radius
+func (svg *SVG) DrawPoint(point *Point, fill string, radius float64) {
+	svg.BoundingBox.MergePoint(point)
+	svg.elements = append(svg.elements, fmt.Sprintf(
+		`<circle cx="%s" cy="%s" r="%s" style="fill: %s" />`,
+		svgNumber(point.X), svgNumber(-point.Y), svgNumber(radius), fill))
+}
+
+// DrawPoints will draw a dot for every point
+func (svg *SVG) DrawPoints(points Points, fill string, radius float64) {
+	for _, point := range points {
+		svg.DrawPoint(point, fill, radius)
+	}
+}
+
+// DrawText will write a label with its baseline starting at point
+func (svg *SVG) DrawText(point *Point, text string, color string) {
+	svg.BoundingBox.MergePoint(point)
+	svg.elements = append(svg.elements, fmt.Sprintf(
+		`<text x="%s" y="%s" font-family="sans-serif" font-size="1" fill="%s">%s</text>`,
+		svgNumber(point.X), svgNumber(-point.Y), color, html.EscapeString(text)))
+}
+
+// WriteTo will write the SVG document with a viewBox around everything drawn
+func (svg *SVG) WriteTo(w io.Writer) (int64, error) {
+	minX, minY, width, height := 0.0, 0.0, 0.0, 0.0
+	if svg.BoundingBox.Defined() {
+		size := svg.BoundingBox.Size()
+		minX = svg.BoundingBox.Min.X - svg.Margin
+		minY = -svg.BoundingBox.Max.Y - svg.Margin
+		width = size.X + 2*svg.Margin
+		height = size.Y + 2*svg.Margin
+	}
+
+	var out strings.Builder
+	out.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n")
+	fmt.Fprintf(&out,
+		`<svg xmlns="http://www.w3.org/2000/svg" version="1.1" width="%smm" height="%smm" viewBox="%s %s %s %s">`+"\n",
+		svgNumber(width), svgNumber(height), svgNumber(minX), svgNumber(minY), svgNumber(width), svgNumber(height))
+	for _, element := range svg.elements {
+		out.WriteString("  " + element + "\n")
+	}
+	out.WriteString("</svg>\n")
+
+	n, err := io.WriteString(w, out.String())
+	return int64(n), err
+}
+
+// Save will write the SVG to a file
+func (svg *SVG) Save(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	buf := bufio.NewWriter(file)
+	if _, err = svg.WriteTo(buf); err == nil {
+		err = buf.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// SVGLayers will save one SVG per layer into dir, named layer-<id>.svg. Every file
+// shares the bounding box of the whole object so they line up when flipped through
+func SVGLayers(layers Layers, dir string) error {
+	bb := new(BoundingBox)
+	for _, layer := range layers {
+		bb.MergeBox(layer.BoundingBox())
+	}
+
+	for _, layer := range layers {
+		svg := NewSVG(bb)
+		svg.DrawPolygonExs(layer.Slices, "lightgray")
+		svg.DrawText(NewPoint(bb.Min.X, bb.Max.Y+svg.Margin/2),
+			fmt.Sprintf("layer %d z=%g", layer.ID, layer.PrintZ), "black")
+		if err := svg.Save(filepath.Join(dir, fmt.Sprintf("layer-%04d.svg", layer.ID))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,402 @@
+package slice
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Well Known Text follows the OGC Simple Features spec. Coordinates are written
+// exactly as they are stored, so they stay scaled. Rings are closed on the way out
+// and the repeated closing point is dropped on the way in.
+
+// ErrInvalidWKT is returned when a WKT string cannot be parsed
+var ErrInvalidWKT = errors.New("Invalid WKT")
+
+func wktNumber(val float64) string {
+	return strconv.FormatFloat(val, 'f', -1, 64)
+}
+
+func wktCoord(point *Point) string {
+	return wktNumber(point.X) + " " + wktNumber(point.Y)
+}
+
+// wktPoints will write a parenthesised list of coordinates, closing it as a ring if asked
+func wktPoints(points Points, ring bool) string {
+	if len(points) == 0 {
+		return "EMPTY"
+	}
+	coords := make([]string, 0, len(points)+1)
+	for _, point := range points {
+		coords = append(coords, wktCoord(point))
+	}
+	if ring && !points.First().CoincidesWith(points.Last()) {
+		coords = append(coords, wktCoord(points.First()))
+	}
+	return "(" + strings.Join(coords, ", ") + ")"
+}
+
+func wktRings(rings Polygons) string {
+	if len(rings) == 0 || len(rings[0].MP.Points) == 0 {
+		return "EMPTY"
+	}
+	parts := make([]string, 0, len(rings))
+	for _, ring := range rings {
+		parts = append(parts, wktPoints(ring.MP.Points, true))
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+func wktList(parts []string) string {
+	if len(parts) == 0 {
+		return "EMPTY"
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// WKT will return the point as a WKT POINT
+func (p *Point) WKT() string {
+	return "POINT (" + wktCoord(p) + ")"
+}
+
+// WKT will return the line as a WKT LINESTRING
+func (l *Line) WKT() string {
+	return "LINESTRING " + wktPoints(Points{l.A, l.B}, false)
+}
+
+// WKT will return the polyline as a WKT LINESTRING
+func (pl *Polyline) WKT() string {
+	return "LINESTRING " + wktPoints(pl.MP.Points, false)
+}
+
+// WKT will return the polygon as a WKT POLYGON
+func (pg *Polygon) WKT() string {
+	return "POLYGON " + wktRings(Polygons{pg})
+}
+
+// WKT will return the PolygonEx as a WKT POLYGON with the holes as interior rings
+func (pgx *PolygonEx) WKT() string {
+	return "POLYGON " + wktRings(pgx.Polygons())
+}
+
+// WKT will return the points as a WKT MULTIPOINT
+func (pts Points) WKT() string {
+	parts := make([]string, 0, len(pts))
+	for _, point := range pts {
+		parts = append(parts, "("+wktCoord(point)+")")
+	}
+	return "MULTIPOINT " + wktList(parts)
+}
+
+// WKT will return the polylines as a WKT MULTILINESTRING
+func (pls Polylines) WKT() string {
+	parts := make([]string, 0, len(pls))
+	for _, pline := range pls {
+		parts = append(parts, wktPoints(pline.MP.Points, false))
+	}
+	return "MULTILINESTRING " + wktList(parts)
+}
+
+// WKT will return the polygons as a WKT MULTIPOLYGON
+func (pts Polygons) WKT() string {
+	parts := make([]string, 0, len(pts))
+	for _, poly := range pts {
+		parts = append(parts, wktRings(Polygons{poly}))
+	}
+	return "MULTIPOLYGON " + wktList(parts)
+}
+
+// WKT will return the PolygonExs as a WKT MULTIPOLYGON
+func (pts PolygonExs) WKT() string {
+	parts := make([]string, 0, len(pts))
+	for _, pgx := range pts {
+		parts = append(parts, wktRings(pgx.Polygons()))
+	}
+	return "MULTIPOLYGON " + wktList(parts)
+}
+
+// WKTCollection will write any of the supported geometries as a WKT GEOMETRYCOLLECTION
+func WKTCollection(geometries ...interface{}) (string, error) {
+	parts := make([]string, 0, len(geometries))
+	for _, geometry := range geometries {
+		wkt, err := WKT(geometry)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, wkt)
+	}
+	return "GEOMETRYCOLLECTION " + wktList(parts), nil
+}
+
+// WKT will write any of the supported geometries. A []interface{} is written as a
+// GEOMETRYCOLLECTION
+func WKT(geometry interface{}) (string, error) {
+	switch geom := geometry.(type) {
+	case *Point:
+		return geom.WKT(), nil
+	case *Line:
+		return geom.WKT(), nil
+	case *Polyline:
+		return geom.WKT(), nil
+	case *Polygon:
+		return geom.WKT(), nil
+	case *PolygonEx:
+		return geom.WKT(), nil
+	case Points:
+		return geom.WKT(), nil
+	case Polylines:
+		return geom.WKT(), nil
+	case Polygons:
+		return geom.WKT(), nil
+	case PolygonExs:
+		return geom.WKT(), nil
+	case []interface{}:
+		return WKTCollection(geom...)
+	}
+	return "", fmt.Errorf("Cannot write %T as WKT", geometry)
+}
+
+// wktParser is a recursive descent parser over WKT tokens
+type wktParser struct {
+	tokens []string
+	pos    int
+}
+
+func tokenizeWKT(wkt string) []string {
+	tokens := make([]string, 0)
+	for i := 0; i < len(wkt); {
+		c := rune(wkt[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(' || c == ')' || c == ',':
+			tokens = append(tokens, string(c))
+			i++
+		default:
+			start := i
+			for i < len(wkt) && !unicode.IsSpace(rune(wkt[i])) && !strings.ContainsRune("(),", rune(wkt[i])) {
+				i++
+			}
+			tokens = append(tokens, wkt[start:i])
+		}
+	}
+	return tokens
+}
+
+func (wp *wktParser) peek() string {
+	if wp.pos >= len(wp.tokens) {
+		return ""
+	}
+	return wp.tokens[wp.pos]
+}
+
+func (wp *wktParser) next() string {
+	token := wp.peek()
+	wp.pos++
+	return token
+}
+
+func (wp *wktParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at token %d", ErrInvalidWKT, fmt.Sprintf(format, args...), wp.pos)
+}
+
+func (wp *wktParser) expect(token string) error {
+	if got := wp.next(); got != token {
+		return wp.errorf("expected %q, got %q", token, got)
+	}
+	return nil
+}
+
+// empty will consume an EMPTY keyword, or the Z/M dimension markers before a list
+func (wp *wktParser) empty() bool {
+	for {
+		switch strings.ToUpper(wp.peek()) {
+		case "Z", "M", "ZM":
+			wp.next()
+		case "EMPTY":
+			wp.next()
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// list will parse "( item, item, ... )" calling item for every entry
+func (wp *wktParser) list(item func() error) error {
+	if err := wp.expect("("); err != nil {
+		return err
+	}
+	for {
+		if err := item(); err != nil {
+			return err
+		}
+		if wp.peek() != "," {
+			break
+		}
+		wp.next()
+	}
+	return wp.expect(")")
+}
+
+// coord will parse an X Y pair. Z and M values are read and dropped
+func (wp *wktParser) coord() (*Point, error) {
+	values := make([]float64, 0, 4)
+	for wp.peek() != "," && wp.peek() != ")" && wp.peek() != "" {
+		val, err := strconv.ParseFloat(wp.next(), 64)
+		if err != nil {
+			return nil, wp.errorf("bad number")
+		}
+		values = append(values, val)
+	}
+	if len(values) < 2 {
+		return nil, wp.errorf("coordinates need X and Y")
+	}
+	return NewPoint(values[0], values[1]), nil
+}
+
+// points will parse a coordinate list. MULTIPOINT coordinates may be parenthesised
+func (wp *wktParser) points() (Points, error) {
+	points := NewPoints()
+	if wp.empty() {
+		return points, nil
+	}
+	err := wp.list(func() error {
+		var point *Point
+		var err error
+		if wp.peek() == "(" {
+			err = wp.list(func() error {
+				point, err = wp.coord()
+				return err
+			})
+		} else {
+			point, err = wp.coord()
+		}
+		if err == nil {
+			points.Push(point)
+		}
+		return err
+	})
+	return points, err
+}
+
+func (wp *wktParser) ring() (*Polygon, error) {
+	points, err := wp.points()
+	if err != nil {
+		return nil, err
+	}
+	if len(points) > 1 && points.First().CoincidesWith(points.Last()) {
+		points.PopBack()
+	}
+	poly := NewPolygon()
+	poly.MP.Points = points
+	return poly, nil
+}
+
+func (wp *wktParser) polygonEx() (*PolygonEx, error) {
+	pgx := NewPolygonEx()
+	if wp.empty() {
+		return pgx, nil
+	}
+	first := true
+	err := wp.list(func() error {
+		ring, err := wp.ring()
+		if err != nil {
+			return err
+		}
+		if first {
+			pgx.Contour = ring
+			first = false
+		} else {
+			pgx.Holes.Push(ring)
+		}
+		return nil
+	})
+	return pgx, err
+}
+
+func (wp *wktParser) geometry() (interface{}, error) {
+	keyword := strings.ToUpper(wp.next())
+	switch keyword {
+	case "POINT":
+		points, err := wp.points()
+		if err != nil {
+			return nil, err
+		}
+		if len(points) != 1 {
+			return nil, wp.errorf("POINT needs exactly one coordinate")
+		}
+		return points.First(), nil
+
+	case "LINESTRING":
+		points, err := wp.points()
+		if err != nil {
+			return nil, err
+		}
+		pline := NewPolyline()
+		pline.MP.Points = points
+		return pline, nil
+
+	case "POLYGON":
+		return wp.polygonEx()
+
+	case "MULTIPOINT":
+		return wp.points()
+
+	case "MULTILINESTRING":
+		plines := NewPolylines()
+		if wp.empty() {
+			return plines, nil
+		}
+		err := wp.list(func() error {
+			points, err := wp.points()
+			pline := NewPolyline()
+			pline.MP.Points = points
+			plines.Push(pline)
+			return err
+		})
+		return plines, err
+
+	case "MULTIPOLYGON":
+		pgxs := NewPolygonExs()
+		if wp.empty() {
+			return pgxs, nil
+		}
+		err := wp.list(func() error {
+			pgx, err := wp.polygonEx()
+			pgxs.Push(pgx)
+			return err
+		})
+		return pgxs, err
+
+	case "GEOMETRYCOLLECTION":
+		geometries := make([]interface{}, 0)
+		if wp.empty() {
+			return geometries, nil
+		}
+		err := wp.list(func() error {
+			geometry, err := wp.geometry()
+			geometries = append(geometries, geometry)
+			return err
+		})
+		return geometries, err
+	}
+	return nil, wp.errorf("unknown geometry %q", keyword)
+}
+
+// ParseWKT will parse a WKT string. The result is a *Point, *Polyline (LINESTRING),
+// *PolygonEx (POLYGON), Points (MULTIPOINT), Polylines (MULTILINESTRING),
+// PolygonExs (MULTIPOLYGON) or []interface{} (GEOMETRYCOLLECTION)
+func ParseWKT(wkt string) (interface{}, error) {
+	wp := &wktParser{tokens: tokenizeWKT(wkt)}
+	geometry, err := wp.geometry()
+	if err != nil {
+		return nil, err
+	}
+	if wp.pos != len(wp.tokens) {
+		return nil, wp.errorf("unexpected %q", wp.peek())
+	}
+	return geometry, nil
+}
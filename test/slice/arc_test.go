@@ -0,0 +1,82 @@
+package slice_test
+
+import (
+	"fmt"
+	"goSlicer/slice"
+	"math"
+	"testing"
+)
+
+func circlePoints(cx, cy, radius float64, count int, sweep float64) slice.Points {
+	points := slice.NewPoints()
+	for i := 0; i < count; i++ {
+		theta := sweep * float64(i) / float64(count)
+		points.Push(slice.NewPoint(cx+radius*math.Cos(theta), cy+radius*math.Sin(theta)))
+	}
+	return points
+}
+
+func TestCircleCenter(t *testing.T) {
+	center := slice.CircleCenter(slice.NewPoint(10, 0), slice.NewPoint(0, 10), slice.NewPoint(-10, 0))
+	if center == nil || center.DistanceTo(slice.NewPoint(0, 0)) > 1e-9 {
+		fmt.Println("Center should be the origin")
+		t.Fail()
+	}
+	if slice.CircleCenter(slice.NewPoint(0, 0), slice.NewPoint(1, 1), slice.NewPoint(2, 2)) != nil {
+		fmt.Println("Collinear points have no center")
+		t.Fail()
+	}
+}
+
+func TestFitArcsPolygon(t *testing.T) {
+	radius := slice.Scale(10)
+	poly := slice.NewPolygon()
+	poly.MP.Points = circlePoints(0, 0, radius, 90, 2*math.Pi)
+
+	path := poly.FitArcs(slice.Scale(0.01))
+	if path.ArcCount() == 0 || len(path.Segments) > 4 {
+		fmt.Printf("Expected a few arcs, got %d segments\n", len(path.Segments))
+		t.Fail()
+	}
+	for _, seg := range path.Segments {
+		if seg.IsArc() && (!seg.Arc.IsCounterClockwise() || math.Abs(seg.Arc.Radius-radius) > slice.Scale(0.01)) {
+			fmt.Println("Bad arc", seg.Arc.Describe())
+			t.Fail()
+		}
+	}
+	if !path.Segments[0].Start().CoincidesWith(path.Segments[len(path.Segments)-1].End()) {
+		fmt.Println("Polygon path should be closed")
+		t.Fail()
+	}
+}
+
+func TestFitArcsPolyline(t *testing.T) {
+	radius := slice.Scale(5)
+	pl := slice.NewPolyline()
+	pl.MP.Points.Push(slice.NewPoint(-radius*2, 0))
+	// clockwise half circle from (0, 0) to (2r, 0) around (r, 0)
+	for _, point := range circlePoints(0, 0, radius, 37, math.Pi) {
+		pl.MP.Points.Push(slice.NewPoint(radius-point.X, point.Y))
+	}
+	pl.MP.Points.Push(slice.NewPoint(radius*4, 0))
+
+	path := pl.FitArcs(slice.Scale(0.005))
+	if len(path.Segments) != 3 || path.Segments[0].IsArc() || !path.Segments[1].IsArc() || path.Segments[2].IsArc() {
+		fmt.Printf("Expected line, arc, line, got %d segments\n", len(path.Segments))
+		t.FailNow()
+	}
+	arc := path.Segments[1].Arc
+	if arc.IsCounterClockwise() || !arc.Center.CoincidesWithEpsilon(slice.NewPoint(radius, 0)) {
+		fmt.Println("Expected a clockwise arc around (r, 0)", arc.Describe())
+		t.Fail()
+	}
+
+	zigzag := slice.NewPolyline()
+	for i := 0; i < 10; i++ {
+		zigzag.MP.Points.Push(slice.NewPoint(float64(i)*100, float64(i%2)*100))
+	}
+	if zigzag.FitArcs(1).ArcCount() != 0 {
+		fmt.Println("A zigzag should not become arcs")
+		t.Fail()
+	}
+}
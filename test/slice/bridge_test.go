@@ -0,0 +1,109 @@
+package slice_test
+
+import (
+	"fmt"
+	"goSlicer/slice"
+	"math"
+	"testing"
+)
+
+// rectangle will make a counter clockwise rectangle in mm
+func rectangle(x0, y0, x1, y1 float64) *slice.PolygonEx {
+	pgx := slice.NewPolygonEx()
+	pgx.Contour.MP.Points.Push(
+		slice.NewPoint(slice.Scale(x0), slice.Scale(y0)),
+		slice.NewPoint(slice.Scale(x1), slice.Scale(y0)),
+		slice.NewPoint(slice.Scale(x1), slice.Scale(y1)),
+		slice.NewPoint(slice.Scale(x0), slice.Scale(y1)))
+	return pgx
+}
+
+// layerStack will make count layers 0.2mm high, each holding copies of the islands
+func layerStack(count int, islands ...*slice.PolygonEx) slice.Layers {
+	layers := slice.NewLayers()
+	for i := 0; i < count; i++ {
+		layer := slice.NewLayer(i, 0.1+0.2*float64(i), 0.2*float64(i+1), 0.2)
+		for _, island := range islands {
+			layer.Slices.Push(island.GetCopy())
+		}
+		layers.Push(layer)
+	}
+	return layers
+}
+
+func TestBridgeDetector(t *testing.T) {
+	tests := []struct {
+		lower slice.PolygonExs
+		angle float64
+	}{
+		// pillars left and right
+		{slice.PolygonExs{rectangle(0, 0, 5, 5), rectangle(10, 0, 15, 5)}, 0},
+		// pillars below and above
+		{slice.PolygonExs{rectangle(5, -5, 10, 0), rectangle(5, 5, 10, 10)}, math.Pi / 2},
+		// a U shape bridges across its arms
+		{slice.PolygonExs{rectangle(0, 0, 5, 5), rectangle(0, -5, 15, 0), rectangle(10, 0, 15, 5)}, 0},
+	}
+	for i, test := range tests {
+		bd, err := slice.NewBridgeDetector(rectangle(5, 0, 10, 5), test.lower, slice.Scale(0.5))
+		if err != nil {
+			fmt.Println("Bridge detector should be constructed", i, err)
+			t.Fail()
+			continue
+		}
+		angle, uncovered, err := bd.Detect()
+		if err != nil || math.Abs(angle-test.angle) > 0.01 {
+			fmt.Println("Wrong bridge angle", i, angle, test.angle, err)
+			t.Fail()
+		}
+		if area := slice.UnScale(slice.UnScale(uncovered.Area())); area > 0.5 {
+			fmt.Println("Bridge between anchors should be covered", i, area)
+			t.Fail()
+		}
+	}
+
+	// a bridge anchored on one side only can't be printed
+	bd, _ := slice.NewBridgeDetector(rectangle(5, 0, 10, 5), slice.PolygonExs{rectangle(0, 0, 5, 5)}, slice.Scale(0.5))
+	angle, uncovered, err := bd.Detect()
+	if err != nil || angle != -1 || math.Round(slice.UnScale(slice.UnScale(uncovered.Area()))) != 25 {
+		fmt.Println("Cantilever should not be bridged", angle, err)
+		t.Fail()
+	}
+}
+
+func TestBridgeDetectorResolution(t *testing.T) {
+	for _, resolution := range []float64{0, -1, math.NaN()} {
+		bd, _ := slice.NewBridgeDetector(rectangle(5, 0, 10, 5), slice.PolygonExs{rectangle(0, 0, 5, 5), rectangle(10, 0, 15, 5)}, slice.Scale(0.5))
+		bd.Resolution = resolution
+		if angle, _, err := bd.Detect(); err != nil || math.Abs(angle) > 0.01 {
+			fmt.Println("A bad resolution should fall back to the default", resolution, angle, err)
+			t.Fail()
+		}
+	}
+}
+
+func TestDetectBridgeAngles(t *testing.T) {
+	layers := layerStack(2)
+	layers[0].Slices.Push(rectangle(-5, 0, 5, 5), rectangle(10, 0, 20, 5))
+	layers[1].Slices.Push(rectangle(-5, 0, 20, 5))
+	slice.DetectSurfacesType(layers)
+	if err := slice.DetectBridgeAngles(layers, slice.Scale(0.5)); err != nil {
+		fmt.Println("Bridge angles should be detected", err)
+		t.Fail()
+	}
+	bridges := layers[1].Surfaces.Filter(slice.StBottomBridge)
+	if len(bridges) != 1 || math.Abs(bridges.First().BridgeAngle) > 0.01 {
+		fmt.Println("Bridge should span between the pillars", len(bridges))
+		t.Fail()
+		return
+	}
+
+	// bridges are filled along their angle whatever the layer
+	params := slice.NewFillParamsForSurface(bridges.First(), 1, 1, math.Pi/4, slice.Scale(0.5))
+	params.DontConnect = true
+	lines, err := fillRegion(slice.FpRectilinear, bridges.First().PolygonEx, params)
+	vertical, horizontal := directions(lines)
+	if err != nil || !params.Bridge || vertical != 0 || horizontal != len(lines) {
+		fmt.Println("Bridge should be filled along the bridge angle", len(lines), vertical, horizontal, err)
+		t.Fail()
+	}
+}
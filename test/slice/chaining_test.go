@@ -0,0 +1,166 @@
+package slice_test
+
+import (
+	"fmt"
+	"goSlicer/slice"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPointIndex(t *testing.T) {
+	rnd := rand.New(rand.NewSource(7))
+	points := slice.NewPoints()
+	for i := 0; i < 500; i++ {
+		points.Push(randomPoint(rnd))
+	}
+	index := slice.NewPointIndex(points)
+	removed := make(map[int]bool)
+	for i := 0; i < 400; i++ {
+		query := randomPoint(rnd)
+		nearest, best := -1, 0.0
+		for id, point := range points {
+			if !removed[id] && (nearest == -1 || query.DistanceTo(point) < best) {
+				nearest, best = id, query.DistanceTo(point)
+			}
+		}
+		if found := index.Nearest(query); found != nearest && query.DistanceTo(points[found]) != best {
+			fmt.Println("Index should find the nearest point", i, found, nearest)
+			t.Fail()
+			return
+		}
+		index.Remove(nearest)
+		removed[nearest] = true
+	}
+	if index.Len() != 100 {
+		fmt.Println("Index should have 100 points left", index.Len())
+		t.Fail()
+	}
+}
+
+func TestPointIndexFarQuery(t *testing.T) {
+	rnd := rand.New(rand.NewSource(11))
+	points := slice.NewPoints()
+	for i := 0; i < 200; i++ {
+		points.Push(randomPoint(rnd))
+	}
+	index := slice.NewPointIndex(points)
+	for i := 0; i < 50; i++ {
+		// queries 300mm away in every direction, far outside the grid
+		angle := rnd.Float64() * 2 * math.Pi
+		query := slice.NewPoint(slice.Scale(300)*math.Cos(angle), slice.Scale(300)*math.Sin(angle))
+		best := math.Inf(1)
+		for _, point := range points {
+			best = math.Min(best, query.DistanceTo(point))
+		}
+		if found := index.Nearest(query); found < 0 || query.DistanceTo(points[found]) != best {
+			fmt.Println("Index should find the nearest point from far away", i, found)
+			t.Fail()
+			return
+		}
+	}
+}
+
+// hatch will make count horizontal 10mm lines 1mm apart in shuffled order and direction
+func hatch(rnd *rand.Rand, count int) slice.Polylines {
+	plines := slice.NewPolylines()
+	for _, i := range rnd.Perm(count) {
+		y := slice.Scale(float64(i))
+		pline := polyline(0, y, slice.Scale(10), y)
+		if rnd.Intn(2) == 0 {
+			pline.MP.Reverse()
+		}
+		plines.Push(pline)
+	}
+	return plines
+}
+
+func TestChainPolylines(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	plines := hatch(rnd, 20)
+	start := slice.NewPoint(0, 0)
+
+	chained := slice.ChainPolylines(plines, start, false)
+	if len(chained) != 20 || slice.UnScale(chained.TravelLength(start)) > 19+1e-6 {
+		fmt.Println("Hatch should be chained as a zig zag", slice.UnScale(chained.TravelLength(start)))
+		t.Fail()
+	}
+
+	// without reversing every line starts on its own side
+	chained = slice.ChainPolylines(hatch(rnd, 20), start, true)
+	if len(chained) != 20 {
+		fmt.Println("Every polyline should be chained", len(chained))
+		t.Fail()
+	}
+}
+
+func TestChainPolylinesShorterThanNearestNeighbour(t *testing.T) {
+	rnd := rand.New(rand.NewSource(11))
+	for n := 0; n < 20; n++ {
+		plines := slice.NewPolylines()
+		for i := 0; i < 40; i++ {
+			a := randomPoint(rnd)
+			plines.Push(polyline(a.X, a.Y, a.X+slice.Scale(rnd.Float64()*20), a.Y+slice.Scale(rnd.Float64()*20)))
+		}
+		start := slice.NewPoint(0, 0)
+
+		// plain nearest neighbour by linear scan
+		greedy := 0.0
+		last := start
+		remaining := plines.GetCopy()
+		for !remaining.Empty() {
+			best, reverse, bestDist := 0, false, -1.0
+			for i, pline := range remaining {
+				if d := last.DistanceTo(pline.MP.Points.First()); bestDist < 0 || d < bestDist {
+					best, reverse, bestDist = i, false, d
+				}
+				if d := last.DistanceTo(pline.MP.Points.Last()); d < bestDist {
+					best, reverse, bestDist = i, true, d
+				}
+			}
+			greedy += bestDist
+			last = remaining[best].MP.Points.Last()
+			if reverse {
+				last = remaining[best].MP.Points.First()
+			}
+			remaining.EraseAt(best)
+		}
+
+		chained := slice.ChainPolylines(plines.GetCopy(), start, false)
+		if travel := chained.TravelLength(start); travel > greedy+slice.ScaledEpsilon {
+			fmt.Println("Chaining should not travel more than nearest neighbour", n, travel, greedy)
+			t.Fail()
+		}
+	}
+}
+
+func TestChainEntities(t *testing.T) {
+	loop := slice.NewExtrusionLoopFromPolygon(square(slice.Scale(20), 0, slice.Scale(5)), slice.ErPerimeter, slice.ElrDefault, 0.1, 0.5, 0.2)
+	path := extrusionPath(slice.ErInternalInfill, slice.Scale(10), 0, 0, 0)
+	start := slice.NewPoint(0, 0)
+
+	chained := slice.ChainEntities(slice.ExtrusionEntities{loop, path}, start, false)
+	if len(chained) != 2 || !chained[0].FirstPoint().CoincidesWith(start) ||
+		!chained[1].FirstPoint().CoincidesWith(slice.NewPoint(slice.Scale(20), 0)) {
+		fmt.Println("Path should be reversed and the loop start at its nearest vertex", chained[0].FirstPoint().Describe(), chained[1].FirstPoint().Describe())
+		t.Fail()
+	}
+	if slice.UnScale(chained.TravelLength(start)) != 10 || slice.UnScale(slice.ExtrusionEntities{loop, path}.TravelLength(start)) != 30 {
+		fmt.Println("Travel length should be reported", slice.UnScale(chained.TravelLength(start)))
+		t.Fail()
+	}
+}
+
+// BenchmarkChainPolylinesFarStart chains 2000 lines from a start 300mm away, like
+// concentric fills chained from the origin
+func BenchmarkChainPolylinesFarStart(b *testing.B) {
+	plines := hatch(rand.New(rand.NewSource(5)), 2000)
+	for _, pline := range plines {
+		pline.MP.Translate(slice.NewPoint(slice.Scale(300), slice.Scale(300)))
+	}
+	start := slice.NewPoint(0, 0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		slice.ChainPolylines(plines, start, false)
+	}
+}
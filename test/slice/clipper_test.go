@@ -61,3 +61,64 @@ func TestReversePolyPtLinks(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestUnion(t *testing.T) {
+	a := slice.NewPolygon()
+	a.MP.Points.Push(slice.NewPoint(0, 0), slice.NewPoint(100, 0), slice.NewPoint(100, 100), slice.NewPoint(0, 100))
+	b := slice.NewPolygon()
+	b.MP.Points.Push(slice.NewPoint(50, 50), slice.NewPoint(150, 50), slice.NewPoint(150, 150), slice.NewPoint(50, 150))
+
+	result, err := slice.Union(slice.Polygons{a, b})
+	if err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+	if len(result) != 1 || result[0].Area() != 17500 {
+		fmt.Printf("Expected one polygon with area 17500, got %d\n", len(result))
+		t.Fail()
+	}
+
+	// a ring made from two polygons should produce a hole
+	outer := slice.NewPolygon()
+	outer.MP.Points.Push(slice.NewPoint(0, 0), slice.NewPoint(300, 0), slice.NewPoint(300, 300), slice.NewPoint(0, 300))
+	inner := slice.NewPolygon()
+	inner.MP.Points.Push(slice.NewPoint(100, 100), slice.NewPoint(100, 200), slice.NewPoint(200, 200), slice.NewPoint(200, 100))
+
+	pgxs, err := slice.UnionEx(slice.Polygons{outer, inner})
+	if err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+	if len(pgxs) != 1 || len(pgxs[0].Holes) != 1 || pgxs[0].Area() != 80000 {
+		fmt.Println("Expected one PolygonEx with a single hole")
+		t.Fail()
+	}
+}
+
+func TestMinkowski(t *testing.T) {
+	pattern := slice.NewPolygon()
+	pattern.MP.Points.Push(slice.NewPoint(-10, -10), slice.NewPoint(10, -10), slice.NewPoint(10, 10), slice.NewPoint(-10, 10))
+
+	path := slice.NewPolyline()
+	path.MP.Points.Push(slice.NewPoint(0, 0), slice.NewPoint(100, 0))
+	swept, err := slice.MinkowskiSumPolyline(pattern, path)
+	if err != nil || len(swept) != 1 || swept[0].Area() != 120*20 {
+		fmt.Println("Sweeping a square along a line should make a 120x20 rectangle", err)
+		t.Fail()
+	}
+
+	closed := slice.NewPolygon()
+	closed.MP.Points.Push(slice.NewPoint(0, 0), slice.NewPoint(100, 0), slice.NewPoint(100, 100), slice.NewPoint(0, 100))
+	sum, err := slice.MinkowskiSum(pattern, closed)
+	if err != nil || len(sum) != 1 || sum[0].Area() != 120*120 {
+		fmt.Println("Sum of a square path should be a filled 120x120 square", err)
+		t.Fail()
+	}
+
+	diff, err := slice.MinkowskiDiff(pattern, closed)
+	if err != nil || len(diff) != 1 || diff[0].Area() != 120*120 ||
+		!diff[0].ContainsPoint(slice.NewPoint(50, 50)) || diff[0].ContainsPoint(slice.NewPoint(115, 50)) {
+		fmt.Println("Difference should be the 120x120 no fit polygon around the path", err)
+		t.Fail()
+	}
+}
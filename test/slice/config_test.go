@@ -0,0 +1,185 @@
+package slice_test
+
+import (
+	"errors"
+	"fmt"
+	"goSlicer/slice"
+	"strings"
+	"testing"
+)
+
+func TestConfigDefaults(t *testing.T) {
+	cfg := slice.NewFullPrintConfig()
+	if cfg.LayerHeight != 0.3 || cfg.Perimeters != 3 || cfg.FillDensity != 20 || cfg.FillPattern != slice.FpHoneycomb {
+		fmt.Println("bad defaults", cfg.LayerHeight, cfg.Perimeters, cfg.FillDensity, cfg.FillPattern)
+		t.Fail()
+	}
+	if !cfg.ExternalPerimeterSpeed.Percent || cfg.ExternalPerimeterSpeed.GetAbsValue(cfg.PerimeterSpeed) != 30 {
+		fmt.Println("external perimeter speed should be 50% of 60, got", cfg.ExternalPerimeterSpeed)
+		t.Fail()
+	}
+	if len(cfg.BedShape) != 4 || cfg.StartGCode != "G28 ; home all axes\nG1 Z5 F5000 ; lift nozzle\n" {
+		fmt.Println("bad printer defaults", cfg.BedShape, cfg.StartGCode)
+		t.Fail()
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Println("defaults should be valid:", err)
+		t.Fail()
+	}
+}
+
+func TestConfigSetGet(t *testing.T) {
+	cfg := slice.NewFullPrintConfig()
+	for key, value := range map[string]string{
+		"fill_density":       "35%",
+		"first_layer_height": "80%",
+		"nozzle_diameter":    "0.4,0.6",
+		"seam_position":      "rear",
+		"gcode_flavor":       "klipper",
+		"wipe":               "1,0",
+		"bed_shape":          "0x0,250x0,250x210,0x210",
+		"post_process":       "/usr/bin/a;/usr/bin/b",
+	} {
+		if err := cfg.Set(key, value); err != nil {
+			fmt.Println("couldn't set", key, err)
+			t.Fail()
+		}
+		if got, _ := cfg.Get(key); got != value {
+			fmt.Println(key, "should be", value, "got", got)
+			t.Fail()
+		}
+	}
+	if cfg.SeamPosition != slice.SeamRear || cfg.GCodeFlavor != slice.GfKlipper || cfg.NozzleDiameter[1] != 0.6 {
+		fmt.Println("typed fields were not set", cfg.SeamPosition, cfg.GCodeFlavor, cfg.NozzleDiameter)
+		t.Fail()
+	}
+
+	if err := cfg.Set("no_such_option", "1"); !errors.Is(err, slice.ErrUnknownOption) {
+		fmt.Println("expected an unknown option, got", err)
+		t.Fail()
+	}
+	err := cfg.Set("fill_pattern", "zigzag")
+	if !errors.Is(err, slice.ErrInvalidOptionValue) || !strings.Contains(err.Error(), "fill_pattern") || !strings.Contains(err.Error(), "honeycomb") {
+		fmt.Println("expected the enum values in the error, got", err)
+		t.Fail()
+	}
+}
+
+func TestConfigEnumValues(t *testing.T) {
+	for key, def := range slice.PrintConfigDef {
+		if def.Type != slice.CoEnum {
+			continue
+		}
+		cfg := slice.NewFullPrintConfig()
+		for _, value := range def.EnumValues {
+			if err := cfg.Set(key, value); err != nil {
+				fmt.Println(key, "can't be", value, err)
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := slice.NewFullPrintConfig()
+	cfg.Set("fill_density", "150%")
+	cfg.Set("layer_height", "0.8")
+	cfg.Set("use_firmware_retraction", "1")
+	cfg.Set("gcode_flavor", "mach3")
+	err := cfg.Validate()
+	if !errors.Is(err, slice.ErrInvalidConfig) {
+		fmt.Println("expected an invalid config, got", err)
+		t.FailNow()
+	}
+	for _, problem := range []string{"fill_density = \"150%\" is above the maximum of 100", "layer_height 0.8 can't be greater than the nozzle diameter 0.5", "use_firmware_retraction", "fill_pattern honeycomb"} {
+		if !strings.Contains(err.Error(), problem) {
+			fmt.Println("expected", problem, "in", err)
+			t.Fail()
+		}
+	}
+}
+
+func TestConfigValidateFirmwareRetractionWipe(t *testing.T) {
+	cfg := slice.NewFullPrintConfig()
+	cfg.Set("gcode_flavor", "repetier")
+	cfg.Set("use_firmware_retraction", "1")
+	if err := cfg.Validate(); err != nil {
+		fmt.Println("repetier should retract in the firmware", err)
+		t.Fail()
+	}
+	cfg.Set("wipe", "0,1")
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "not compatible with wipe") {
+		fmt.Println("expected wipe to be rejected with firmware retraction, got", err)
+		t.Fail()
+	}
+}
+
+func TestConfigIniSlic3rValues(t *testing.T) {
+	ini := `gcode_flavor = sailfish
+fill_pattern = stars
+external_fill_pattern = alignedrectilinear
+`
+	cfg := slice.NewFullPrintConfig()
+	if err := cfg.ReadIni(strings.NewReader(ini)); err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+	if cfg.GCodeFlavor != slice.GfSailfish || cfg.FillPattern != slice.FpStars || cfg.ExternalFillPattern != slice.FpAlignedRectilinear {
+		fmt.Println("bad options", cfg.GCodeFlavor, cfg.FillPattern, cfg.ExternalFillPattern)
+		t.Fail()
+	}
+}
+
+func TestConfigIni(t *testing.T) {
+	ini := `# a Slic3r profile
+layer_height = 0.2
+; comment
+fill_pattern = gyroid
+retract_length = 1.5,3
+start_gcode = G28\nM109 S[temperature] ; wait\n
+compatible_printers_condition = nozzle_diameter[0]==0.4
+`
+	cfg := slice.NewFullPrintConfig()
+	if err := cfg.ReadIni(strings.NewReader(ini)); err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+	if cfg.LayerHeight != 0.2 || cfg.FillPattern != slice.FpGyroid || cfg.RetractLength[1] != 3 || cfg.StartGCode != "G28\nM109 S[temperature] ; wait\n" {
+		fmt.Println("bad options", cfg.LayerHeight, cfg.FillPattern, cfg.RetractLength, cfg.StartGCode)
+		t.Fail()
+	}
+	if cfg.Extra["compatible_printers_condition"] != "nozzle_diameter[0]==0.4" {
+		fmt.Println("unknown options should be kept", cfg.Extra)
+		t.Fail()
+	}
+	if cfg.NewExtruder(1).RetractLength != 3 || cfg.NewExtruder(1).FilamentDiameter != 3 {
+		fmt.Println("extruder 1 should retract 3 with the first filament diameter")
+		t.Fail()
+	}
+
+	out := new(strings.Builder)
+	cfg.WriteTo(out)
+	again := slice.NewFullPrintConfig()
+	if err := again.ReadIni(strings.NewReader(out.String())); err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+	for _, key := range cfg.Keys() {
+		a, _ := cfg.Get(key)
+		b, _ := again.Get(key)
+		if a != b {
+			fmt.Println(key, "changed from", a, "to", b)
+			t.Fail()
+		}
+	}
+	if again.Extra["compatible_printers_condition"] != cfg.Extra["compatible_printers_condition"] {
+		fmt.Println("unknown options should be saved back")
+		t.Fail()
+	}
+
+	err := cfg.ReadIni(strings.NewReader("perimeters = 3\nperimeters = many\n"))
+	if err == nil || !strings.HasPrefix(err.Error(), "line 2:") {
+		fmt.Println("expected the line of the bad value, got", err)
+		t.Fail()
+	}
+}
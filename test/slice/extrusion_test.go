@@ -0,0 +1,142 @@
+package slice_test
+
+import (
+	"fmt"
+	"goSlicer/slice"
+	"testing"
+)
+
+func extrusionPath(role slice.ExtrusionRole, points ...float64) *slice.ExtrusionPath {
+	path := slice.NewExtrusionPath(role, 0.05, 450, 200)
+	path.Polyline = polyline(points...)
+	return path
+}
+
+func TestExtrusionPath(t *testing.T) {
+	path := extrusionPath(slice.ErPerimeter, 0, 0, 100, 0, 100, 100)
+	if path.Length() != 200 || !path.GetRole().IsPerimeter() || path.GetRole().IsInfill() {
+		fmt.Println("Path has the wrong length or role", path.Length(), path.GetRole())
+		t.Fail()
+	}
+
+	clone := path.Clone()
+	clone.Reverse()
+	if !clone.FirstPoint().CoincidesWith(slice.NewPoint(100, 100)) || !path.FirstPoint().CoincidesWith(slice.NewPoint(0, 0)) {
+		fmt.Println("Reversing a clone should leave the original alone")
+		t.Fail()
+	}
+
+	inside, err := path.Intersect(slice.PolygonExs{squareWithHole()})
+	if err != nil || len(inside) != 1 || inside[0].Length() != 200 || inside[0].MM3PerMM != path.MM3PerMM {
+		fmt.Println("Intersecting a path should keep its flow", err)
+		t.Fail()
+	}
+}
+
+func TestExtrusionLoop(t *testing.T) {
+	loop := slice.NewExtrusionLoopFromPolygon(square(0, 0, 100), slice.ErExternalPerimeter, slice.ElrDefault, 0.05, 450, 200)
+	if loop.Length() != 400 || !loop.IsCounterClockwise() || loop.CanReverse() {
+		fmt.Println("Loop around a square has the wrong length", loop.Length())
+		t.Fail()
+	}
+
+	if !loop.MakeClockwise() || loop.IsCounterClockwise() {
+		fmt.Println("Loop should have been made clockwise")
+		t.Fail()
+	}
+	loop.MakeCounterClockwise()
+
+	// splitting a single path loop only rotates it
+	if !loop.SplitAtVertex(slice.NewPoint(100, 100)) || len(loop.Paths) != 1 ||
+		!loop.FirstPoint().CoincidesWith(slice.NewPoint(100, 100)) || loop.Length() != 400 {
+		fmt.Println("Loop should start at the split vertex", loop.AsPolyline().Describe())
+		t.Fail()
+	}
+	if loop.SplitAtVertex(slice.NewPoint(50, 50)) {
+		fmt.Println("A point that is not a vertex cannot be split at")
+		t.Fail()
+	}
+
+	// a loop of two paths is split into three
+	loop = slice.NewExtrusionLoop(slice.ElrDefault,
+		extrusionPath(slice.ErPerimeter, 0, 0, 100, 0, 100, 100),
+		extrusionPath(slice.ErOverhangPerimeter, 100, 100, 0, 100, 0, 0))
+	loop.SplitAt(slice.NewPoint(50, 120))
+	if len(loop.Paths) != 3 || !loop.FirstPoint().CoincidesWith(slice.NewPoint(50, 100)) ||
+		loop.Paths[0].Role != slice.ErOverhangPerimeter || loop.Length() != 400 {
+		fmt.Println("Loop should start on the nearest edge", loop.AsPolyline().Describe())
+		t.Fail()
+	}
+
+	clipped := loop.ClipEnd(75)
+	if clipped.Length() != 325 || loop.Length() != 400 {
+		fmt.Println("Clipping the end of a loop has the wrong length", clipped.Length())
+		t.Fail()
+	}
+}
+
+func TestExtrusionEntityCollection(t *testing.T) {
+	collection := slice.NewExtrusionEntityCollection(
+		extrusionPath(slice.ErGapFill, 300, 0, 200, 0),
+		extrusionPath(slice.ErGapFill, 0, 0, 100, 0),
+		slice.NewExtrusionLoopFromPolygon(square(400, 0, 100), slice.ErPerimeter, slice.ElrDefault, 0.05, 450, 200),
+	)
+	if collection.GetRole() != slice.ErMixed || collection.Length() != 600 {
+		fmt.Println("Collection has the wrong role or length", collection.GetRole(), collection.Length())
+		t.Fail()
+	}
+
+	chained := collection.ChainedPathFrom(slice.NewPoint(0, 0), false)
+	expected := []*slice.Point{slice.NewPoint(0, 0), slice.NewPoint(200, 0), slice.NewPoint(400, 0)}
+	for i, entity := range chained.Entities {
+		if !entity.FirstPoint().CoincidesWith(expected[i]) {
+			fmt.Printf("Entity %d should start at %s not %s\n", i, expected[i].Describe(), entity.FirstPoint().Describe())
+			t.Fail()
+		}
+	}
+	if !collection.Entities.First().FirstPoint().CoincidesWith(slice.NewPoint(300, 0)) {
+		fmt.Println("Chaining should not change the original collection")
+		t.Fail()
+	}
+
+	nested := slice.NewExtrusionEntityCollection(collection, extrusionPath(slice.ErSkirt, 0, 0, 0, 10))
+	if nested.ItemsCount() != 4 || len(nested.Flatten().Entities) != 4 {
+		fmt.Println("Nested collection should hold 4 items", nested.ItemsCount())
+		t.Fail()
+	}
+}
+
+func TestPerimeterExtrusions(t *testing.T) {
+	island := slice.NewPolygonEx()
+	island.Contour = square(0, 0, slice.Scale(10))
+	pg := slice.NewPerimeterGenerator(slice.PolygonExs{island}, 0, 2, slice.Scale(0.5), slice.Scale(0.45))
+	if err := pg.Process(); err != nil || len(pg.Extrusions.Entities) != 1 {
+		fmt.Println("One island should give one collection of loops", err)
+		t.FailNow()
+	}
+
+	loops := pg.Extrusions.Entities[0].(*slice.ExtrusionEntityCollection)
+	if !loops.NoSort || len(loops.Entities) != 2 {
+		fmt.Println("The loops should keep their print order")
+		t.FailNow()
+	}
+	for i, role := range []slice.ExtrusionRole{slice.ErPerimeter, slice.ErExternalPerimeter} {
+		loop := loops.Entities[i].(*slice.ExtrusionLoop)
+		if loop.GetRole() != role || loop.Paths[0].Width != slice.Scale(0.5) {
+			fmt.Println("Loop", i, "should be a", role, "got", loop.GetRole())
+			t.Fail()
+		}
+	}
+}
+
+func TestExtrusionLoopEmpty(t *testing.T) {
+	for _, loop := range []*slice.ExtrusionLoop{
+		slice.NewExtrusionLoop(slice.ElrDefault),
+		slice.NewExtrusionLoop(slice.ElrDefault, extrusionPath(slice.ErPerimeter)),
+	} {
+		if pg := loop.Polygon(); !pg.MP.Points.Empty() {
+			fmt.Println("A loop without points should give an empty polygon")
+			t.Fail()
+		}
+	}
+}
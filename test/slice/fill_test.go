@@ -0,0 +1,246 @@
+package slice_test
+
+import (
+	"fmt"
+	"goSlicer/slice"
+	"testing"
+)
+
+// fillRegion will fill a square 10mm region with the pattern
+func fillRegion(pattern slice.FillPattern, pgx *slice.PolygonEx, params *slice.FillParams) (slice.Polylines, error) {
+	fill, err := slice.NewFill(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return fill.FillSurface(pgx, params)
+}
+
+// directions will count the vertical and horizontal segments
+func directions(plines slice.Polylines) (vertical int, horizontal int) {
+	for _, pline := range plines {
+		for _, line := range pline.Lines() {
+			if line.A.X == line.B.X {
+				vertical++
+			} else if line.A.Y == line.B.Y {
+				horizontal++
+			}
+		}
+	}
+	return vertical, horizontal
+}
+
+func TestFillRectilinear(t *testing.T) {
+	island := slice.NewPolygonEx()
+	island.Contour = square(0, 0, slice.Scale(10))
+
+	params := slice.NewFillParams(0, 1, 0, slice.Scale(0.5))
+	params.DontConnect = true
+	even, err := fillRegion(slice.FpRectilinear, island, params)
+	vertical, horizontal := directions(even)
+	if err != nil || len(even) != 21 || vertical != 0 || horizontal != 21 {
+		fmt.Println("Solid rectilinear should give 21 parallel lines", len(even), vertical, horizontal, err)
+		t.Fail()
+	}
+
+	// lines turn a quarter turn every layer
+	params.LayerID = 1
+	odd, _ := fillRegion(slice.FpRectilinear, island, params)
+	vertical, horizontal = directions(odd)
+	if len(odd) != 21 || vertical != 21 || horizontal != 0 {
+		fmt.Println("Odd layers should fill the other way", len(odd), vertical, horizontal)
+		t.Fail()
+	}
+
+	// connected lines make a single zig zag
+	params.DontConnect = false
+	params.Density = 0.5
+	connected, _ := fillRegion(slice.FpRectilinear, island, params)
+	if len(connected) != 1 {
+		fmt.Println("Lines of a square should all be connected", len(connected))
+		t.Fail()
+	}
+
+	// connections never cross the hole, only the ends poke into it
+	pgx := squareWithHole()
+	pgx.Scale(slice.Scale(0.1))
+	filled, _ := fillRegion(slice.FpRectilinear, pgx, params)
+	hole := pgx.Holes[0].GetCopy()
+	hole.MakeCounterClockwise()
+	inner, _ := slice.Offset(slice.Polygons{hole}, -slice.Scale(0.25))
+	crossing, err := slice.IntersectionPolylines(filled, inner)
+	if err != nil || len(filled) < 2 || !crossing.Empty() {
+		fmt.Println("Fill should go around the hole", len(filled), len(crossing), err)
+		t.Fail()
+	}
+}
+
+func TestFillGridAndLine(t *testing.T) {
+	island := slice.NewPolygonEx()
+	island.Contour = square(0, 0, slice.Scale(10))
+	params := slice.NewFillParams(1, 0.5, 0, slice.Scale(0.5))
+	params.DontConnect = true
+
+	grid, err := fillRegion(slice.FpGrid, island, params)
+	vertical, horizontal := directions(grid)
+	if err != nil || vertical != 5 || horizontal != 5 {
+		fmt.Println("Grid should fill both ways at half the density each", vertical, horizontal, err)
+		t.Fail()
+	}
+
+	line, err := fillRegion(slice.FpLine, island, params)
+	vertical, horizontal = directions(line)
+	if err != nil || len(line) != 11 || vertical != 6 || horizontal != 0 {
+		fmt.Println("Every other line should lean", len(line), vertical, horizontal, err)
+		t.Fail()
+	}
+	params.DontConnect = false
+	if line, _ = fillRegion(slice.FpLine, island, params); len(line) != 1 {
+		fmt.Println("Leaning lines should be connected", len(line))
+		t.Fail()
+	}
+
+	if _, err := slice.NewFill(slice.FillPattern(-1)); err != slice.ErrUnknownFillPattern {
+		fmt.Println("Unknown patterns should fail")
+		t.Fail()
+	}
+}
+
+func TestFillPatterns(t *testing.T) {
+	pgx := squareWithHole()
+	pgx.Scale(slice.Scale(0.2))
+	grown, _ := slice.Offset(pgx.Polygons(), slice.Scale(0.45))
+	patterns := []slice.FillPattern{slice.FpCubic, slice.FpTriangles, slice.FpStars, slice.FpHoneycomb, slice.Fp3DHoneycomb,
+		slice.FpGyroid, slice.FpHilbertCurve, slice.FpArchimedeanChords, slice.FpOctagramSpiral}
+	for _, pattern := range patterns {
+		for _, dontConnect := range []bool{true, false} {
+			params := slice.NewFillParams(3, 0.2, 0, slice.Scale(0.45))
+			params.Z = 0.8
+			params.DontConnect = dontConnect
+			plines, err := fillRegion(pattern, pgx, params)
+			length := 0.0
+			for _, pline := range plines {
+				length += slice.UnScale(pline.MP.Length())
+			}
+			// 300mm2 at 20% with 0.45mm lines is about 133mm of infill
+			if err != nil || plines.Empty() || length < 100 || length > 220 {
+				fmt.Println("Fill should cover the region at the given density", pattern, dontConnect, len(plines), length, err)
+				t.Fail()
+			}
+			outside, _ := slice.DiffPolylines(plines, grown)
+			if !outside.Empty() {
+				fmt.Println("Fill should stay inside the region", pattern, dontConnect, len(outside))
+				t.Fail()
+			}
+		}
+	}
+
+	// 3D patterns change with Z
+	for _, pattern := range []slice.FillPattern{slice.FpGyroid, slice.Fp3DHoneycomb} {
+		params := slice.NewFillParams(3, 0.2, 0, slice.Scale(0.45))
+		params.DontConnect = true
+		params.Z = 0.8
+		low, _ := fillRegion(pattern, pgx, params)
+		params.Z = 1.4
+		high, _ := fillRegion(pattern, pgx, params)
+		if len(low) == len(high) && low.First().MP.Points.First().CoincidesWith(high.First().MP.Points.First()) {
+			fmt.Println("Fill should change with the layer height", pattern)
+			t.Fail()
+		}
+	}
+}
+
+// sameFill will tell if two fills have the same lines
+func sameFill(a slice.Polylines, b slice.Polylines) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i].MP.Points) != len(b[i].MP.Points) {
+			return false
+		}
+		for j, point := range a[i].MP.Points {
+			if !point.CoincidesWith(b[i].MP.Points[j]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestFillLayerAngle(t *testing.T) {
+	pgx := squareWithHole()
+	pgx.Scale(slice.Scale(0.2))
+	fill := func(pattern slice.FillPattern, layerID int) slice.Polylines {
+		params := slice.NewFillParams(layerID, 0.2, 0, slice.Scale(0.45))
+		params.Z = 0.8
+		plines, err := fillRegion(pattern, pgx, params)
+		if err != nil {
+			fmt.Println(err)
+			t.FailNow()
+		}
+		return plines
+	}
+	for _, pattern := range []slice.FillPattern{slice.FpHilbertCurve, slice.FpArchimedeanChords, slice.FpOctagramSpiral} {
+		if sameFill(fill(pattern, 2), fill(pattern, 3)) || !sameFill(fill(pattern, 2), fill(pattern, 4)) {
+			fmt.Println("Fill should turn on every other layer", pattern)
+			t.Fail()
+		}
+	}
+
+	// as in Slic3r these keep their angle, cubic needs it for the cubes to stack
+	for _, pattern := range []slice.FillPattern{slice.FpAlignedRectilinear, slice.FpCubic, slice.FpTriangles, slice.FpStars} {
+		if !sameFill(fill(pattern, 2), fill(pattern, 3)) {
+			fmt.Println("Fill should not turn between layers", pattern)
+			t.Fail()
+		}
+	}
+
+	// stars shift one set of lines off the triangles
+	if sameFill(fill(slice.FpStars, 2), fill(slice.FpTriangles, 2)) {
+		fmt.Println("Stars should differ from triangles")
+		t.Fail()
+	}
+}
+
+func TestFillConcentric(t *testing.T) {
+	island := slice.NewPolygonEx()
+	island.Contour = square(0, 0, slice.Scale(10))
+	params := slice.NewFillParams(0, 1, 0, slice.Scale(0.5))
+	loops, err := fillRegion(slice.FpConcentric, island, params)
+	if err != nil || len(loops) != 10 {
+		fmt.Println("Solid concentric fill of a 10mm square should give 10 loops", len(loops), err)
+		t.Fail()
+	}
+
+	// every loop starts next to where the previous one ended
+	for i := 1; i < len(loops); i++ {
+		travel := loops[i-1].MP.Points.Last().DistanceTo(loops[i].MP.Points.First())
+		if travel > slice.Scale(0.75) {
+			fmt.Println("Loops should be chained to minimize travel", i, slice.UnScale(travel))
+			t.Fail()
+		}
+	}
+
+	// loops are clipped so they don't close on themselves
+	for _, loop := range loops {
+		if loop.MP.Points.First().CoincidesWith(loop.MP.Points.Last()) {
+			fmt.Println("Loops should be clipped at the end", loop.Describe())
+			t.Fail()
+		}
+	}
+
+	// loops go around the hole
+	pgx := squareWithHole()
+	pgx.Scale(slice.Scale(0.1))
+	params.Density = 0.5
+	loops, _ = fillRegion(slice.FpConcentric, pgx, params)
+	// the outer loops follow the outline, so only the inside of the hole is off limits
+	hole := pgx.Holes[0].GetCopy()
+	hole.MakeCounterClockwise()
+	inside, _ := slice.Offset(slice.Polygons{hole}, -slice.Scale(0.01))
+	crossing, _ := slice.IntersectionPolylines(loops, inside)
+	if loops.Empty() || !crossing.Empty() {
+		fmt.Println("Concentric fill should go around the hole", len(loops), len(crossing))
+		t.Fail()
+	}
+}
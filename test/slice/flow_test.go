@@ -0,0 +1,84 @@
+package slice_test
+
+import (
+	"fmt"
+	"goSlicer/slice"
+	"math"
+	"testing"
+)
+
+func TestFlow(t *testing.T) {
+	flow, err := slice.NewFlowFromConfigWidth(slice.FrPerimeter, slice.FloatOrPercent{Value: 0.5}, 0.4, 0.2, 0)
+	if err != nil || flow.Width != 0.5 || flow.Bridge {
+		fmt.Println("Absolute width should be used as is", err)
+		t.FailNow()
+	}
+	// rectangle with semicircles at the ends
+	if !closeTo(flow.Spacing(), 0.5-0.2*(1-math.Pi/4)) || !closeTo(flow.MM3PerMM(), 0.5*0.2-0.2*0.2*(1-math.Pi/4)) {
+		fmt.Println("Flow has the wrong spacing or area", flow.Spacing(), flow.MM3PerMM())
+		t.Fail()
+	}
+
+	percent, _ := slice.NewFlowFromConfigWidth(slice.FrPerimeter, slice.FloatOrPercent{Value: 250, Percent: true}, 0.4, 0.2, 0)
+	if !closeTo(percent.Width, 0.5) {
+		fmt.Println("Percent width should be relative to the layer height", percent.Width)
+		t.Fail()
+	}
+
+	// automatic widths are clamped per role
+	external, _ := slice.NewFlowFromConfigWidth(slice.FrExternalPerimeter, slice.FloatOrPercent{}, 0.4, 0.2, 0)
+	infill, _ := slice.NewFlowFromConfigWidth(slice.FrInfill, slice.FloatOrPercent{}, 0.4, 0.1, 0)
+	if !closeTo(external.Width, 0.44) || infill.Width <= 0.4*1.7 {
+		fmt.Println("Automatic widths are wrong", external.Width, infill.Width)
+		t.Fail()
+	}
+
+	bridge, _ := slice.NewFlowFromConfigWidth(slice.FrSolidInfill, slice.FloatOrPercent{}, 0.4, 0, 1)
+	if !bridge.Bridge || bridge.Width != 0.4 || bridge.Height != 0.4 || !closeTo(bridge.Spacing(), 0.45) ||
+		!closeTo(bridge.MM3PerMM(), math.Pi*0.04) {
+		fmt.Println("Bridge flow should be round", bridge.Width, bridge.MM3PerMM())
+		t.Fail()
+	}
+
+	if _, err := slice.NewFlowFromConfigWidth(slice.FrPerimeter, slice.FloatOrPercent{}, 0.4, 0, 0); err != slice.ErrInvalidFlowHeight {
+		fmt.Println("A flow without a height should fail")
+		t.Fail()
+	}
+
+	fromSpacing, _ := slice.NewFlowFromSpacing(flow.Spacing(), 0.4, 0.2, false)
+	if !closeTo(fromSpacing.Width, flow.Width) {
+		fmt.Println("Width from spacing should give back the width", fromSpacing.Width)
+		t.Fail()
+	}
+
+	// a whole number of lines fits the width
+	flow.SetSolidSpacing(10)
+	if lines := 10 / flow.Spacing(); !closeTo(lines, math.Round(lines)) {
+		fmt.Println("Solid spacing should fit a whole number of lines", lines)
+		t.Fail()
+	}
+}
+
+func TestPerimeterGeneratorFromFlows(t *testing.T) {
+	perimeter := slice.NewFlow(0.45, 0.2, 0.4, false)
+	external := slice.NewFlow(0.5, 0.2, 0.4, false)
+	island := slice.NewPolygonEx()
+	island.Contour = square(0, 0, slice.Scale(10))
+	pg := slice.NewPerimeterGeneratorFromFlows(slice.PolygonExs{island}, 0, 2, perimeter, external, perimeter)
+	if err := pg.Process(); err != nil || len(pg.Extrusions.Entities) != 1 {
+		fmt.Println("One island should give one collection of loops", err)
+		t.FailNow()
+	}
+
+	loops := pg.Extrusions.Entities[0].(*slice.ExtrusionEntityCollection)
+	if len(loops.Entities) != 2 || loops.Entities[1].GetRole() != slice.ErExternalPerimeter ||
+		loops.Entities[0].GetRole() != slice.ErPerimeter {
+		fmt.Println("External perimeter should be printed last")
+		t.FailNow()
+	}
+	path := loops.Entities[1].(*slice.ExtrusionLoop).Paths[0]
+	if path.MM3PerMM != external.MM3PerMM() || path.Width != external.ScaledWidth() {
+		fmt.Println("External perimeter should use the external flow")
+		t.Fail()
+	}
+}
@@ -0,0 +1,322 @@
+package slice_test
+
+import (
+	"fmt"
+	"goSlicer/slice"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// gcodeWriter will set up a writer with a single 1.75mm extruder
+func gcodeWriter(flavor slice.GCodeFlavor, relative bool) (*slice.GCodeWriter, *strings.Builder) {
+	var out strings.Builder
+	gw := slice.NewGCodeWriter(&out, flavor)
+	gw.UseRelativeEDistances = relative
+	gw.SetExtruders(slice.NewExtruder(0, 1.75))
+	return gw, &out
+}
+
+// lastE will find the E value of the last line moving the axis
+func lastE(gcode string, axis string) float64 {
+	lines := strings.Split(strings.TrimSpace(gcode), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		for _, field := range strings.Fields(lines[i]) {
+			if strings.HasPrefix(field, axis) {
+				val, _ := strconv.ParseFloat(field[1:], 64)
+				return val
+			}
+		}
+	}
+	return math.NaN()
+}
+
+func TestGCodeWriterPreamble(t *testing.T) {
+	tests := []struct {
+		flavor   slice.GCodeFlavor
+		relative bool
+		expected string
+	}{
+		{slice.GfMarlin, false, "G21 ; set units to millimeters\nG90 ; use absolute coordinates\nM82 ; use absolute distances for extrusion\nG92 E0\n"},
+		{slice.GfKlipper, true, "G21 ; set units to millimeters\nG90 ; use absolute coordinates\nM83 ; use relative distances for extrusion\n"},
+		{slice.GfMach3, false, "G21 ; set units to millimeters\nG90 ; use absolute coordinates\n"},
+		{slice.GfNoExtrusion, false, "G21 ; set units to millimeters\nG90 ; use absolute coordinates\n"},
+		{slice.GfSailfish, false, "G21 ; set units to millimeters\nG90 ; use absolute coordinates\n"},
+		{slice.GfRepetier, false, "G21 ; set units to millimeters\nG90 ; use absolute coordinates\nM82 ; use absolute distances for extrusion\nG92 E0\n"},
+	}
+	for _, test := range tests {
+		gw, out := gcodeWriter(test.flavor, test.relative)
+		if err := gw.Preamble(); err != nil || out.String() != test.expected {
+			fmt.Println("Wrong preamble", test.flavor, out.String(), err)
+			t.Fail()
+		}
+	}
+}
+
+func TestGCodeWriterFlavors(t *testing.T) {
+	tests := []struct {
+		flavor   slice.GCodeFlavor
+		write    func(gw *slice.GCodeWriter) error
+		expected string
+	}{
+		{slice.GfMarlin, func(gw *slice.GCodeWriter) error { return gw.SetTemperature(200, true, -1) },
+			"M109 S200 ; set temperature and wait for it to be reached\n"},
+		{slice.GfRepRap, func(gw *slice.GCodeWriter) error { return gw.SetTemperature(200, false, -1) },
+			"M104 S200 ; set temperature\n"},
+		{slice.GfTeacup, func(gw *slice.GCodeWriter) error { return gw.SetTemperature(200, true, -1) },
+			"M104 S200 ; set temperature\nM116 ; wait for temperature to be reached\n"},
+		{slice.GfMach3, func(gw *slice.GCodeWriter) error { return gw.SetTemperature(200, true, -1) },
+			"M109 P200 ; set temperature and wait for it to be reached\n"},
+		{slice.GfSmoothie, func(gw *slice.GCodeWriter) error { return gw.SetBedTemperature(60, true) },
+			"M190 S60 ; set bed temperature and wait for it to be reached\n"},
+		{slice.GfTeacup, func(gw *slice.GCodeWriter) error { return gw.SetBedTemperature(60, true) },
+			"M140 S60 ; set bed temperature\nM116 ; wait for bed temperature to be reached\n"},
+		{slice.GfMarlin, func(gw *slice.GCodeWriter) error { return gw.SetFan(50, false) }, "M106 S127.5\n"},
+		{slice.GfMach3, func(gw *slice.GCodeWriter) error { return gw.SetFan(100, false) }, "M106 P255\n"},
+		{slice.GfMarlin, func(gw *slice.GCodeWriter) error { gw.SetFan(50, false); return gw.SetFan(50, false) }, "M106 S127.5\n"},
+		{slice.GfTeacup, func(gw *slice.GCodeWriter) error { gw.SetFan(50, false); return gw.SetFan(0, false) }, "M106 S127.5\nM106 S0\n"},
+		{slice.GfSmoothie, func(gw *slice.GCodeWriter) error { gw.SetFan(50, false); return gw.SetFan(0, false) }, "M106 S127.5\nM107\n"},
+		{slice.GfMarlin, func(gw *slice.GCodeWriter) error { return gw.SetAcceleration(1000) }, "M204 S1000\n"},
+		{slice.GfKlipper, func(gw *slice.GCodeWriter) error { return gw.SetAcceleration(1000) }, "SET_VELOCITY_LIMIT ACCEL=1000\n"},
+		{slice.GfRepetier, func(gw *slice.GCodeWriter) error { return gw.SetAcceleration(1000) }, "M201 X1000 Y1000\nM202 X1000 Y1000\n"},
+		{slice.GfMakerWare, func(gw *slice.GCodeWriter) error { return gw.SetTemperature(200, true, -1) }, ""},
+		{slice.GfSailfish, func(gw *slice.GCodeWriter) error { gw.SetFan(50, false); return gw.SetFan(0, false) }, "M126\nM127\n"},
+		{slice.GfMachinekit, func(gw *slice.GCodeWriter) error { return gw.SetFan(100, false) }, "M106 P255\n"},
+		{slice.GfMachinekit, func(gw *slice.GCodeWriter) error {
+			gw.SetExtruders(slice.NewExtruder(0, 1.75), slice.NewExtruder(1, 1.75))
+			return gw.Toolchange(1)
+		}, "M6 T1\n"},
+		{slice.GfSailfish, func(gw *slice.GCodeWriter) error {
+			gw.SetExtruders(slice.NewExtruder(0, 1.75), slice.NewExtruder(1, 1.75))
+			return gw.Toolchange(1)
+		}, "M108 T1\n"},
+	}
+	for i, test := range tests {
+		gw, out := gcodeWriter(test.flavor, false)
+		if err := test.write(gw); err != nil || out.String() != test.expected {
+			fmt.Println("Wrong G-code for the flavor", i, test.flavor, out.String(), err)
+			t.Fail()
+		}
+	}
+
+	if flavor, err := slice.ParseGCodeFlavor("klipper"); err != nil || flavor != slice.GfKlipper {
+		fmt.Println("Flavors should be parsed by name", flavor, err)
+		t.Fail()
+	}
+	if _, err := slice.ParseGCodeFlavor("grbl"); err != slice.ErrUnknownGCodeFlavor {
+		fmt.Println("Unknown flavors should fail", err)
+		t.Fail()
+	}
+}
+
+func TestGCodeWriterExtrusion(t *testing.T) {
+	flow := slice.NewFlow(0.5, 0.2, 0.4, false)
+	path := slice.NewExtrusionPath(slice.ErPerimeter, flow.MM3PerMM(), flow.ScaledWidth(), slice.Scale(0.2))
+	path.Polyline = polyline(slice.Scale(5), 0, slice.Scale(15), 0, slice.Scale(15), slice.Scale(10))
+
+	// 20mm of extrusion over the cross section of the filament
+	expected := flow.MM3PerMM() * 20 / (1.75 * 1.75 * math.Pi / 4)
+	tests := []struct {
+		flavor     slice.GCodeFlavor
+		relative   bool
+		volumetric bool
+		axis       string
+		e          float64
+	}{
+		{slice.GfMarlin, false, false, "E", expected},
+		{slice.GfRepRap, true, false, "E", expected / 2},
+		{slice.GfSmoothie, false, true, "E", flow.MM3PerMM() * 20},
+		{slice.GfMach3, false, false, "A", expected},
+		{slice.GfMachinekit, false, false, "A", expected},
+	}
+	for _, test := range tests {
+		var out strings.Builder
+		gw := slice.NewGCodeWriter(&out, test.flavor)
+		gw.UseRelativeEDistances = test.relative
+		gw.UseVolumetricE = test.volumetric
+		gw.SetExtruders(slice.NewExtruder(0, 1.75))
+		if err := gw.ExtrudePath(path, 30, ""); err != nil || math.Abs(lastE(out.String(), test.axis)-test.e) > 1e-4 {
+			fmt.Println("Wrong extrusion", test.flavor, test.e, out.String(), err)
+			t.Fail()
+		}
+		if !strings.HasPrefix(out.String(), "G1 X5.000 Y0.000 F7800\nG1 F1800\nG1 X15.000 Y0.000 "+test.axis) {
+			fmt.Println("Path should start with a travel and the speed", test.flavor, out.String())
+			t.Fail()
+		}
+	}
+
+	// nothing is extruded without an extruder axis
+	gw, out := gcodeWriter(slice.GfNoExtrusion, false)
+	gw.ExtrudePath(path, 30, "")
+	gw.Retract(false)
+	if strings.Contains(out.String(), "E") || !strings.HasSuffix(out.String(), "G1 X15.000 Y10.000\n") {
+		fmt.Println("No extrusion flavor should only move", out.String())
+		t.Fail()
+	}
+}
+
+func TestGCodeWriterRetraction(t *testing.T) {
+	gw, out := gcodeWriter(slice.GfMarlin, false)
+	gw.Extruder.RetractLift = 0.4
+	gw.ExtrudeToXY(slice.NewPoint(10, 0), 1, "")
+	gw.Retract(false)
+	gw.Retract(false) // already retracted
+	gw.TravelToZ(0.2, "")
+	gw.Lift()
+	gw.TravelToZ(0.4, "") // within the lift
+	gw.Unlift()
+	gw.Unretract()
+
+	expected := "G1 X10.000 Y0.000 E1.00000\n" +
+		"G1 E-1.00000 F2400\n" +
+		"G1 Z0.200 F7800\n" +
+		"G1 Z0.600 F7800\n" +
+		"G1 Z0.400 F7800\n" +
+		"G1 E1.00000 F2400\n"
+	if out.String() != expected {
+		fmt.Println("Wrong retraction", out.String())
+		t.Fail()
+	}
+	if z := gw.Position().Z; z != 0.4 {
+		fmt.Println("Lift should be undone to the new layer", z)
+		t.Fail()
+	}
+}
+
+func TestGCodeNeedsRetraction(t *testing.T) {
+	gw, _ := gcodeWriter(slice.GfMarlin, false)
+	gcode := slice.NewGCode(gw)
+	layer := slice.NewLayer(0, 0.1, 0.2, 0.2)
+	layer.Slices.Push(rectangle(0, 0, 20, 20), rectangle(30, 0, 50, 20))
+	layer.SupportIslands.Push(rectangle(60, 0, 80, 20))
+	gcode.Layer = layer
+
+	tests := []struct {
+		travel   *slice.Polyline
+		role     slice.ExtrusionRole
+		crossing bool
+		retract  bool
+	}{
+		{polyline(slice.Scale(5), slice.Scale(5), slice.Scale(6), slice.Scale(5)), slice.ErPerimeter, false, false},
+		{polyline(slice.Scale(5), slice.Scale(5), slice.Scale(15), slice.Scale(5)), slice.ErPerimeter, false, true},
+		// the slices hold the perimeters, without surfaces there is no telling
+		{polyline(slice.Scale(5), slice.Scale(5), slice.Scale(15), slice.Scale(5)), slice.ErPerimeter, true, true},
+		{polyline(slice.Scale(5), slice.Scale(5), slice.Scale(35), slice.Scale(5)), slice.ErPerimeter, true, true},
+		{polyline(slice.Scale(65), slice.Scale(5), slice.Scale(75), slice.Scale(5)), slice.ErSupportMaterial, false, false},
+		{polyline(slice.Scale(65), slice.Scale(5), slice.Scale(75), slice.Scale(5)), slice.ErPerimeter, false, true},
+	}
+	for i, test := range tests {
+		gcode.OnlyRetractWhenCrossingPerimeters = test.crossing
+		if gcode.NeedsRetraction(test.travel, test.role) != test.retract {
+			fmt.Println("Wrong retraction decision", i, test.retract)
+			t.Fail()
+		}
+	}
+
+	// inside the infill strings are hidden, unless there is no infill
+	gcode.OnlyRetractWhenCrossingPerimeters = true
+	layer.Surfaces.Push(slice.NewSurface(rectangle(2, 2, 18, 18), slice.StInternal))
+	inside := polyline(slice.Scale(5), slice.Scale(5), slice.Scale(15), slice.Scale(5))
+	across := polyline(slice.Scale(1), slice.Scale(5), slice.Scale(15), slice.Scale(5))
+	if gcode.NeedsRetraction(inside, slice.ErPerimeter) || !gcode.NeedsRetraction(across, slice.ErPerimeter) {
+		fmt.Println("Only travels crossing the perimeters should retract")
+		t.Fail()
+	}
+	gcode.FillDensity = 0
+	if !gcode.NeedsRetraction(inside, slice.ErPerimeter) {
+		fmt.Println("Travels should retract without infill to hide them")
+		t.Fail()
+	}
+}
+
+func TestGCodeFirmwareRetraction(t *testing.T) {
+	gw, out := gcodeWriter(slice.GfMarlin, false)
+	gw.UseFirmwareRetraction = true
+	gw.Extruder.RetractLength = 0
+	gw.ExtrudeToXY(slice.NewPoint(10, 0), 1, "")
+	gw.Retract(false)
+	gw.Unretract()
+	expected := "G1 X10.000 Y0.000 E1.00000\nG10 ; retract\nG11 ; unretract\nG92 E0\n"
+	if out.String() != expected {
+		fmt.Println("Firmware retraction should use G10 and G11", out.String())
+		t.Fail()
+	}
+}
+
+func TestGCodeLiftRange(t *testing.T) {
+	tests := []struct {
+		z      float64
+		lifted bool
+	}{
+		{0.2, false},
+		{1.2, true},
+		{2.2, false},
+	}
+	for _, test := range tests {
+		gw, out := gcodeWriter(slice.GfMarlin, false)
+		gw.Extruder.RetractLift = 0.4
+		gw.Extruder.RetractLiftAbove = 1
+		gw.Extruder.RetractLiftBelow = 2
+		gw.TravelToZ(test.z, "")
+		out.Reset()
+		gw.Lift()
+		if (out.Len() > 0) != test.lifted {
+			fmt.Println("Lift should only happen within its Z range", test.z, out.String())
+			t.Fail()
+		}
+	}
+}
+
+func TestGCodeWipe(t *testing.T) {
+	gw, out := gcodeWriter(slice.GfMarlin, false)
+	gw.Extruder.Wipe = true
+	gw.Extruder.RetractBeforeWipe = 0.2
+	gw.Extruder.RetractRestartExtra = 0.5
+	gcode := slice.NewGCode(gw)
+
+	path := slice.NewExtrusionPath(slice.ErPerimeter, 0.1, slice.Scale(0.5), slice.Scale(0.2))
+	path.Polyline = polyline(0, 0, slice.Scale(20), 0)
+	gcode.ExtrudePath(path, 30, "")
+	gcode.TravelTo(slice.NewPoint(0, slice.Scale(10)), slice.ErPerimeter, "")
+
+	// 1.6mm retracted at 40mm/s while moving at 104mm/s is a 4.16mm wipe
+	if !strings.Contains(out.String(), "G1 X15.840 Y0.000 E") || math.Abs(gw.Extruder.Retracted-2) > 1e-9 {
+		fmt.Println("Wipe should retract along the last path", gw.Extruder.Retracted, out.String())
+		t.Fail()
+	}
+
+	// E was reset after retracting, the restart extra is pushed in on top of the retraction
+	gcode.Unretract()
+	if math.Abs(gw.Extruder.E-2.5) > 1e-9 {
+		fmt.Println("Unretract should push the restart extra", gw.Extruder.E)
+		t.Fail()
+	}
+}
+
+func TestGCodeWipeFirmwareRetraction(t *testing.T) {
+	gw, out := gcodeWriter(slice.GfMarlin, false)
+	gw.UseFirmwareRetraction = true
+	gw.Extruder.Wipe = true
+	gcode := slice.NewGCode(gw)
+
+	path := slice.NewExtrusionPath(slice.ErPerimeter, 0.1, slice.Scale(0.5), slice.Scale(0.2))
+	path.Polyline = polyline(0, 0, slice.Scale(20), 0)
+	gcode.ExtrudePath(path, 30, "")
+	gcode.TravelTo(slice.NewPoint(0, slice.Scale(10)), slice.ErPerimeter, "")
+	gcode.Unretract()
+
+	// the wipe moves without E between G10 and G11, which restore each other
+	gcodeText := out.String()
+	start, end := strings.Index(gcodeText, "G10"), strings.Index(gcodeText, "G11")
+	if start < 0 || end < start || !strings.Contains(gcodeText[start:end], "G1 X14.800 Y0.000\n") {
+		fmt.Println("Wipe should move between G10 and G11", gcodeText)
+		t.FailNow()
+	}
+	for _, line := range strings.Split(gcodeText[start:end], "\n") {
+		if strings.HasPrefix(line, "G1 ") && strings.Contains(line, " E") {
+			fmt.Println("Wipe should not move E with firmware retraction", line)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package slice_test
+
+import (
+	"fmt"
+	"goSlicer/slice"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func polyline(points ...float64) *slice.Polyline {
+	pl := slice.NewPolyline()
+	for i := 0; i+1 < len(points); i += 2 {
+		pl.MP.Points.Push(slice.NewPoint(points[i], points[i+1]))
+	}
+	return pl
+}
+
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestLinesCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		points   []float64
+		polyline int
+		polygon  int
+	}{
+		{"segment", []float64{0, 0, 10, 0}, 1, 2},
+		{"triangle", []float64{0, 0, 10, 0, 10, 10}, 2, 3},
+		{"square", []float64{0, 0, 10, 0, 10, 10, 0, 10}, 3, 4},
+		{"pentagon", []float64{0, 0, 10, 0, 15, 5, 10, 10, 0, 10}, 4, 5},
+	}
+
+	for _, test := range tests {
+		pl := polyline(test.points...)
+		lines := pl.Lines()
+		if len(lines) != test.polyline {
+			fmt.Printf("%s: polyline has %d lines, expected %d\n", test.name, len(lines), test.polyline)
+			t.Fail()
+			continue
+		}
+		for i, line := range lines {
+			if line.A != pl.MP.Points[i] || line.B != pl.MP.Points[i+1] {
+				fmt.Printf("%s: polyline line %d does not join points %d and %d\n", test.name, i, i, i+1)
+				t.Fail()
+			}
+		}
+
+		pg := slice.NewPolygon()
+		pg.MP.Points.Push(pl.MP.Points...)
+		lines = pg.Lines()
+		if len(lines) != test.polygon {
+			fmt.Printf("%s: polygon has %d lines, expected %d\n", test.name, len(lines), test.polygon)
+			t.Fail()
+			continue
+		}
+		if lines[len(lines)-1].B != pg.MP.Points.First() {
+			fmt.Printf("%s: polygon is not closed\n", test.name)
+			t.Fail()
+		}
+	}
+}
+
+func TestPointAt(t *testing.T) {
+	tests := []struct {
+		line     *slice.Line
+		distance float64
+		expected *slice.Point
+	}{
+		{slice.NewLine(slice.NewPoint(0, 0), slice.NewPoint(100, 0)), 25, slice.NewPoint(25, 0)},
+		{slice.NewLine(slice.NewPoint(0, 0), slice.NewPoint(0, 100)), 100, slice.NewPoint(0, 100)},
+		{slice.NewLine(slice.NewPoint(0, 0), slice.NewPoint(30, 40)), 25, slice.NewPoint(15, 20)},
+		{slice.NewLine(slice.NewPoint(10, 10), slice.NewPoint(20, 10)), -10, slice.NewPoint(0, 10)},
+	}
+
+	for _, test := range tests {
+		point := test.line.PointAt(test.distance)
+		if point == nil || !point.CoincidesWith(test.expected) {
+			fmt.Printf("PointAt(%f) of %s should be %s\n", test.distance, test.line.Describe(), test.expected.Describe())
+			t.Fail()
+		}
+	}
+}
+
+func TestLineIntersection(t *testing.T) {
+	tests := []struct {
+		a, b     *slice.Line
+		expected *slice.Point
+		infinite *slice.Point
+	}{
+		{
+			slice.NewLine(slice.NewPoint(0, 0), slice.NewPoint(100, 100)),
+			slice.NewLine(slice.NewPoint(0, 100), slice.NewPoint(100, 0)),
+			slice.NewPoint(50, 50), slice.NewPoint(50, 50),
+		},
+		{
+			slice.NewLine(slice.NewPoint(0, 0), slice.NewPoint(10, 0)),
+			slice.NewLine(slice.NewPoint(20, -10), slice.NewPoint(20, 10)),
+			nil, slice.NewPoint(20, 0),
+		},
+		{
+			slice.NewLine(slice.NewPoint(0, 0), slice.NewPoint(10, 0)),
+			slice.NewLine(slice.NewPoint(0, 10), slice.NewPoint(10, 10)),
+			nil, nil,
+		},
+	}
+
+	for i, test := range tests {
+		point, err := test.a.Intersection(test.b)
+		if test.expected == nil && err == nil || test.expected != nil && (err != nil || !point.CoincidesWith(test.expected)) {
+			fmt.Printf("Case %d: unexpected segment intersection %v %v\n", i, point, err)
+			t.Fail()
+		}
+
+		point, err = test.a.IntersectionInfinite(test.b)
+		if test.infinite == nil && err != slice.ErrParallelLines || test.infinite != nil && (err != nil || !point.CoincidesWith(test.infinite)) {
+			fmt.Printf("Case %d: unexpected infinite intersection %v %v\n", i, point, err)
+			t.Fail()
+		}
+	}
+}
+
+func TestBoundingBox(t *testing.T) {
+	bb := slice.NewBoundingBox(slice.NewPoint(10, 20), slice.NewPoint(40, 60))
+	if !bb.Center().CoincidesWith(slice.NewPoint(25, 40)) {
+		fmt.Println("Center should be (25, 40), got", bb.Center().Describe())
+		t.Fail()
+	}
+	if bb.Radius() != 25 {
+		fmt.Println("Radius should be 25, got", bb.Radius())
+		t.Fail()
+	}
+	if bb.Polygon().Area() != 1200 {
+		fmt.Println("Polygon should have an area of 1200, got", bb.Polygon().Area())
+		t.Fail()
+	}
+
+	point := slice.NewPoint(0, 100)
+	bb.MergePoint(point)
+	if !bb.Min.CoincidesWith(slice.NewPoint(0, 20)) || !bb.Max.CoincidesWith(slice.NewPoint(40, 100)) {
+		fmt.Println("MergePoint gave", bb.Min.Describe(), bb.Max.Describe())
+		t.Fail()
+	}
+
+	empty := new(slice.BoundingBox)
+	empty.MergePoint(point)
+	empty.MergePoint(slice.NewPoint(10, 110))
+	if !point.CoincidesWith(slice.NewPoint(0, 100)) {
+		fmt.Println("MergePoint should not alter the merged point")
+		t.Fail()
+	}
+
+	bb.Rotate(math.Pi)
+	if !bb.Min.CoincidesWith(slice.NewPoint(-40, -100)) || !bb.Max.CoincidesWith(slice.NewPoint(0, -20)) {
+		fmt.Println("Rotate gave", bb.Min.Describe(), bb.Max.Describe())
+		t.Fail()
+	}
+}
+
+func TestPolylineSplitAndExtend(t *testing.T) {
+	pl := polyline(0, 0, 100, 0, 100, 100)
+	first, second := pl.SplitAt(slice.NewPoint(100, 40))
+	if len(first.MP.Points) != 3 || len(second.MP.Points) != 2 ||
+		!first.MP.Points.Last().CoincidesWith(slice.NewPoint(100, 40)) ||
+		!second.MP.Points.First().CoincidesWith(slice.NewPoint(100, 40)) {
+		fmt.Println("SplitAt should split at (100, 40)")
+		t.Fail()
+	}
+	if !closeTo(first.MP.Length()+second.MP.Length(), pl.MP.Length()) {
+		fmt.Println("SplitAt halves should add up to the whole")
+		t.Fail()
+	}
+
+	pl.ExtendEnd(50)
+	pl.ExtendStart(25)
+	if !pl.MP.Points.Last().CoincidesWith(slice.NewPoint(100, 150)) ||
+		!pl.MP.Points.First().CoincidesWith(slice.NewPoint(-25, 0)) {
+		fmt.Println("Extend gave", pl.MP.Points.First().Describe(), pl.MP.Points.Last().Describe())
+		t.Fail()
+	}
+
+	points := polyline(0, 0, 100, 0).EquallySpacedPoints(25)
+	if len(points) != 5 {
+		fmt.Printf("Expected 5 equally spaced points, got %d\n", len(points))
+		t.Fail()
+	}
+}
+
+func TestPolygonCentroidAndTriangles(t *testing.T) {
+	poly := square(0, 0, 100)
+	if !poly.Centroid().CoincidesWith(slice.NewPoint(50, 50)) {
+		fmt.Println("Centroid should be (50, 50), got", poly.Centroid().Describe())
+		t.Fail()
+	}
+
+	if poly.MP.Length() != 400 {
+		fmt.Println("Perimeter should be 400, got", poly.MP.Length())
+		t.Fail()
+	}
+
+	triangles := poly.TriangulateConvex()
+	area := 0.0
+	for _, triangle := range triangles {
+		area += triangle.Area()
+	}
+	if len(triangles) != 2 || area != poly.Area() {
+		fmt.Printf("Expected 2 triangles covering %f, got %d covering %f\n", poly.Area(), len(triangles), area)
+		t.Fail()
+	}
+}
+
+func randomPoint(rnd *rand.Rand) *slice.Point {
+	return slice.NewPoint(math.Round(rnd.Float64()*2e6-1e6), math.Round(rnd.Float64()*2e6-1e6))
+}
+
+func TestGeometryProperties(t *testing.T) {
+	rnd := rand.New(rand.NewSource(29))
+
+	for i := 0; i < 500; i++ {
+		line := slice.NewLine(randomPoint(rnd), randomPoint(rnd))
+		if line.Length() < 1 {
+			continue
+		}
+
+		// PointAt lands the requested distance from A, on the line
+		distance := (rnd.Float64()*3 - 1) * line.Length()
+		point := line.PointAt(distance)
+		if math.Abs(line.A.DistanceTo(point)-math.Abs(distance)) > 1e-6*line.Length() ||
+			point.DistanceToPerp(line) > 1e-6*line.Length() {
+			fmt.Println("PointAt strayed for", line.Describe(), distance)
+			t.Fail()
+		}
+
+		// Intersection does not depend on the order of the lines
+		other := slice.NewLine(randomPoint(rnd), randomPoint(rnd))
+		p1, err1 := line.Intersection(other)
+		p2, err2 := other.Intersection(line)
+		if err1 != err2 || (err1 == nil && p1.DistanceTo(p2) > 1e-3) {
+			fmt.Println("Intersection is not symmetric for", line.Describe(), other.Describe())
+			t.Fail()
+		}
+		if err1 == nil && (line.DistanceTo(p1) > 1e-3 || other.DistanceTo(p1) > 1e-3) {
+			fmt.Println("Intersection is not on both lines", p1.Describe())
+			t.Fail()
+		}
+
+		// Rotating there and back again returns the point, give or take rounding
+		angle := rnd.Float64() * 2 * math.Pi
+		center := randomPoint(rnd)
+		rotated := slice.NewPoint(line.A.X, line.A.Y)
+		rotated.RotateWithCenter(angle, center)
+		if math.Abs(rotated.DistanceTo(center)-line.A.DistanceTo(center)) > 1 {
+			fmt.Println("Rotate changed the distance to the center")
+			t.Fail()
+		}
+		rotated.RotateWithCenter(-angle, center)
+		if rotated.DistanceTo(line.A) > 2 {
+			fmt.Println("Rotate did not round trip", rotated.Describe(), line.A.Describe())
+			t.Fail()
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		pl := slice.NewPolyline()
+		for j := 0; j < 2+rnd.Intn(20); j++ {
+			pl.MP.Points.Push(randomPoint(rnd))
+		}
+
+		// the polyline length is the sum of the distances between consecutive points
+		var expected float64
+		for j := 1; j < len(pl.MP.Points); j++ {
+			expected += pl.MP.Points[j-1].DistanceTo(pl.MP.Points[j])
+		}
+		var total float64
+		for _, line := range pl.Lines() {
+			total += line.Length()
+		}
+		if !closeTo(total, expected) || len(pl.Lines()) != len(pl.MP.Points)-1 {
+			fmt.Printf("Lines length %f != %f\n", total, expected)
+			t.Fail()
+		}
+
+		// every box contains its own center and the points it was built from
+		bb := slice.NewBoundingBox(pl.MP.Points...)
+		if !bb.ContainsPoint(bb.Center()) {
+			fmt.Println("BoundingBox does not contain its center")
+			t.Fail()
+		}
+		for _, point := range pl.MP.Points {
+			if !bb.ContainsPoint(point) || bb.Center().DistanceTo(point) > bb.Radius()+1e-6 {
+				fmt.Println("BoundingBox does not contain", point.Describe())
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestConvexHull(t *testing.T) {
+	rnd := rand.New(rand.NewSource(5))
+	points := slice.NewPoints()
+	points.Push(square(-2e6, -2e6, 4e6).MP.Points...)
+	for i := 0; i < 200; i++ {
+		points.Push(randomPoint(rnd))
+	}
+	hull := slice.ConvexHull(points)
+	if len(hull.MP.Points) != 4 || !hull.IsCounterClockwise() || !closeTo(hull.Area(), 16e12) {
+		fmt.Println("Hull should be the enclosing square", hull.Describe())
+		t.Fail()
+	}
+}
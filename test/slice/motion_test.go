@@ -0,0 +1,97 @@
+package slice_test
+
+import (
+	"fmt"
+	"goSlicer/slice"
+	"math"
+	"strings"
+	"testing"
+)
+
+// uShape will make a 30mm U open at the top with 10mm arms
+func uShape() *slice.PolygonEx {
+	pgx := slice.NewPolygonEx()
+	for _, xy := range [][2]float64{{0, 0}, {30, 0}, {30, 30}, {20, 30}, {20, 10}, {10, 10}, {10, 30}, {0, 30}} {
+		pgx.Contour.MP.Points.Push(slice.NewPoint(slice.Scale(xy[0]), slice.Scale(xy[1])))
+	}
+	return pgx
+}
+
+func TestMotionPlannerInsideIsland(t *testing.T) {
+	island := uShape()
+	planner := slice.NewMotionPlanner(slice.PolygonExs{island})
+
+	// straight when nothing is in the way
+	path, err := planner.ShortestPath(slice.NewPoint(slice.Scale(5), slice.Scale(5)), slice.NewPoint(slice.Scale(25), slice.Scale(5)))
+	if err != nil || len(path.MP.Points) != 2 {
+		fmt.Println("Unobstructed travel should be straight", err)
+		t.Fail()
+	}
+
+	// from one arm to the other goes down and around the gap
+	path, err = planner.ShortestPath(slice.NewPoint(slice.Scale(5), slice.Scale(25)), slice.NewPoint(slice.Scale(25), slice.Scale(25)))
+	if err != nil {
+		fmt.Println("Travel should be planned", err)
+		t.Fail()
+		return
+	}
+	length := slice.UnScale(path.MP.Length())
+	if !island.ContainsPline(path) || length < 40 || length > 50 {
+		fmt.Println("Travel should stay inside the U", length, path.Describe())
+		t.Fail()
+	}
+}
+
+func TestMotionPlannerBetweenIslands(t *testing.T) {
+	obstacle := rectangle(10, -10, 20, 20)
+	planner := slice.NewMotionPlanner(slice.PolygonExs{rectangle(0, 0, 5, 5), obstacle, rectangle(25, 0, 30, 5)})
+
+	path, err := planner.ShortestPath(slice.NewPoint(slice.Scale(2.5), slice.Scale(2.5)), slice.NewPoint(slice.Scale(27.5), slice.Scale(2.5)))
+	if err != nil {
+		fmt.Println("Travel should be planned", err)
+		t.Fail()
+		return
+	}
+	crossing, err := slice.IntersectionPolylines(slice.Polylines{path}, obstacle.Polygons())
+	if err != nil || !crossing.Empty() || len(path.MP.Points) < 3 {
+		fmt.Println("Travel should go around the island in between", path.Describe())
+		t.Fail()
+	}
+}
+
+func TestGCodeAvoidCrossingPerimeters(t *testing.T) {
+	gw, out := gcodeWriter(slice.GfMarlin, false)
+	gcode := slice.NewGCode(gw)
+	gcode.AvoidCrossingPerimeters = true
+	layer := slice.NewLayer(0, 0.1, 0.2, 0.2)
+	layer.Slices.Push(uShape())
+	gcode.ChangeLayer(layer)
+
+	gcode.TravelTo(slice.NewPoint(slice.Scale(5), slice.Scale(25)), slice.ErPerimeter, "")
+	out.Reset()
+	gcode.TravelTo(slice.NewPoint(slice.Scale(25), slice.Scale(25)), slice.ErPerimeter, "")
+	if travels := strings.Count(out.String(), "G1 X"); travels < 3 || !strings.Contains(out.String(), "G1 X25.000 Y25.000") {
+		fmt.Println("Travel should be split around the U", out.String())
+		t.Fail()
+	}
+}
+
+// BenchmarkMotionPlanner plans travels across a grid of round islands, each new
+// planner building only the environments its travels go through
+func BenchmarkMotionPlanner(b *testing.B) {
+	islands := slice.NewPolygonExs()
+	for i := 0; i < 49; i++ {
+		island := slice.NewPolygonEx()
+		x, y := slice.Scale(float64(i%7*30)), slice.Scale(float64(i/7*30))
+		island.Contour.MP.Points.Push(circlePoints(x, y, slice.Scale(10), 64, 2*math.Pi)...)
+		islands.Push(island)
+	}
+	from, to := slice.NewPoint(0, 0), slice.NewPoint(slice.Scale(180), slice.Scale(180))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		planner := slice.NewMotionPlanner(islands)
+		if _, err := planner.ShortestPath(from, to); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package slice_test
+
+import (
+	"fmt"
+	"goSlicer/slice"
+	"math"
+	"testing"
+)
+
+func TestOffset(t *testing.T) {
+	tests := []struct {
+		delta float64
+		area  float64
+	}{
+		{-1e5, 8e5 * 8e5},
+		{1e5, 1.2e6 * 1.2e6},
+		{-6e5, 0},
+		{0, 1e6 * 1e6},
+	}
+	for _, test := range tests {
+		offset, err := slice.Offset(slice.Polygons{square(0, 0, 1e6)}, test.delta)
+		area := 0.0
+		for _, poly := range offset {
+			area += poly.Area()
+		}
+		if err != nil || area != test.area {
+			fmt.Printf("Offset by %f should have area %f, got %f %v\n", test.delta, test.area, area, err)
+			t.Fail()
+		}
+	}
+
+	// holes grow while the contour shrinks
+	pgx := squareWithHole()
+	pgx.Scale(1e4)
+	shrunk, err := slice.OffsetEx(pgx.Polygons(), -5e4)
+	if err != nil || len(shrunk) != 1 || len(shrunk[0].Holes) != 1 || shrunk[0].Area() != 9e5*9e5-6e5*6e5 {
+		fmt.Println("Shrinking a PolygonEx should keep its hole", err)
+		t.Fail()
+	}
+
+	// round joins stay within the arc tolerance of a circle
+	round, err := slice.OffsetJoin(slice.Polygons{square(0, 0, 1e6)}, 1e5, slice.JoinRound, 1000)
+	expected := 1e6*1e6 + 4*1e6*1e5 + math.Pi*1e5*1e5
+	if err != nil || len(round) != 1 || math.Abs(round[0].Area()-expected)/expected > 1e-3 {
+		fmt.Println("Round offset has the wrong area", err)
+		t.Fail()
+	}
+
+	// a polyline becomes a stadium
+	pline := polyline(0, 0, 1e6, 0)
+	outline, err := slice.OffsetPolylines(slice.Polylines{pline}, 1e5, slice.JoinRound, slice.EndOpenButt, 1000)
+	if err != nil || len(outline) != 1 || outline[0].Area() != 2e5*1e6 {
+		fmt.Println("Butt ended polyline offset has the wrong area", err)
+		t.Fail()
+	}
+}
+
+func TestClipPolylines(t *testing.T) {
+	plines := slice.Polylines{polyline(-50, 50, 150, 50), polyline(200, 0, 300, 0)}
+	inside, err := slice.IntersectionPolylines(plines, slice.Polygons{square(0, 0, 100)})
+	if err != nil || len(inside) != 1 || inside[0].MP.Length() != 100 {
+		fmt.Println("Only the middle of the first polyline is inside", err)
+		t.Fail()
+	}
+	outside, err := slice.DiffPolylines(plines, slice.Polygons{square(0, 0, 100)})
+	if err != nil || len(outside) != 3 {
+		fmt.Println("Both ends of the first polyline and the second are outside", err, len(outside))
+		t.Fail()
+	}
+}
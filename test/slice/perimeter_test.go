@@ -0,0 +1,83 @@
+package slice_test
+
+import (
+	"fmt"
+	"goSlicer/slice"
+	"math"
+	"testing"
+)
+
+func TestPerimeterGenerator(t *testing.T) {
+	island := slice.NewPolygonEx()
+	island.Contour = square(0, 0, slice.Scale(10))
+	pg := slice.NewPerimeterGenerator(slice.PolygonExs{island}, 0, 3, slice.Scale(0.45), slice.Scale(0.4))
+	if err := pg.Process(); err != nil || len(pg.Loops) != 1 || len(pg.Loops[0]) != 3 {
+		fmt.Println("A square with 3 perimeters should give 3 loops", err)
+		t.FailNow()
+	}
+
+	// inside-out, external perimeter last
+	for i, loop := range pg.Loops[0] {
+		if loop.Depth != 2-i || !loop.IsContour || !loop.Polygon.IsCounterClockwise() {
+			fmt.Printf("Loop %d has depth %d\n", i, loop.Depth)
+			t.Fail()
+		}
+	}
+	external := math.Round(slice.Scale(10 - 0.45))
+	if pg.Loops[0][2].Polygon.Area() != external*external {
+		fmt.Println("External perimeter should be half a width inside the slice")
+		t.Fail()
+	}
+
+	// 0.225 to the external loop, 2 spacings to the last loop and half a spacing to the infill
+	side := slice.Scale(10 - 2*1.225)
+	if len(pg.FillSurfaces) != 1 || math.Abs(pg.FillSurfaces.Area()-side*side) > 1 {
+		fmt.Println("Fill surface has the wrong area", pg.FillSurfaces.Area())
+		t.Fail()
+	}
+
+	// holes are printed before the loops inside them, so the external perimeters stay last
+	pgx := squareWithHole()
+	pgx.Scale(slice.Scale(0.1))
+	pg = slice.NewPerimeterGenerator(slice.PolygonExs{pgx}, 0, 2, slice.Scale(0.45), slice.Scale(0.4))
+	pg.ExternalPerimetersFirst = true
+	if err := pg.Process(); err != nil || len(pg.Loops) != 1 || len(pg.Loops[0]) != 4 {
+		fmt.Println("A square with a hole and 2 perimeters should give 4 loops", err)
+		t.FailNow()
+	}
+	expected := []struct {
+		depth   int
+		contour bool
+	}{{0, true}, {1, true}, {0, false}, {1, false}}
+	for i, loop := range pg.Loops[0] {
+		if loop.Depth != expected[i].depth || loop.IsContour != expected[i].contour {
+			fmt.Printf("Loop %d should have depth %d and contour %v\n", i, expected[i].depth, expected[i].contour)
+			t.Fail()
+		}
+	}
+	if len(pg.FillSurfaces) != 1 || len(pg.FillSurfaces[0].Holes) != 1 {
+		fmt.Println("Fill surface should keep the hole")
+		t.Fail()
+	}
+}
+
+func TestPerimeterGeneratorOneLoopInset(t *testing.T) {
+	island := slice.NewPolygonEx()
+	island.Contour = square(0, 0, slice.Scale(10))
+	perimeter := slice.NewFlow(0.7, 0.2, 0.4, false)
+	external := slice.NewFlow(0.5, 0.2, 0.4, false)
+	pg := slice.NewPerimeterGeneratorFromFlows(slice.PolygonExs{island}, 0, 1, perimeter, external, perimeter)
+	if err := pg.Process(); err != nil || len(pg.FillSurfaces) != 1 {
+		fmt.Println("A single loop should leave one fill surface", err)
+		t.FailNow()
+	}
+
+	// half a width to the external loop and half its own spacing to the infill, the
+	// spacing to the wider perimeters doesn't matter without them
+	inset := external.Width/2 + external.Spacing()/2
+	side := slice.Scale(10 - 2*inset)
+	if math.Abs(pg.FillSurfaces.Area()-side*side)/(side*side) > 1e-3 {
+		fmt.Println("Fill surface should be", inset, "inside the slice, area", pg.FillSurfaces.Area(), "not", side*side)
+		t.Fail()
+	}
+}
@@ -3,6 +3,7 @@ package slice_test
 import (
 	"fmt"
 	"goSlicer/slice"
+	"math"
 	"testing"
 )
 
@@ -22,3 +23,172 @@ func TestArea(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func square(x, y, size float64) *slice.Polygon {
+	poly := slice.NewPolygon()
+	poly.MP.Points.Push(
+		slice.NewPoint(x, y),
+		slice.NewPoint(x+size, y),
+		slice.NewPoint(x+size, y+size),
+		slice.NewPoint(x, y+size))
+	return poly
+}
+
+func bowtie() *slice.Polygon {
+	poly := slice.NewPolygon()
+	poly.MP.Points.Push(
+		slice.NewPoint(0, 0),
+		slice.NewPoint(100, 100),
+		slice.NewPoint(100, 0),
+		slice.NewPoint(0, 100))
+	return poly
+}
+
+func TestIsSimple(t *testing.T) {
+	if !square(0, 0, 100).IsSimple() {
+		fmt.Println("Square should be simple")
+		t.Fail()
+	}
+
+	inters := bowtie().SelfIntersections()
+	if len(inters) != 1 || !inters[0].Point.CoincidesWith(slice.NewPoint(50, 50)) {
+		fmt.Printf("Bowtie should cross once at (50, 50), got %d intersections\n", len(inters))
+		t.Fail()
+	}
+
+	// spike that doubles back along its own edge
+	spike := square(0, 0, 100)
+	spike.MP.Points.Push(slice.NewPoint(0, 150))
+	if spike.IsSimple() {
+		fmt.Println("Spike should not be simple")
+		t.Fail()
+	}
+}
+
+func TestFindIntersections(t *testing.T) {
+	lines := []*slice.Line{
+		slice.NewLine(slice.NewPoint(0, 0), slice.NewPoint(100, 100)),
+		slice.NewLine(slice.NewPoint(0, 100), slice.NewPoint(100, 0)),
+		slice.NewLine(slice.NewPoint(200, 0), slice.NewPoint(300, 0)),
+		slice.NewLine(slice.NewPoint(250, 0), slice.NewPoint(400, 0)),
+		slice.NewLine(slice.NewPoint(500, 0), slice.NewPoint(600, 0)),
+	}
+
+	inters := slice.FindIntersections(lines)
+	if len(inters) != 2 {
+		fmt.Printf("Expected 2 intersections, got %d\n", len(inters))
+		t.FailNow()
+	}
+	if inters[0].LineA != 0 || inters[0].LineB != 1 || inters[0].Overlap != nil {
+		fmt.Println("Expected a crossing between lines 0 and 1")
+		t.Fail()
+	}
+	if inters[1].LineA != 2 || inters[1].LineB != 3 || inters[1].Overlap == nil ||
+		inters[1].Overlap.Length() != 50 {
+		fmt.Println("Expected a 50 long overlap between lines 2 and 3")
+		t.Fail()
+	}
+}
+
+func TestMakeSimple(t *testing.T) {
+	polys, err := bowtie().MakeSimple()
+	if err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+	if len(polys) != 2 {
+		fmt.Printf("Bowtie should split into 2 polygons, got %d\n", len(polys))
+		t.FailNow()
+	}
+	for _, poly := range polys {
+		if !poly.IsSimple() || poly.Area() != 2500 {
+			fmt.Printf("Bad lobe %s area %f\n", poly.Describe(), poly.Area())
+			t.Fail()
+		}
+	}
+}
+
+func TestPolygonExValidate(t *testing.T) {
+	pgx := slice.NewPolygonEx()
+	pgx.Contour = square(0, 0, 100)
+	hole := square(25, 25, 50)
+	hole.MakeClockwise()
+	pgx.Holes.Push(hole)
+	if !pgx.IsValid() {
+		fmt.Println(pgx.Validate().Problems)
+		t.Fail()
+	}
+
+	crossing := square(50, 50, 100)
+	crossing.MakeClockwise()
+	pgx.Holes.Push(crossing)
+	report := pgx.Validate()
+	if report.Valid() || len(report.Intersections) != 4 {
+		fmt.Println("Hole crossing the contour and the other hole should be reported", report.Problems)
+		t.Fail()
+	}
+
+	simple, err := pgx.MakeSimple()
+	if err != nil || len(simple) != 1 || !simple[0].IsValid() || simple[0].Area() != 5625 {
+		fmt.Println("MakeSimple should produce one valid PolygonEx", err)
+		t.Fail()
+	}
+}
+
+// lShape will make a counter clockwise L with one concave corner at (size, size)
+func lShape(size float64) *slice.Polygon {
+	poly := slice.NewPolygon()
+	poly.MP.Points.Push(
+		slice.NewPoint(0, 0),
+		slice.NewPoint(2*size, 0),
+		slice.NewPoint(2*size, size),
+		slice.NewPoint(size, size),
+		slice.NewPoint(size, 2*size),
+		slice.NewPoint(0, 2*size))
+	return poly
+}
+
+func TestConcaveConvexPoints(t *testing.T) {
+	for rotation := 0; rotation < 6; rotation++ {
+		l := lShape(slice.Scale(1))
+		l.MP.Points = l.SplitAtIndex(rotation).MP.Points[:6]
+
+		concave := l.ConcavePoints(math.Pi * 4 / 3)
+		if len(concave) != 1 || !concave[0].CoincidesWith(slice.NewPoint(slice.Scale(1), slice.Scale(1))) {
+			fmt.Println("L should have one concave corner", rotation, len(concave))
+			t.Fail()
+		}
+		if convex := l.ConvexPoints(math.Pi * 2 / 3); len(convex) != 5 {
+			fmt.Println("L should have five convex corners", rotation, len(convex))
+			t.Fail()
+		}
+	}
+}
+
+// BenchmarkFindIntersections sweeps the short edges of a finely sliced circle, the
+// case slices give it
+func BenchmarkFindIntersections(b *testing.B) {
+	points := circlePoints(0, 0, slice.Scale(100), 20000, 2*math.Pi)
+	lines := make([]*slice.Line, len(points))
+	for i := range points {
+		lines[i] = slice.NewLine(points[i], points[(i+1)%len(points)])
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		slice.FindIntersections(lines)
+	}
+}
+
+// BenchmarkFindIntersectionsWorstCase sweeps long lines that all share the same X
+// range, where every pair is compared
+func BenchmarkFindIntersectionsWorstCase(b *testing.B) {
+	lines := make([]*slice.Line, 2000)
+	for i := range lines {
+		y := slice.Scale(float64(i))
+		lines[i] = slice.NewLine(slice.NewPoint(0, y), slice.NewPoint(slice.Scale(100), y+slice.Scale(0.5)))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		slice.FindIntersections(lines)
+	}
+}
@@ -0,0 +1,350 @@
+package slice_test
+
+import (
+	"errors"
+	"fmt"
+	"goSlicer/slice"
+	"math"
+	"strings"
+	"testing"
+)
+
+func regionArea(region *slice.LayerRegion) float64 {
+	return math.Round(slice.UnScale(slice.UnScale(region.Slices.Area())))
+}
+
+func TestSplitRegions(t *testing.T) {
+	object := slice.NewPrintObject(layerStack(8, rectangle(0, 0, 20, 20)))
+	dense := slice.NewModifierVolume(layerStack(4, rectangle(10, 0, 30, 10)))
+	dense.Overrides["fill_density"] = "100%"
+	dense.Overrides["fill_pattern"] = "rectilinear"
+	// the same options again, so the same region
+	same := slice.NewModifierVolume(layerStack(4, rectangle(0, 10, 5, 15)))
+	same.Overrides["fill_pattern"] = "rectilinear"
+	same.Overrides["fill_density"] = "100%"
+	object.Modifiers.Push(dense, same)
+
+	if err := object.SplitRegions(); err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+	if len(object.Regions) != 2 || object.Regions[1].FillDensity != 100 || object.Regions[0].FillDensity != 20 {
+		fmt.Println("expected the default and a dense region, got", len(object.Regions))
+		t.FailNow()
+	}
+	for i, layer := range object.Layers {
+		if len(layer.Regions) != 2 {
+			fmt.Println("every layer should have both regions, layer", i, "has", len(layer.Regions))
+			t.Fail()
+			continue
+		}
+		denseArea := 125.0
+		if i >= 4 {
+			denseArea = 0
+		}
+		if regionArea(layer.Regions[1]) != denseArea || regionArea(layer.Regions[0]) != 400-denseArea {
+			fmt.Println("layer", i, "regions should be", 400-denseArea, denseArea, "got", regionArea(layer.Regions[0]), regionArea(layer.Regions[1]))
+			t.Fail()
+		}
+	}
+
+	if err := slice.DetectSurfacesType(object.Layers); err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+	// the regions meet inside the object, which makes no top or bottom there
+	if area := surfaceArea(object.Layers[2], slice.StInternal); area != 400 {
+		fmt.Println("layer 2 should be all internal, got", area)
+		t.Fail()
+	}
+	if area := slice.UnScale(slice.UnScale(object.Layers[2].Regions[1].Surfaces.Area())); math.Round(area) != 125 {
+		fmt.Println("the dense region should keep its surfaces, got", area)
+		t.Fail()
+	}
+}
+
+func TestRegionShells(t *testing.T) {
+	object := slice.NewPrintObject(layerStack(12, rectangle(0, 0, 20, 20)))
+	shells := slice.NewModifierVolume(layerStack(12, rectangle(0, 0, 10, 20)))
+	shells.Overrides["bottom_solid_layers"] = "5"
+	object.Modifiers.Push(shells)
+	if err := object.SplitRegions(); err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+	if err := slice.DetectSurfacesType(object.Layers); err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+	if err := slice.DiscoverVerticalShells(object.Layers, 1, 1); err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+	// the default region follows its config of 3 bottom layers, the modifier 5
+	for i, solid := range []float64{0, 400, 400, 200, 200, 0} {
+		if area := surfaceArea(object.Layers[i], slice.StInternalSolid); area != solid {
+			fmt.Println("layer", i, "should have", solid, "solid, got", area)
+			t.Fail()
+		}
+	}
+}
+
+func TestRegionConfigs(t *testing.T) {
+	object := slice.NewPrintObject(layerStack(4, rectangle(0, 0, 20, 20)))
+	modifier := slice.NewModifierVolume(layerStack(4, rectangle(0, 0, 10, 20)))
+	modifier.Overrides["perimeters"] = "5"
+	modifier.Overrides["perimeter_extrusion_width"] = "0.6"
+	modifier.Overrides["fill_density"] = "50%"
+	modifier.Overrides["fill_pattern"] = "gyroid"
+	modifier.Overrides["fill_angle"] = "90"
+	modifier.Overrides["external_fill_pattern"] = "concentric"
+	object.Modifiers.Push(modifier)
+	if err := object.SplitRegions(); err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+
+	layer := object.Layers[1]
+	base, modified := layer.Regions[0], layer.Regions[1]
+	for _, test := range []struct {
+		region     *slice.LayerRegion
+		perimeters int
+		width      float64
+		density    float64
+		pattern    slice.FillPattern
+		angle      float64
+		external   slice.FillPattern
+	}{
+		{base, 3, 0, 0.2, slice.FpHoneycomb, math.Pi / 4, slice.FpRectilinear},
+		{modified, 5, 0.6, 0.5, slice.FpGyroid, math.Pi / 2, slice.FpConcentric},
+	} {
+		pg, err := test.region.PerimeterGenerator(layer, 0.4)
+		if err != nil || pg.Perimeters != test.perimeters || pg.Slices.Area() != test.region.Slices.Area() ||
+			(test.width > 0 && pg.PerimeterFlow.Width != test.width) {
+			fmt.Println("the perimeters should follow the region config", pg.Perimeters, pg.PerimeterFlow.Width, err)
+			t.Fail()
+		}
+
+		internal := slice.NewSurface(test.region.Slices.First(), slice.StInternal)
+		flow, err := test.region.FillFlow(internal, layer, 0.4)
+		if err != nil {
+			fmt.Println(err)
+			t.FailNow()
+		}
+		params := test.region.FillParams(internal, layer, flow)
+		if test.region.FillPattern(internal) != test.pattern || params.Density != test.density || math.Abs(params.Angle-test.angle) > 1e-9 {
+			fmt.Println("the infill should follow the region config", test.region.FillPattern(internal), params.Density, params.Angle)
+			t.Fail()
+		}
+
+		// solid surfaces ignore the density and the pattern of sparse infill, and bridges
+		// are lines along their angle whatever the external pattern
+		for _, solid := range []struct {
+			st      slice.SurfaceType
+			pattern slice.FillPattern
+		}{
+			{slice.StTop, test.external},
+			{slice.StBottom, test.external},
+			{slice.StInternalSolid, slice.FpRectilinear},
+			{slice.StBottomBridge, slice.FpRectilinear},
+			{slice.StInternalBridge, slice.FpRectilinear},
+		} {
+			surface := slice.NewSurface(test.region.Slices.First(), solid.st)
+			surface.BridgeAngle = 0.3
+			params := test.region.FillParams(surface, layer, flow)
+			if test.region.FillPattern(surface) != solid.pattern || params.Density != 1 || (surface.IsBridge() && params.Angle != 0.3) {
+				fmt.Println(solid.st, "should be filled with", solid.pattern, "at full density, got", test.region.FillPattern(surface), params.Density, params.Angle)
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestPrintObjectOverrides(t *testing.T) {
+	print := slice.NewFullPrintConfig()
+	object := slice.NewPrintObject(layerStack(1, rectangle(0, 0, 20, 20)))
+	object.Overrides["perimeters"] = "5"
+	object.Overrides["support_material"] = "1"
+	if err := object.ApplyConfig(print); err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+	if object.RegionConfig.Perimeters != 5 || !object.Config.SupportMaterial || print.Perimeters != 3 {
+		fmt.Println("the object should override the print without changing it")
+		t.Fail()
+	}
+
+	object.Overrides["gcode_flavor"] = "marlin"
+	if err := object.ApplyConfig(print); !errors.Is(err, slice.ErrUnknownOption) {
+		fmt.Println("printer options can't be set per object, got", err)
+		t.Fail()
+	}
+
+	volume := slice.NewModifierVolume(layerStack(1, rectangle(0, 0, 5, 5)))
+	volume.Overrides["layer_height"] = "0.1"
+	if _, err := volume.RegionConfig(object.RegionConfig); !errors.Is(err, slice.ErrUnknownOption) {
+		fmt.Println("object options can't be set by a modifier, got", err)
+		t.Fail()
+	}
+	volume.Overrides = map[string]string{"perimeters": "few"}
+	if _, err := volume.RegionConfig(object.RegionConfig); !errors.Is(err, slice.ErrInvalidOptionValue) {
+		fmt.Println("expected an invalid value, got", err)
+		t.Fail()
+	}
+}
+
+func TestGenerateLayersWithRanges(t *testing.T) {
+	object := slice.NewPrintObject(nil)
+	object.Overrides["layer_height"] = "0.25"
+	object.Overrides["first_layer_height"] = "0.25"
+	if err := object.ApplyConfig(slice.NewFullPrintConfig()); err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+	fine := slice.NewLayerRange(1, 2)
+	fine.Overrides["layer_height"] = "0.1"
+	fine.Overrides["fill_density"] = "100%"
+	fine.Overrides["fill_pattern"] = "rectilinear"
+	object.Ranges.Push(fine)
+
+	layers, err := object.GenerateLayers(3)
+	if err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+	if len(layers) != 18 || math.Abs(layers.Last().PrintZ-3) > slice.Epsilon {
+		fmt.Println("expected 4 + 10 + 4 layers up to 3mm, got", len(layers))
+		t.FailNow()
+	}
+	for i, layer := range layers {
+		height := 0.25
+		if i >= 4 && i < 14 {
+			height = 0.1
+		}
+		if math.Abs(layer.Height-height) > slice.Epsilon || math.Abs(layer.SliceZ-(layer.PrintZ-height/2)) > slice.Epsilon {
+			fmt.Println("layer", i, "should be", height, "high, got", layer.Height, layer.SliceZ, layer.PrintZ)
+			t.Fail()
+		}
+	}
+
+	// a range starting within a layer cuts it short
+	object.Ranges[0].MinZ = 1.1
+	if layers, _ = object.GenerateLayers(3); math.Abs(layers[4].Height-0.1) > slice.Epsilon || math.Abs(layers[5].PrintZ-1.2) > slice.Epsilon {
+		fmt.Println("the layer under the range should end at 1.1, got", layers[4].PrintZ)
+		t.Fail()
+	}
+	object.Ranges[0].MinZ = 1
+
+	object.Layers, _ = object.GenerateLayers(3)
+	for _, layer := range object.Layers {
+		layer.Slices.Push(rectangle(0, 0, 20, 20))
+	}
+	if err := object.SplitRegions(); err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+	if len(object.Regions) != 2 || object.Regions[1].FillDensity != 100 {
+		fmt.Println("expected a region for the range, got", len(object.Regions))
+		t.FailNow()
+	}
+	for i, layer := range object.Layers {
+		inRange := i >= 4 && i < 14
+		if (regionArea(layer.Regions[1]) == 400) != inRange || (regionArea(layer.Regions[0]) == 400) == inRange {
+			fmt.Println("layer", i, "should be printed by the range", inRange)
+			t.Fail()
+		}
+	}
+}
+
+func TestGenerateLayersWithFractionalRange(t *testing.T) {
+	object := slice.NewPrintObject(nil)
+	object.Overrides["layer_height"] = "0.2"
+	object.Overrides["first_layer_height"] = "0.2"
+	if err := object.ApplyConfig(slice.NewFullPrintConfig()); err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+	fine := slice.NewLayerRange(0.5, 0.8)
+	fine.Overrides["layer_height"] = "0.1"
+	fine.Overrides["fill_density"] = "100%"
+	fine.Overrides["fill_pattern"] = "rectilinear"
+	object.Ranges.Push(fine)
+
+	layers, err := object.GenerateLayers(1.2)
+	if err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+	// 0.2, 0.4, 0.5 below the range, 0.6, 0.7, 0.8 in it and 1.0, 1.2 above
+	printZ := []float64{0.2, 0.4, 0.5, 0.6, 0.7, 0.8, 1.0, 1.2}
+	if len(layers) != len(printZ) {
+		fmt.Println("expected", len(printZ), "layers, got", len(layers))
+		t.FailNow()
+	}
+	for i, layer := range layers {
+		if math.Abs(layer.PrintZ-printZ[i]) > slice.Epsilon {
+			fmt.Println("layer", i, "should end at", printZ[i], "got", layer.PrintZ)
+			t.Fail()
+		}
+	}
+	if layers[5].PrintZ != 0.8 {
+		fmt.Println("the range should end exactly at 0.8, got", layers[5].PrintZ)
+		t.Fail()
+	}
+
+	object.Layers = layers
+	for _, layer := range object.Layers {
+		layer.Slices.Push(rectangle(0, 0, 20, 20))
+	}
+	if err := object.SplitRegions(); err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+	for i, layer := range object.Layers {
+		inRange := i >= 3 && i < 6
+		if len(layer.Regions) != 2 || (regionArea(layer.Regions[1]) == 400) != inRange {
+			fmt.Println("layer", i, "should be printed by the range", inRange)
+			t.Fail()
+		}
+	}
+}
+
+func TestPrintObjectIni(t *testing.T) {
+	object := slice.NewPrintObject(nil)
+	object.Overrides["perimeters"] = "4"
+	lr := slice.NewLayerRange(10, 20.5)
+	lr.Overrides["fill_density"] = "100%"
+	lr.Overrides["layer_height"] = "0.1"
+	object.Ranges.Push(lr, slice.NewLayerRange(30, 40))
+
+	out := new(strings.Builder)
+	object.WriteTo(out)
+	expected := "# generated by goSlicer\nperimeters = 4\n\n[layer_range 10 20.5]\nfill_density = 100%\nlayer_height = 0.1\n\n[layer_range 30 40]\n"
+	if out.String() != expected {
+		fmt.Println("unexpected object file", out.String())
+		t.Fail()
+	}
+
+	again := slice.NewPrintObject(nil)
+	if err := again.ReadIni(strings.NewReader(out.String())); err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+	if again.Overrides["perimeters"] != "4" || len(again.Ranges) != 2 || again.Ranges[0].MaxZ != 20.5 || again.Ranges[0].Overrides["layer_height"] != "0.1" {
+		fmt.Println("the object should read back the same")
+		t.Fail()
+	}
+
+	for _, ini := range []string{
+		"[layer_range 10 20]\nsupport_material = 1\n",
+		"[layer_range 20 10]\n",
+		"[modifier]\n",
+		"gcode_flavor = marlin\n",
+	} {
+		err := slice.NewPrintObject(nil).ReadIni(strings.NewReader(ini))
+		if err == nil || !strings.HasPrefix(err.Error(), "line ") {
+			fmt.Println("expected an error with its line for", ini, "got", err)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package slice_test
+
+import (
+	"fmt"
+	"goSlicer/slice"
+	"testing"
+)
+
+func TestParseSeamPosition(t *testing.T) {
+	for _, sp := range []slice.SeamPosition{slice.SeamAligned, slice.SeamNearest, slice.SeamRandom, slice.SeamRear, slice.SeamSharpest} {
+		if parsed, err := slice.ParseSeamPosition(sp.String()); err != nil || parsed != sp {
+			fmt.Println("Seam position should parse back", sp)
+			t.Fail()
+		}
+	}
+	if _, err := slice.ParseSeamPosition("front"); err != slice.ErrUnknownSeamPosition {
+		fmt.Println("Unknown seam position should fail")
+		t.Fail()
+	}
+}
+
+func TestSeamPlacer(t *testing.T) {
+	mm := slice.Scale(1)
+	house := slice.NewPolygon()
+	house.MP.Points.Push(slice.NewPoint(0, 0), slice.NewPoint(10*mm, 0), slice.NewPoint(10*mm, 10*mm), slice.NewPoint(5*mm, 15*mm), slice.NewPoint(0, 10*mm))
+	wedge := slice.NewPolygon()
+	wedge.MP.Points.Push(slice.NewPoint(0, 0), slice.NewPoint(10*mm, 0), slice.NewPoint(0, 3*mm))
+
+	tests := []struct {
+		position slice.SeamPosition
+		pg       *slice.Polygon
+		lastPos  *slice.Point
+		seam     *slice.Point
+	}{
+		{slice.SeamNearest, square(0, 0, 10*mm), slice.NewPoint(12*mm, -mm), slice.NewPoint(10*mm, 0)},
+		// concave corners win over nearer convex ones
+		{slice.SeamNearest, lShape(mm), slice.NewPoint(2*mm, 0), slice.NewPoint(mm, mm)},
+		{slice.SeamRear, house, slice.NewPoint(0, 0), slice.NewPoint(5*mm, 15*mm)},
+		{slice.SeamSharpest, wedge, slice.NewPoint(0, 0), slice.NewPoint(10*mm, 0)},
+		{slice.SeamSharpest, lShape(mm), slice.NewPoint(0, 0), slice.NewPoint(mm, mm)},
+	}
+	for i, test := range tests {
+		seam := slice.NewSeamPlacer(test.position).Place(test.pg, test.lastPos)
+		if seam == nil || !seam.CoincidesWith(test.seam) {
+			fmt.Println("Wrong seam", i, test.position)
+			t.Fail()
+		}
+		pline := slice.NewSeamPlacer(test.position).SplitLoop(test.pg, test.lastPos)
+		if !pline.MP.Points.First().CoincidesWith(test.seam) || !pline.MP.Points.Last().CoincidesWith(test.seam) {
+			fmt.Println("Loop should be split at its seam", i)
+			t.Fail()
+		}
+	}
+}
+
+func TestSeamPlacerAligned(t *testing.T) {
+	mm := slice.Scale(1)
+	sp := slice.NewSeamPlacer(slice.SeamAligned)
+	// every layer the nozzle comes from somewhere else, the seam stays at the back right
+	for layer, lastPos := range []*slice.Point{slice.NewPoint(20*mm, 20*mm), slice.NewPoint(0, 0), slice.NewPoint(-5*mm, 5*mm)} {
+		seam := sp.Place(square(0, 0, (10-float64(layer))*mm), lastPos)
+		if !seam.CoincidesWith(slice.NewPoint((10-float64(layer))*mm, (10-float64(layer))*mm)) {
+			fmt.Println("Aligned seams should form a line", layer, seam.Describe())
+			t.Fail()
+		}
+	}
+
+	// random seams move around
+	sp = slice.NewSeamPlacer(slice.SeamRandom)
+	seams := make(map[string]bool)
+	for i := 0; i < 20; i++ {
+		seams[sp.Place(square(0, 0, 10*mm), nil).Describe()] = true
+	}
+	if len(seams) < 2 {
+		fmt.Println("Random seams should not all be the same", len(seams))
+		t.Fail()
+	}
+}
+
+func TestGCodeSeam(t *testing.T) {
+	gw, _ := gcodeWriter(slice.GfMarlin, false)
+	gcode := slice.NewGCode(gw)
+	gcode.Seam = slice.NewSeamPlacer(slice.SeamRear)
+	loop := slice.NewExtrusionLoopFromPolygon(square(0, 0, slice.Scale(10)), slice.ErPerimeter, slice.ElrDefault, 0.1, slice.Scale(0.5), slice.Scale(0.2))
+	gcode.ExtrudeEntity(loop, 30, "")
+	if !gcode.LastPosition().CoincidesWith(slice.NewPoint(0, slice.Scale(10))) {
+		fmt.Println("Loop should start and end at its rear seam", gcode.LastPosition().Describe())
+		t.Fail()
+	}
+}
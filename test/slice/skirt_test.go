@@ -0,0 +1,150 @@
+package slice_test
+
+import (
+	"fmt"
+	"goSlicer/slice"
+	"math"
+	"strings"
+	"testing"
+)
+
+func skirtLoopLength(entity slice.ExtrusionEntity) float64 {
+	return slice.UnScale(entity.(*slice.ExtrusionLoop).Length())
+}
+
+func TestSkirt(t *testing.T) {
+	flow := slice.NewFlow(0.5, 0.2, 0.4, false)
+	skirt := slice.NewSkirt(flow, 1.75)
+	skirt.Loops = 2
+
+	collection, err := skirt.Generate(layerStack(2, rectangle(0, 0, 10, 10)), layerStack(2, rectangle(20, 0, 30, 10)))
+	if err != nil || len(collection.Entities) != 2 {
+		fmt.Println("Skirt should have 2 loops", err)
+		t.Fail()
+		return
+	}
+	// around the 30 by 10 hull, rounded by the distance
+	spacing := slice.UnScale(flow.ScaledSpacing())
+	for i, radius := range []float64{6 + 1.5*spacing, 6 + 0.5*spacing} {
+		expected := 80 + 2*math.Pi*radius
+		if length := skirtLoopLength(collection.Entities[i]); math.Abs(length-expected) > 0.01*expected {
+			fmt.Println("Skirt loop should go around the convex hull", i, length, expected)
+			t.Fail()
+		}
+		if collection.Entities[i].GetRole() != slice.ErSkirt {
+			fmt.Println("Skirt loops should be skirt extrusions")
+			t.Fail()
+		}
+	}
+
+	// around each object when they are far enough apart
+	skirt.ConvexHull = false
+	skirt.Distance = 2
+	collection, _ = skirt.Generate(layerStack(2, rectangle(0, 0, 10, 10), rectangle(20, 0, 30, 10)))
+	if len(collection.Entities) != 4 {
+		fmt.Println("Skirt should go around each island", len(collection.Entities))
+		t.Fail()
+	}
+
+	// enough loops to use the minimum filament
+	skirt.Loops = 1
+	skirt.MinLength = 20
+	collection, _ = skirt.Generate(layerStack(2, rectangle(0, 0, 10, 10)))
+	used := 0.0
+	for _, entity := range collection.Entities {
+		used += skirtLoopLength(entity) * flow.MM3PerMM() / (1.75 * 1.75 * math.Pi / 4)
+	}
+	if len(collection.Entities) < 2 || used < 20 || used-skirtLoopLength(collection.Entities[0])*flow.MM3PerMM()/(1.75*1.75*math.Pi/4) >= 20 {
+		fmt.Println("Skirt should use just enough filament", len(collection.Entities), used)
+		t.Fail()
+	}
+
+	// the minimum length doesn't bring back a disabled skirt
+	skirt.Loops = 0
+	if collection, _ = skirt.Generate(layerStack(2, rectangle(0, 0, 10, 10))); len(collection.Entities) != 0 {
+		fmt.Println("Skirt without loops should be empty", len(collection.Entities))
+		t.Fail()
+	}
+	skirt.Loops = 1
+	skirt.Height = 0
+	if collection, _ = skirt.Generate(layerStack(2, rectangle(0, 0, 10, 10))); len(collection.Entities) != 0 {
+		fmt.Println("Skirt without height should be empty", len(collection.Entities))
+		t.Fail()
+	}
+}
+
+func TestBrim(t *testing.T) {
+	flow := slice.NewFlow(0.5, 0.2, 0.4, false)
+	island := rectangle(0, 0, 20, 20)
+	hole := rectangle(5, 5, 15, 15).Contour
+	hole.MakeClockwise()
+	island.Holes.Push(hole)
+
+	tests := []struct {
+		brimType slice.BrimType
+		outer    int
+		inner    int
+	}{
+		{slice.BtOuterOnly, 4, 0},
+		{slice.BtInnerOnly, 0, 4},
+		{slice.BtOuterAndInner, 4, 4},
+	}
+	for _, test := range tests {
+		brim := slice.NewBrim(flow, 2)
+		brim.Type = test.brimType
+		collection, err := brim.Generate(layerStack(2, island))
+		if err != nil {
+			fmt.Println("Brim should be generated", err)
+			t.Fail()
+			continue
+		}
+		outer, inner := 0, 0
+		for _, entity := range collection.Entities {
+			pline := entity.AsPolyline()
+			switch {
+			case rectangle(5, 5, 15, 15).ContainsPline(pline):
+				inner++
+			case !rectangle(-0.1, -0.1, 20.1, 20.1).ContainsPline(pline):
+				outer++
+			}
+		}
+		if outer != test.outer || inner != test.inner || len(collection.Entities) != outer+inner {
+			fmt.Println("Wrong brim loops", test.brimType, outer, inner, len(collection.Entities))
+			t.Fail()
+		}
+	}
+
+	if bt, err := slice.ParseBrimType("outer_and_inner"); err != nil || bt != slice.BtOuterAndInner {
+		fmt.Println("Brim type should parse")
+		t.Fail()
+	}
+}
+
+func TestGCodeSkirtAndBrim(t *testing.T) {
+	flow := slice.NewFlow(0.5, 0.2, 0.4, false)
+	object := layerStack(2, rectangle(0, 0, 10, 10))
+	skirt, _ := slice.NewSkirt(flow, 1.75).Generate(object)
+	brim, _ := slice.NewBrim(flow, 1).Generate(object)
+
+	gw, out := gcodeWriter(slice.GfMarlin, false)
+	gw.GCodeComments = true
+	gcode := slice.NewGCode(gw)
+	gcode.Skirt, gcode.Brim = skirt, brim
+
+	gcode.ChangeLayer(object[0])
+	first := out.String()
+	skirtAt, brimAt := strings.Index(first, "; skirt"), strings.Index(first, "; brim")
+	if skirtAt < 0 || brimAt < skirtAt {
+		fmt.Println("Skirt then brim should be extruded on the first layer", first)
+		t.Fail()
+	}
+
+	// a second object on the same layer and the next layer don't get them again
+	out.Reset()
+	gcode.ChangeLayer(object[0])
+	gcode.ChangeLayer(object[1])
+	if strings.Contains(out.String(), "; skirt") || strings.Contains(out.String(), "; brim") {
+		fmt.Println("Skirt and brim should be extruded once", out.String())
+		t.Fail()
+	}
+}
@@ -0,0 +1,173 @@
+package slice_test
+
+import (
+	"fmt"
+	"goSlicer/slice"
+	"math"
+	"testing"
+)
+
+// tLayers will stack 10 layers of a 10mm column under 5 layers of a 30mm slab
+func tLayers() slice.Layers {
+	layers := layerStack(15, rectangle(0, 0, 10, 10))
+	for _, layer := range layers[10:] {
+		layer.Slices = slice.PolygonExs{rectangle(-10, 0, 20, 10)}
+	}
+	return layers
+}
+
+// supportFlow is a 0.5mm wide line out of a 0.4mm nozzle
+func supportFlow() *slice.Flow {
+	return slice.NewFlow(0.5, 0.2, 0.4, false)
+}
+
+// extrusionPolylines will collect the polylines of the paths of a collection
+func extrusionPolylines(collection *slice.ExtrusionEntityCollection, role slice.ExtrusionRole) (slice.Polylines, bool) {
+	plines := slice.NewPolylines()
+	for _, entity := range collection.Entities {
+		if entity.GetRole() != role {
+			return plines, false
+		}
+		plines.Push(entity.AsPolyline())
+	}
+	return plines, true
+}
+
+func TestSupportMaterial(t *testing.T) {
+	layers := tLayers()
+	sm := slice.NewSupportMaterial(supportFlow(), supportFlow())
+	if err := sm.Generate(layers); err != nil {
+		fmt.Println("Support should be generated", err)
+		t.Fail()
+	}
+
+	for i, layer := range layers {
+		// the Z gap leaves the layer under the overhang empty
+		supported := i <= 8
+		if layer.SupportIslands.Empty() == supported {
+			fmt.Println("Wrong support islands", i, len(layer.SupportIslands))
+			t.Fail()
+		}
+		if !supported {
+			continue
+		}
+
+		// interface on the top 3 layers of the columns only
+		if layer.SupportInterfaceFills.Empty() != (i < 6) {
+			fmt.Println("Wrong support interface", i, layer.SupportInterfaceFills.Empty())
+			t.Fail()
+		}
+		if layer.SupportFills.Empty() != (i >= 6) {
+			fmt.Println("Wrong support base", i, layer.SupportFills.Empty())
+			t.Fail()
+		}
+
+		base, ok := extrusionPolylines(layer.SupportFills, slice.ErSupportMaterial)
+		interfaces, interfaceOk := extrusionPolylines(layer.SupportInterfaceFills, slice.ErSupportMaterialInterface)
+		if !ok || !interfaceOk {
+			fmt.Println("Support should have the support roles", i)
+			t.Fail()
+		}
+
+		// support keeps away from the object
+		object, _ := slice.Offset(layer.Slices.Polygons(), sm.XYDistance)
+		touching, _ := slice.IntersectionPolylines(append(base, interfaces...), object)
+		if !touching.Empty() {
+			fmt.Println("Support should keep its distance from the object", i, len(touching))
+			t.Fail()
+		}
+	}
+}
+
+func TestSupportMaterialThreshold(t *testing.T) {
+	// a wall leaning 0.1mm out every 0.2mm layer, about 63 degrees from horizontal
+	layers := layerStack(10)
+	for i, layer := range layers {
+		layer.Slices.Push(rectangle(0, 0, 10+0.1*float64(i), 10))
+	}
+
+	tests := []struct {
+		threshold float64
+		supported bool
+	}{
+		{45, false},
+		{80, true},
+	}
+	for _, test := range tests {
+		sm := slice.NewSupportMaterial(supportFlow(), supportFlow())
+		sm.ThresholdAngle = test.threshold
+		sm.Generate(layers)
+		if layers[1].SupportIslands.Empty() == test.supported {
+			fmt.Println("Wrong support for the threshold", test.threshold)
+			t.Fail()
+		}
+	}
+}
+
+func TestSupportMaterialPillars(t *testing.T) {
+	layers := tLayers()
+	sm := slice.NewSupportMaterial(supportFlow(), supportFlow())
+	sm.Pattern = slice.SpPillars
+	sm.PillarSpacing = slice.Scale(5)
+	sm.Generate(layers)
+
+	// pillars at -10, -5, 15 and 20 along X and 0, 5 and 10 along Y
+	plines, ok := extrusionPolylines(layers[0].SupportFills, slice.ErSupportMaterial)
+	grown, _ := slice.OffsetPolylines(plines, slice.Scale(0.5), slice.JoinSquare, slice.EndOpenSquare, 3)
+	islands, _ := slice.UnionEx(grown)
+	if !ok || len(layers[0].SupportIslands) != 2 || len(islands) != 12 {
+		fmt.Println("Pillars should stand apart", len(layers[0].SupportIslands), len(islands))
+		t.Fail()
+	}
+}
+
+func TestSupportMaterialRaft(t *testing.T) {
+	layers := tLayers()
+	sm := slice.NewSupportMaterial(supportFlow(), supportFlow())
+	sm.InterfaceLayers = 1
+	sm.RaftLayers = 3
+	if err := sm.Generate(layers); err != nil {
+		fmt.Println("Support should be generated", err)
+		t.Fail()
+	}
+	raft, err := sm.Raft(layers)
+	if err != nil || len(raft) != 3 {
+		fmt.Println("Raft should have 3 layers", err)
+		t.Fail()
+		return
+	}
+
+	// under the column and the support of the slab, which reaches a bit past the slab,
+	// with the margin
+	for i, layer := range raft {
+		if !closeTo(layer.PrintZ, 0.2*float64(i+1)) || len(layer.SupportIslands) != 1 ||
+			math.Abs(slice.UnScale(slice.UnScale(layer.SupportIslands.Area()))-33.5*13.5) > 5 {
+			fmt.Println("Wrong raft layer", i, layer.PrintZ, slice.UnScale(slice.UnScale(layer.SupportIslands.Area())))
+			t.Fail()
+		}
+	}
+
+	// sparse base turning every layer, dense interface on top
+	base0, ok0 := extrusionPolylines(raft[0].SupportFills, slice.ErSupportMaterial)
+	base1, ok1 := extrusionPolylines(raft[1].SupportFills, slice.ErSupportMaterial)
+	top, ok2 := extrusionPolylines(raft[2].SupportInterfaceFills, slice.ErSupportMaterialInterface)
+	vertical0, horizontal0 := directions(base0)
+	vertical1, horizontal1 := directions(base1)
+	length := func(plines slice.Polylines) (total float64) {
+		for _, pline := range plines {
+			total += pline.MP.Length()
+		}
+		return total
+	}
+	if !ok0 || !ok1 || !ok2 || base0.Empty() || (vertical0 > horizontal0) == (vertical1 > horizontal1) ||
+		!raft[2].SupportFills.Empty() || length(top) < 3*length(base1) {
+		fmt.Println("Wrong raft fills", vertical0, horizontal0, vertical1, horizontal1, length(top), length(base1))
+		t.Fail()
+	}
+
+	// the object sits the Z gap above the raft
+	if layers[0].ID != 3 || !closeTo(layers[0].PrintZ, 1.0) || !closeTo(layers.Last().PrintZ, 3.8) {
+		fmt.Println("Object should be moved above the raft", layers[0].ID, layers[0].PrintZ)
+		t.Fail()
+	}
+}
@@ -0,0 +1,96 @@
+package slice_test
+
+import (
+	"fmt"
+	"goSlicer/slice"
+	"math"
+	"testing"
+)
+
+// towerLayers will stack 10 layers of a 10mm square under 2 layers of a 20mm square
+func towerLayers() slice.Layers {
+	layers := layerStack(12, rectangle(0, 0, 10, 10))
+	for _, layer := range layers[10:] {
+		layer.Slices = slice.PolygonExs{rectangle(0, 0, 20, 20)}
+	}
+	return layers
+}
+
+// surfaceArea will add up the unscaled area of the surfaces of a type
+func surfaceArea(layer *slice.Layer, st slice.SurfaceType) float64 {
+	area := layer.Surfaces.Filter(st).Area()
+	return math.Round(slice.UnScale(slice.UnScale(area)))
+}
+
+func TestDetectSurfacesType(t *testing.T) {
+	layers := towerLayers()
+	if err := slice.DetectSurfacesType(layers); err != nil {
+		fmt.Println("Surfaces should be detected", err)
+		t.Fail()
+	}
+
+	tests := []struct {
+		layer int
+		st    slice.SurfaceType
+		area  float64
+	}{
+		{0, slice.StBottom, 100},
+		{0, slice.StInternal, 0},
+		{5, slice.StInternal, 100},
+		{9, slice.StTop, 0},
+		{10, slice.StBottomBridge, 300},
+		{10, slice.StInternal, 100},
+		{11, slice.StTop, 400},
+	}
+	for _, test := range tests {
+		if area := surfaceArea(layers[test.layer], test.st); area != test.area {
+			fmt.Println("Wrong surface area", test.layer, test.st, area, test.area)
+			t.Fail()
+		}
+	}
+	if surface := layers[11].Surfaces.First(); surface.Thickness != 0.2 || surface.BridgeAngle != -1 {
+		fmt.Println("Surfaces should have the layer thickness and no bridge angle", surface.Thickness, surface.BridgeAngle)
+		t.Fail()
+	}
+}
+
+func TestDiscoverVerticalShells(t *testing.T) {
+	layers := towerLayers()
+	slice.DetectSurfacesType(layers)
+	if err := slice.DiscoverVerticalShells(layers, 3, 3); err != nil {
+		fmt.Println("Shells should be discovered", err)
+		t.Fail()
+	}
+	if err := slice.BridgeOverInfill(layers); err != nil {
+		fmt.Println("Bridges over infill should be detected", err)
+		t.Fail()
+	}
+
+	tests := []struct {
+		layer int
+		st    slice.SurfaceType
+		area  float64
+	}{
+		{1, slice.StInternalSolid, 100},
+		{2, slice.StInternalSolid, 100},
+		{3, slice.StInternal, 100},
+		{8, slice.StInternal, 100},
+		{9, slice.StInternalBridge, 100},
+		{9, slice.StInternal, 0},
+		{10, slice.StInternalSolid, 100},
+		{10, slice.StBottomBridge, 300},
+	}
+	for _, test := range tests {
+		if area := surfaceArea(layers[test.layer], test.st); area != test.area {
+			fmt.Println("Wrong shell area", test.layer, test.st, area, test.area)
+			t.Fail()
+		}
+	}
+
+	// small sparse areas are filled solid
+	slice.SolidifySmallAreas(layers, slice.Scale(1)*slice.Scale(150))
+	if area := surfaceArea(layers[5], slice.StInternalSolid); area != 100 || !layers[5].Surfaces.First().IsSolid() {
+		fmt.Println("Small sparse areas should be solid", area)
+		t.Fail()
+	}
+}
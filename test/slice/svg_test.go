@@ -0,0 +1,68 @@
+package slice_test
+
+import (
+	"bytes"
+	"fmt"
+	"goSlicer/slice"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSVG(t *testing.T) {
+	svg := slice.NewSVG(nil)
+	svg.Margin = 0
+
+	pgx := squareWithHole()
+	pgx.Scale(1e4)
+	svg.DrawPolygonEx(pgx, "red")
+
+	pl := polyline(0, 0, 2e6, 2e6)
+	pl.Width = slice.Scale(0.4)
+	svg.DrawPolyline(pl, "blue")
+	svg.DrawText(slice.NewPoint(0, 0), "a < b", "black")
+
+	var buf bytes.Buffer
+	if _, err := svg.WriteTo(&buf); err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+	out := buf.String()
+
+	for _, expected := range []string{
+		`viewBox="0 -2 2 2"`,
+		`fill-rule: evenodd`,
+		`M 0,-0 L 1,-0 L 1,-1 L 0,-1 Z M 0.25,-0.75`,
+		`stroke-width: 0.4`,
+		`a &lt; b`,
+	} {
+		if !strings.Contains(out, expected) {
+			fmt.Printf("SVG is missing %s\n%s", expected, out)
+			t.Fail()
+		}
+	}
+}
+
+func TestSVGLayers(t *testing.T) {
+	layers := layerStack(3)
+	for i, layer := range layers {
+		pgx := squareWithHole()
+		pgx.Scale(float64(i+1) * 1e4)
+		layer.Slices.Push(pgx)
+	}
+
+	dir := t.TempDir()
+	if err := slice.SVGLayers(layers, dir); err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+
+	for i := 0; i < 3; i++ {
+		data, err := ioutil.ReadFile(filepath.Join(dir, fmt.Sprintf("layer-%04d.svg", i)))
+		if err != nil || !strings.Contains(string(data), `viewBox="-1 -4.5 5 5.5"`) {
+			fmt.Println("Layer SVGs should share the object viewBox", err)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package slice_test
+
+import (
+	"errors"
+	"fmt"
+	"goSlicer/slice"
+	"testing"
+)
+
+func squareWithHole() *slice.PolygonEx {
+	pgx := slice.NewPolygonEx()
+	pgx.Contour = square(0, 0, 100)
+	hole := square(25, 25, 50)
+	hole.MakeClockwise()
+	pgx.Holes.Push(hole)
+	return pgx
+}
+
+func TestWKTWrite(t *testing.T) {
+	tests := []struct {
+		geometry interface{}
+		expected string
+	}{
+		{slice.NewPoint(1.5, -2), "POINT (1.5 -2)"},
+		{slice.NewLine(slice.NewPoint(0, 0), slice.NewPoint(10, 5)), "LINESTRING (0 0, 10 5)"},
+		{polyline(0, 0, 10, 0, 10, 10), "LINESTRING (0 0, 10 0, 10 10)"},
+		{square(0, 0, 10), "POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0))"},
+		{squareWithHole(), "POLYGON ((0 0, 100 0, 100 100, 0 100, 0 0), (25 75, 75 75, 75 25, 25 25, 25 75))"},
+		{slice.Points{slice.NewPoint(1, 2), slice.NewPoint(3, 4)}, "MULTIPOINT ((1 2), (3 4))"},
+		{slice.Polygons{square(0, 0, 1), square(5, 5, 1)}, "MULTIPOLYGON (((0 0, 1 0, 1 1, 0 1, 0 0)), ((5 5, 6 5, 6 6, 5 6, 5 5)))"},
+		{slice.NewPolylines(), "MULTILINESTRING EMPTY"},
+		{[]interface{}{slice.NewPoint(1, 2), polyline(0, 0, 1, 1)}, "GEOMETRYCOLLECTION (POINT (1 2), LINESTRING (0 0, 1 1))"},
+	}
+
+	for _, test := range tests {
+		wkt, err := slice.WKT(test.geometry)
+		if err != nil || wkt != test.expected {
+			fmt.Printf("Expected %s, got %s %v\n", test.expected, wkt, err)
+			t.Fail()
+		}
+	}
+}
+
+func TestWKTRoundTrip(t *testing.T) {
+	inputs := []string{
+		"POINT (1.5 -2)",
+		"LINESTRING (0 0, 10 0, 10 10)",
+		"POLYGON ((0 0, 100 0, 100 100, 0 100, 0 0), (25 25, 25 75, 75 75, 75 25, 25 25))",
+		"MULTIPOINT ((1 2), (3 4))",
+		"MULTILINESTRING ((0 0, 1 1), (2 2, 3 3, 4 4))",
+		"MULTIPOLYGON (((0 0, 1 0, 1 1, 0 1, 0 0)), ((5 5, 6 5, 6 6, 5 6, 5 5)))",
+		"GEOMETRYCOLLECTION (POINT (1 2), POLYGON EMPTY)",
+	}
+
+	for _, input := range inputs {
+		geometry, err := slice.ParseWKT(input)
+		if err != nil {
+			fmt.Println(input, err)
+			t.Fail()
+			continue
+		}
+		wkt, err := slice.WKT(geometry)
+		if err != nil || wkt != input {
+			fmt.Printf("Round trip of %s gave %s %v\n", input, wkt, err)
+			t.Fail()
+		}
+	}
+
+	// loose input is accepted
+	geometry, err := slice.ParseWKT("multipoint z (1 2 3, 4 5 6)")
+	if points, ok := geometry.(slice.Points); err != nil || !ok || len(points) != 2 || !points[1].CoincidesWith(slice.NewPoint(4, 5)) {
+		fmt.Println("Lower case MULTIPOINT Z should parse", err)
+		t.Fail()
+	}
+
+	geometry, err = slice.ParseWKT(squareWithHole().WKT())
+	pgx, ok := geometry.(*slice.PolygonEx)
+	if err != nil || !ok || len(pgx.Contour.MP.Points) != 4 || len(pgx.Holes) != 1 || pgx.Area() != squareWithHole().Area() {
+		fmt.Println("PolygonEx should parse back with its hole", err)
+		t.Fail()
+	}
+
+	for _, bad := range []string{"", "POINT", "POINT (1)", "LINESTRING (0 0, 1 1", "CIRCLE (0 0)", "POINT (1 2) POINT (3 4)"} {
+		if _, err := slice.ParseWKT(bad); !errors.Is(err, slice.ErrInvalidWKT) {
+			fmt.Printf("%q should be invalid, got %v\n", bad, err)
+			t.Fail()
+		}
+	}
+}
+
+func TestGeoJSON(t *testing.T) {
+	data, err := slice.MarshalGeoJSON(squareWithHole())
+	expected := `{"type":"Polygon","coordinates":[[[0,0],[100,0],[100,100],[0,100],[0,0]],[[25,75],[75,75],[75,25],[25,25],[25,75]]]}`
+	if err != nil || string(data) != expected {
+		fmt.Println("Unexpected GeoJSON", string(data), err)
+		t.Fail()
+	}
+
+	geometries := []interface{}{
+		slice.NewPoint(1, 2),
+		polyline(0, 0, 10, 10),
+		squareWithHole(),
+		slice.Points{slice.NewPoint(1, 2)},
+		slice.Polylines{polyline(0, 0, 1, 1)},
+		slice.PolygonExs{squareWithHole(), squareWithHole()},
+	}
+	data, err = slice.MarshalGeoJSON(geometries)
+	if err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+	decoded, err := slice.UnmarshalGeoJSON(data)
+	if err != nil {
+		fmt.Println(err)
+		t.FailNow()
+	}
+	before, _ := slice.WKT(geometries)
+	after, _ := slice.WKT(decoded)
+	if before != after {
+		fmt.Printf("GeoJSON round trip changed\n%s\n%s\n", before, after)
+		t.Fail()
+	}
+
+	feature := `{"type":"FeatureCollection","features":[{"type":"Feature","properties":{"layer":3},"geometry":{"type":"Point","coordinates":[5,6,7]}}]}`
+	decoded, err = slice.UnmarshalGeoJSON([]byte(feature))
+	collection, ok := decoded.([]interface{})
+	if err != nil || !ok || len(collection) != 1 || !collection[0].(*slice.Point).CoincidesWith(slice.NewPoint(5, 6)) {
+		fmt.Println("FeatureCollection should decode to its point", err)
+		t.Fail()
+	}
+
+	if _, err := slice.UnmarshalGeoJSON([]byte(`{"type":"Point","coordinates":[1]}`)); !errors.Is(err, slice.ErrInvalidGeoJSON) {
+		fmt.Println("A single coordinate should be invalid", err)
+		t.Fail()
+	}
+}